@@ -29,8 +29,6 @@ func generateUniqueIdForJwt() string {
 	return uuidGen.URN()
 }
 
-// TODO Suppressing Notifications https://developer.box.com/reference#suppressing-notifications
-
 // APIConnRefreshNotifier is the interface that notifies the refresh result AccessToken/RefreshToken
 type APIConnRefreshNotifier interface {
 	Success(apiConn *APIConn)
@@ -52,11 +50,30 @@ type APIConn struct {
 	LastRefresh        time.Time
 	Expires            float64
 	MaxRequestAttempts int
-	rwLock             sync.RWMutex
-	notifier           APIConnRefreshNotifier
-	accessTokenLock    sync.RWMutex
-	RestrictedTo       []*FileScope `json:"restricted_to"`
-	jwtAuth            *JwtAuthClaim
+	// MaxPollingAttempts is the maximum number of requests to wait for a thumbnail or a representation to be generated.
+	// ErrNotReady is returned if it is not generated within the attempts. If 0, 10 attempts are made.
+	MaxPollingAttempts int
+	// SuppressNotifications sends "Box-Notifications: off" with every request made through this connection.
+	// This functionality required "Suppress notifications" permission.
+	// See https://developer.box.com/reference#suppressing-notifications
+	SuppressNotifications bool
+	// Transport is used to send the requests made through this connection (e.g. a Recorder or a Replayer).
+	// If nil, the shared default transport is used.
+	Transport http.RoundTripper
+	// RateLimiter limits the rate of the HTTP requests made through this connection (e.g. NewRateLimiter(10, 10)).
+	// If nil, requests are not limited.
+	RateLimiter RateLimiter
+	// CircuitBreaker fails requests fast during a sustained outage of Box (e.g. NewCircuitBreaker(5, 30*time.Second)).
+	// If nil, requests are always sent.
+	CircuitBreaker *CircuitBreaker
+	// Cache caches the responses of GetInfo calls (e.g. NewResponseCache(NewLRUCache(1000), 0)).
+	// If nil, responses are not cached.
+	Cache           *ResponseCache
+	rwLock          sync.RWMutex
+	notifier        APIConnRefreshNotifier
+	accessTokenLock sync.RWMutex
+	RestrictedTo    []*FileScope `json:"restricted_to"`
+	jwtAuth         *JwtAuthClaim
 }
 
 type JwtConfig struct {
@@ -159,13 +176,89 @@ type boxJwt struct {
 	jwt.RegisteredClaims
 }
 
+// Endpoints is the set of URLs used by APIConn.
+type Endpoints struct {
+	// Base URL of Box API, e.g. https://api.box.com/2.0/
+	BaseURL string
+	// Base URL of Box Upload API, e.g. https://upload.box.com/api/2.0/
+	BaseUploadURL string
+	TokenURL      string
+	RevokeURL     string
+	// URL of authorization page (OAuth2.0)
+	AuthorizationURL string
+}
+
+// DefaultEndpoints is the endpoints of Box (api.box.com, upload.box.com and account.box.com).
+var DefaultEndpoints = Endpoints{
+	BaseURL:          "https://api.box.com/2.0/",
+	BaseUploadURL:    "https://upload.box.com/api/2.0/",
+	TokenURL:         "https://api.box.com/oauth2/token",
+	RevokeURL:        "https://api.box.com/oauth2/revoke",
+	AuthorizationURL: "https://account.box.com/api/oauth2/authorize",
+}
+
+// NewEndpoints returns the endpoints served under single root URL (a local emulator, a reverse proxy, etc...).
+//
+// For example, NewEndpoints("http://localhost:8080") returns
+// BaseURL "http://localhost:8080/2.0/", BaseUploadURL "http://localhost:8080/api/2.0/",
+// TokenURL "http://localhost:8080/oauth2/token", RevokeURL "http://localhost:8080/oauth2/revoke" and
+// AuthorizationURL "http://localhost:8080/api/oauth2/authorize".
+func NewEndpoints(rootURL string) Endpoints {
+	root := strings.TrimSuffix(rootURL, "/")
+	return Endpoints{
+		BaseURL:          root + "/2.0/",
+		BaseUploadURL:    root + "/api/2.0/",
+		TokenURL:         root + "/oauth2/token",
+		RevokeURL:        root + "/oauth2/revoke",
+		AuthorizationURL: root + "/api/oauth2/authorize",
+	}
+}
+
+// Endpoints returns the endpoints currently used by this connection.
+func (ac *APIConn) Endpoints() Endpoints {
+	return Endpoints{
+		BaseURL:          ac.BaseURL,
+		BaseUploadURL:    ac.BaseUploadURL,
+		TokenURL:         ac.TokenURL,
+		RevokeURL:        ac.RevokeURL,
+		AuthorizationURL: ac.AuthorizationURL,
+	}
+}
+
+// SetEndpoints changes the endpoints used by this connection, and returns the connection itself.
+//
+// Empty fields of endpoints are left unchanged.
+// BaseURL and BaseUploadURL are completed with the trailing slash if missing.
+//
+//	apiConn := goboxer.NewAPIConnWithAccessToken(token).SetEndpoints(goboxer.NewEndpoints("http://localhost:8080"))
+func (ac *APIConn) SetEndpoints(endpoints Endpoints) *APIConn {
+	withSlash := func(u string) string {
+		if strings.HasSuffix(u, "/") {
+			return u
+		}
+		return u + "/"
+	}
+	if endpoints.BaseURL != "" {
+		ac.BaseURL = withSlash(endpoints.BaseURL)
+	}
+	if endpoints.BaseUploadURL != "" {
+		ac.BaseUploadURL = withSlash(endpoints.BaseUploadURL)
+	}
+	if endpoints.TokenURL != "" {
+		ac.TokenURL = endpoints.TokenURL
+	}
+	if endpoints.RevokeURL != "" {
+		ac.RevokeURL = endpoints.RevokeURL
+	}
+	if endpoints.AuthorizationURL != "" {
+		ac.AuthorizationURL = endpoints.AuthorizationURL
+	}
+	return ac
+}
+
 // Common Initialization
 func (ac *APIConn) commonInit() {
-	ac.TokenURL = "https://api.box.com/oauth2/token"
-	ac.RevokeURL = "https://api.box.com/oauth2/revoke"
-	ac.BaseURL = "https://api.box.com/2.0/"
-	ac.BaseUploadURL = "https://upload.box.com/api/2.0/"
-	ac.AuthorizationURL = "https://account.box.com/api/oauth2/authorize"
+	ac.SetEndpoints(DefaultEndpoints)
 	ac.UserAgent = fmt.Sprintf("goboxer/%s", VERSION)
 	ac.MaxRequestAttempts = 5
 }
@@ -175,6 +268,20 @@ func (ac *APIConn) SetAPIConnRefreshNotifier(notifier APIConnRefreshNotifier) {
 	ac.notifier = notifier
 }
 
+func (ac *APIConn) connInfo() *apiInfo {
+	return &apiInfo{api: ac}
+}
+
+// AsUser returns the connection that executes every request as specified user.
+//
+// Resources created from the returned connection (NewFile, NewFolder, ...) and the resources they return
+// send "As-User" header with all of their requests, including uploads and batch requests.
+// This functionality required "Perform actions as users" permission.
+// See https://developer.box.com/reference#as-user-1
+func (ac *APIConn) AsUser(userId string) Connection {
+	return &apiInfo{api: ac, asUser: userId}
+}
+
 // NewAPIConnWithAccessToken allocates and returns a new Box API connection from AccessToken.
 //
 // Instance created by this method can not refresh a AccessToken.
@@ -2,9 +2,11 @@ package goboxer
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -424,7 +426,7 @@ func TestApiConn_SaveStateAndRestore(t *testing.T) {
 			},
 			&APIConn{"CLIENT_ID", "CLIENT_SECRET", "ACCESS_TOKEN", "REFRESH_TOKEN",
 				"TOKEN_URL", "REVOKE_URL", "BASE_URL", "BASE_UPLOAD_URL",
-				"AUTHORIZATION_URL", "USER_AGENT", testTime, 3600.0, 10,
+				"AUTHORIZATION_URL", "USER_AGENT", testTime, 3600.0, 10, 0, false, nil, nil, nil, nil,
 				sync.RWMutex{}, nil, sync.RWMutex{},
 				nil, nil,
 			},
@@ -470,3 +472,95 @@ func TestApiConn_SaveStateAndRestore(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIConn_AsUser(t *testing.T) {
+	var asUsers []string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			asUsers = append(asUsers, r.URL.Path+" "+r.Header.Get(httpHeaderAsUser))
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/files/content"):
+				w.WriteHeader(http.StatusCreated)
+				resp, _ := ioutil.ReadFile("testdata/files/uploadfile_normal.json")
+				_, _ = w.Write(resp)
+			case strings.HasSuffix(r.URL.Path, "/batch"):
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{"responses":[{"status":200,"response":{"type":"file","id":"5000948880"}}]}`))
+			default:
+				w.WriteHeader(http.StatusOK)
+				resp, _ := ioutil.ReadFile("testdata/files/file_json.json")
+				_, _ = w.Write(resp)
+			}
+		},
+	))
+	defer ts.Close()
+
+	apiConn := commonInit(ts.URL)
+	conn := apiConn.AsUser("12345")
+
+	uploaded, err := NewFile(conn).UploadFile("a.txt", strings.NewReader("content"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile() error = %v", err)
+	}
+	// the returned resource keeps the scope
+	if _, err = uploaded.GetFileInfo("5000948880", false, nil); err != nil {
+		t.Fatalf("GetFileInfo() error = %v", err)
+	}
+	// unscoped connection is not affected
+	if _, err = NewFile(apiConn).GetFileInfo("5000948880", false, nil); err != nil {
+		t.Fatalf("GetFileInfo() error = %v", err)
+	}
+	// batch
+	if _, err = NewBatchRequest(conn).ExecuteBatch([]*Request{NewFile(conn).GetFileInfoReq("5000948880", false, nil)}); err != nil {
+		t.Fatalf("ExecuteBatch() error = %v", err)
+	}
+
+	want := []string{
+		"/api/2.0/files/content 12345",
+		"/2.0/files/5000948880 12345",
+		"/2.0/files/5000948880 ",
+		"/2.0/batch 12345",
+	}
+	if diff := cmp.Diff(asUsers, want); diff != "" {
+		t.Errorf("As-User diff: (-got +want)\n%s", diff)
+	}
+
+	req := NewFile(conn).GetFileInfoReq("5000948880", false, nil)
+	b, _ := req.MarshalJSON()
+	if !strings.Contains(string(b), `"As-User":"12345"`) {
+		t.Errorf("As-User is not included in batch sub request: %s", b)
+	}
+}
+
+func TestAPIConn_SetEndpoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints Endpoints
+		want      Endpoints
+	}{
+		{"default", Endpoints{}, DefaultEndpoints},
+		{"root", NewEndpoints("http://localhost:8080/"), Endpoints{
+			BaseURL:          "http://localhost:8080/2.0/",
+			BaseUploadURL:    "http://localhost:8080/api/2.0/",
+			TokenURL:         "http://localhost:8080/oauth2/token",
+			RevokeURL:        "http://localhost:8080/oauth2/revoke",
+			AuthorizationURL: "http://localhost:8080/api/oauth2/authorize",
+		}},
+		{"partial without trailing slash", Endpoints{BaseURL: "https://proxy.example.com/box/2.0"}, Endpoints{
+			BaseURL:          "https://proxy.example.com/box/2.0/",
+			BaseUploadURL:    DefaultEndpoints.BaseUploadURL,
+			TokenURL:         DefaultEndpoints.TokenURL,
+			RevokeURL:        DefaultEndpoints.RevokeURL,
+			AuthorizationURL: DefaultEndpoints.AuthorizationURL,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAPIConnWithAccessToken("ACCESS_TOKEN").SetEndpoints(tt.endpoints).Endpoints()
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Endpoints diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package goboxer
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// Typed results of batch sub requests
+//
+// Each XxxReq builder has the XxxBatch builder, which adds the request to the batch request
+// and returns the handle typed by its result.
+//
+//	batch := goboxer.NewBatchRequest(apiConn)
+//	folder := goboxer.NewFolder(apiConn).GetInfoBatch(batch, "0", nil)
+//	_, err := batch.Execute()
+//	f, err := folder.Result()
+//
+// Result also checks the "type" of the result, so it fails instead of decoding an unexpected resource.
+
+// pending is a sub request added to a batch request, and its response once the batch request is executed.
+type pending struct {
+	req  *Request
+	resp *Response
+}
+
+// Request returns the sub request.
+func (p *pending) Request() *Request {
+	return p.req
+}
+
+// Response returns the raw response of the sub request, or nil if the batch request is not executed yet.
+func (p *pending) Response() *Response {
+	return p.resp
+}
+
+// response returns the response of the sub request, or *ApiStatusError if the sub request failed.
+func (p *pending) response() (*Response, error) {
+	if p.resp == nil {
+		return nil, newApiOtherError(xerrors.New("batch request is not executed yet"), "")
+	}
+	if p.resp.ResponseCode < 200 || p.resp.ResponseCode >= 300 {
+		return nil, newApiStatusError(p.resp)
+	}
+	return p.resp, nil
+}
+
+// decode unmarshals the result of the sub request into v. The type of the result must be resourceType.
+func (p *pending) decode(resourceType string, v interface{}) error {
+	resp, err := p.response()
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Type string `json:"type"`
+	}
+	if err = UnmarshalJSONWrapper(resp.Body, &result); err != nil {
+		return err
+	}
+	if result.Type != resourceType {
+		return p.unexpected(result.Type, resourceType)
+	}
+	return UnmarshalJSONWrapper(resp.Body, v)
+}
+
+// decodeEntries unmarshals the result of the sub request, a collection of resourceType, into v.
+func (p *pending) decodeEntries(resourceType string, v interface{}) error {
+	resp, err := p.response()
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Type    string `json:"type"`
+		Entries *[]struct {
+			Type string `json:"type"`
+		} `json:"entries"`
+	}
+	if err = UnmarshalJSONWrapper(resp.Body, &result); err != nil {
+		return err
+	}
+	if result.Entries == nil {
+		return p.unexpected(result.Type, "collection of "+resourceType)
+	}
+	for _, entry := range *result.Entries {
+		if entry.Type != resourceType {
+			return p.unexpected("collection of "+entry.Type, "collection of "+resourceType)
+		}
+	}
+	return UnmarshalJSONWrapper(resp.Body, v)
+}
+
+func (p *pending) unexpected(got string, want string) error {
+	err := xerrors.Errorf("result of the sub request is %q, not %q: %s %s", got, want, convertMethodStr(p.req.Method), p.req.Url)
+	return newApiOtherError(err, "")
+}
+
+// apiInfo returns the connection of the sub request, which is set to the decoded resources.
+func (p *pending) apiInfo() *apiInfo {
+	return &apiInfo{api: p.req.apiConn, asUser: p.req.headers.Get(httpHeaderAsUser)}
+}
+
+func (req *BatchRequest) add(r *Request) *pending {
+	p := &pending{req: r}
+	req.pending = append(req.pending, p)
+	return p
+}
+
+// Execute the sub requests added by XxxBatch.
+//
+// Results of the sub requests are available from the returned pending handles.
+// If the batch request fails, the handles of the sub requests already executed still have their results.
+// The added sub requests are cleared, so the BatchRequest can be reused.
+func (req *BatchRequest) Execute() (*BatchResponse, error) {
+	requests := make([]*Request, len(req.pending))
+	for i, p := range req.pending {
+		requests[i] = p.req
+	}
+	resp, err := req.ExecuteBatch(requests)
+	for i, p := range req.pending {
+		p.resp = resp.Responses[i]
+	}
+	req.pending = nil
+	return resp, err
+}
+
+// PendingFolder is a sub request of a batch request whose result is a folder.
+// (Folder.GetInfoBatch, Folder.CreateBatch, Folder.UpdateBatch, Folder.CopyBatch, Folder.RestoreBatch)
+type PendingFolder struct {
+	*pending
+}
+
+// addFolder adds the sub request whose result is a folder.
+func (req *BatchRequest) addFolder(r *Request) *PendingFolder {
+	return &PendingFolder{req.add(r)}
+}
+
+// Result returns the decoded folder, or the error of the sub request.
+func (p *PendingFolder) Result() (*Folder, error) {
+	folder := &Folder{}
+	if err := p.decode("folder", folder); err != nil {
+		return nil, err
+	}
+	info := p.apiInfo()
+	if folder.ItemCollection != nil {
+		for _, v := range folder.ItemCollection.Entries {
+			setApiInfo(v, info)
+		}
+	}
+	folder.apiInfo = info
+	return folder, nil
+}
+
+// PendingFile is a sub request of a batch request whose result is a file.
+// (File.GetFileInfoBatch, File.UpdateBatch, File.CopyBatch, File.LockFileBatch, File.UnlockFileBatch, File.RestoreBatch)
+type PendingFile struct {
+	*pending
+}
+
+// addFile adds the sub request whose result is a file.
+func (req *BatchRequest) addFile(r *Request) *PendingFile {
+	return &PendingFile{req.add(r)}
+}
+
+// Result returns the decoded file, or the error of the sub request.
+func (p *PendingFile) Result() (*File, error) {
+	file := &File{apiInfo: p.apiInfo()}
+	if err := p.decode("file", file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// PendingCollaboration is a sub request of a batch request whose result is a collaboration.
+// (Collaboration.GetInfoBatch, Collaboration.CreateBatch, Collaboration.UpdateBatch)
+type PendingCollaboration struct {
+	*pending
+}
+
+// addCollaboration adds the sub request whose result is a collaboration.
+func (req *BatchRequest) addCollaboration(r *Request) *PendingCollaboration {
+	return &PendingCollaboration{req.add(r)}
+}
+
+// Result returns the decoded collaboration, or the error of the sub request.
+func (p *PendingCollaboration) Result() (*Collaboration, error) {
+	collab := &Collaboration{apiInfo: p.apiInfo()}
+	if err := p.decode("collaboration", collab); err != nil {
+		return nil, err
+	}
+	return collab, nil
+}
+
+// PendingCollaborations is a sub request of a batch request whose result is a list of collaborations.
+// (Folder.CollaborationsBatch, File.CollaborationsBatch, Collaboration.PendingCollaborationsBatch)
+type PendingCollaborations struct {
+	*pending
+}
+
+// addCollaborations adds the sub request whose result is a list of collaborations.
+func (req *BatchRequest) addCollaborations(r *Request) *PendingCollaborations {
+	return &PendingCollaborations{req.add(r)}
+}
+
+// Result returns the decoded collaborations, or the error of the sub request.
+// Paging information (total_count, next_marker...) of the list is not returned.
+func (p *PendingCollaborations) Result() ([]*Collaboration, error) {
+	collabs := struct {
+		Entries []*Collaboration `json:"entries"`
+	}{}
+	if err := p.decodeEntries("collaboration", &collabs); err != nil {
+		return nil, err
+	}
+	info := p.apiInfo()
+	for _, collab := range collabs.Entries {
+		collab.apiInfo = info
+	}
+	return collabs.Entries, nil
+}
+
+// PendingUser is a sub request of a batch request whose result is a user.
+// (User.GetUserBatch, User.CreateUserBatch, User.UpdateUserBatch)
+type PendingUser struct {
+	*pending
+}
+
+// addUser adds the sub request whose result is a user.
+func (req *BatchRequest) addUser(r *Request) *PendingUser {
+	return &PendingUser{req.add(r)}
+}
+
+// Result returns the decoded user, or the error of the sub request.
+func (p *PendingUser) Result() (*User, error) {
+	user := &User{apiInfo: p.apiInfo()}
+	if err := p.decode("user", user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// PendingGroup is a sub request of a batch request whose result is a group.
+// (Group.GetGroupBatch, Group.CreateGroupBatch, Group.UpdateGroupBatch)
+type PendingGroup struct {
+	*pending
+}
+
+// addGroup adds the sub request whose result is a group.
+func (req *BatchRequest) addGroup(r *Request) *PendingGroup {
+	return &PendingGroup{req.add(r)}
+}
+
+// Result returns the decoded group, or the error of the sub request.
+func (p *PendingGroup) Result() (*Group, error) {
+	group := &Group{apiInfo: p.apiInfo()}
+	if err := p.decode("group", group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// PendingMembership is a sub request of a batch request whose result is a group membership.
+// (Membership.GetMembershipBatch, Membership.CreateMembershipBatch, Membership.UpdateMembershipBatch)
+type PendingMembership struct {
+	*pending
+}
+
+// addMembership adds the sub request whose result is a group membership.
+func (req *BatchRequest) addMembership(r *Request) *PendingMembership {
+	return &PendingMembership{req.add(r)}
+}
+
+// Result returns the decoded membership, or the error of the sub request.
+func (p *PendingMembership) Result() (*Membership, error) {
+	membership := &Membership{apiInfo: p.apiInfo()}
+	if err := p.decode("group_membership", membership); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// PendingComment is a sub request of a batch request whose result is a comment.
+// (Comment.GetInfoBatch, Comment.CreateBatch, Comment.UpdateBatch)
+type PendingComment struct {
+	*pending
+}
+
+// addComment adds the sub request whose result is a comment.
+func (req *BatchRequest) addComment(r *Request) *PendingComment {
+	return &PendingComment{req.add(r)}
+}
+
+// Result returns the decoded comment, or the error of the sub request.
+func (p *PendingComment) Result() (*Comment, error) {
+	comment := &Comment{apiInfo: p.apiInfo()}
+	if err := p.decode("comment", comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// PendingTask is a sub request of a batch request whose result is a task.
+// (Task.GetInfoBatch, Task.CreateBatch, Task.UpdateBatch)
+type PendingTask struct {
+	*pending
+}
+
+// addTask adds the sub request whose result is a task.
+func (req *BatchRequest) addTask(r *Request) *PendingTask {
+	return &PendingTask{req.add(r)}
+}
+
+// Result returns the decoded task, or the error of the sub request.
+func (p *PendingTask) Result() (*Task, error) {
+	task := &Task{apiInfo: p.apiInfo()}
+	if err := p.decode("task", task); err != nil {
+		return nil, err
+	}
+	task.setApiInfoToAssignments()
+	return task, nil
+}
+
+// PendingTaskAssignment is a sub request of a batch request whose result is a task assignment.
+// (TaskAssignment.GetInfoBatch, TaskAssignment.CreateBatch, TaskAssignment.UpdateBatch)
+type PendingTaskAssignment struct {
+	*pending
+}
+
+// addTaskAssignment adds the sub request whose result is a task assignment.
+func (req *BatchRequest) addTaskAssignment(r *Request) *PendingTaskAssignment {
+	return &PendingTaskAssignment{req.add(r)}
+}
+
+// Result returns the decoded task assignment, or the error of the sub request.
+func (p *PendingTaskAssignment) Result() (*TaskAssignment, error) {
+	assignment := &TaskAssignment{apiInfo: p.apiInfo()}
+	if err := p.decode("task_assignment", assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// PendingNoContent is a sub request of a batch request without result.
+// (Folder.DeleteBatch, File.DeleteBatch, Collaboration.DeleteBatch, User.DeleteUserBatch, Group.DeleteGroupBatch,
+// Membership.DeleteMembershipBatch, Comment.DeleteBatch, Task.DeleteBatch, TaskAssignment.DeleteBatch)
+type PendingNoContent struct {
+	*pending
+}
+
+// addNoContent adds the sub request without result.
+func (req *BatchRequest) addNoContent(r *Request) *PendingNoContent {
+	return &PendingNoContent{req.add(r)}
+}
+
+// Result returns the error of the sub request.
+func (p *PendingNoContent) Result() error {
+	_, err := p.response()
+	return err
+}
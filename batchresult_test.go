@@ -0,0 +1,130 @@
+package goboxer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestBatchRequest_Execute(t *testing.T) {
+	folderJson, _ := ioutil.ReadFile("testdata/folders/getinfo_normal.json")
+	fileJson, _ := ioutil.ReadFile("testdata/files/file_json.json")
+	collabsJson, _ := ioutil.ReadFile("testdata/folders/collaborations_normal.json")
+	notFoundJson, _ := ioutil.ReadFile("testdata/genericerror/404.json")
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Requests []struct {
+					Method      string `json:"method"`
+					RelativeURL string `json:"relative_url"`
+				} `json:"requests"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("invalid batch request: %v", err)
+			}
+			var responses []map[string]interface{}
+			for _, sub := range body.Requests {
+				switch sub.Method + " " + sub.RelativeURL {
+				case "POST /folders":
+					responses = append(responses, map[string]interface{}{"status": 201, "response": json.RawMessage(folderJson)})
+				case "GET /files/5000948880":
+					responses = append(responses, map[string]interface{}{"status": 200, "response": json.RawMessage(fileJson)})
+				case "GET /folders/10000/collaborations":
+					responses = append(responses, map[string]interface{}{"status": 200, "response": json.RawMessage(collabsJson)})
+				case "DELETE /files/5000948880":
+					responses = append(responses, map[string]interface{}{"status": 204, "response": nil})
+				case "GET /files/20000", "GET /folders/20000/collaborations":
+					// unexpected results
+					responses = append(responses, map[string]interface{}{"status": 200, "response": json.RawMessage(folderJson)})
+				default:
+					responses = append(responses, map[string]interface{}{"status": 404, "response": json.RawMessage(notFoundJson)})
+				}
+			}
+			w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+		},
+	))
+	defer ts.Close()
+
+	apiConn := commonInit(ts.URL)
+	conn := apiConn.AsUser("12345")
+
+	batch := NewBatchRequest(apiConn)
+	created := NewFolder(conn).CreateBatch(batch, "0", "Pictures", nil)
+	file := NewFile(apiConn).GetFileInfoBatch(batch, "5000948880", false, nil)
+	collabs := NewFolder(apiConn).CollaborationsBatch(batch, "10000", nil)
+	deleted := NewFile(apiConn).DeleteBatch(batch, "5000948880", "")
+	notFound := NewUser(apiConn).GetUserBatch(batch, "404", nil)
+	wrongType := NewFile(apiConn).GetFileInfoBatch(batch, "20000", false, nil)
+	wrongEntries := NewFolder(apiConn).CollaborationsBatch(batch, "20000", nil)
+
+	if _, err := created.Result(); err == nil {
+		t.Errorf("Result() before Execute() must fail")
+	}
+
+	resp, err := batch.Execute()
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(resp.Responses) != 7 {
+		t.Errorf("len(Responses) = %d, want 7", len(resp.Responses))
+	}
+
+	folder, err := created.Result()
+	if err != nil {
+		t.Fatalf("PendingFolder.Result() error = %v", err)
+	}
+	if *folder.ID != "10000" || folder.apiInfo.asUser != "12345" {
+		t.Errorf("unexpected folder: %s as user %q", *folder.ID, folder.apiInfo.asUser)
+	}
+	if created.Response().ResponseCode != http.StatusCreated {
+		t.Errorf("Response().ResponseCode = %d", created.Response().ResponseCode)
+	}
+
+	f, err := file.Result()
+	if err != nil {
+		t.Fatalf("PendingFile.Result() error = %v", err)
+	}
+	if *f.ID != "5000948880" || f.apiInfo.asUser != "" {
+		t.Errorf("unexpected file: %s as user %q", *f.ID, f.apiInfo.asUser)
+	}
+
+	c, err := collabs.Result()
+	if err != nil {
+		t.Fatalf("PendingCollaborations.Result() error = %v", err)
+	}
+	if len(c) == 0 || c[0].apiInfo == nil {
+		t.Errorf("unexpected collaborations: %v", c)
+	}
+
+	if err := deleted.Result(); err != nil {
+		t.Errorf("PendingNoContent.Result() error = %v", err)
+	}
+
+	_, err = notFound.Result()
+	if !xerrors.Is(err, ErrNotFound) {
+		t.Errorf("PendingUser.Result() error = %v, want ErrNotFound", err)
+	}
+	var apiErr *ApiStatusError
+	if !xerrors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("PendingUser.Result() error = %v, want *ApiStatusError", err)
+	}
+
+	// unexpected results are not decoded
+	if got, err := wrongType.Result(); err == nil {
+		t.Errorf("PendingFile.Result() of a folder = %v, want error", got)
+	}
+	if got, err := wrongEntries.Result(); err == nil {
+		t.Errorf("PendingCollaborations.Result() of a folder = %v, want error", got)
+	}
+
+	// added sub requests are cleared
+	if resp, err := batch.Execute(); err != nil || len(resp.Responses) != 0 {
+		t.Errorf("Execute() without sub requests = %v, %v, want no responses", resp, err)
+	}
+}
@@ -0,0 +1,78 @@
+package boxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+)
+
+// maxBatchRequests is the maximum number of sub requests in a batch request.
+const maxBatchRequests = 20
+
+func (s *Server) batch(c *call) {
+	var body struct {
+		Requests []struct {
+			Method      string            `json:"method"`
+			RelativeURL string            `json:"relative_url"`
+			Body        json.RawMessage   `json:"body"`
+			Headers     map[string]string `json:"headers"`
+		} `json:"requests"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if len(body.Requests) == 0 {
+		badRequest(c, "'requests' is required")
+		return
+	}
+	if len(body.Requests) > maxBatchRequests {
+		badRequest(c, "Too many requests in a batch request")
+		return
+	}
+
+	var responses []interface{}
+	for _, sub := range body.Requests {
+		var reqBody []byte
+		if len(sub.Body) != 0 && string(sub.Body) != "null" {
+			reqBody = sub.Body
+		}
+		r, err := http.NewRequest(sub.Method, s.URL+"/2.0"+sub.RelativeURL, bytes.NewReader(reqBody))
+		if err != nil {
+			responses = append(responses, map[string]interface{}{
+				"status":   http.StatusBadRequest,
+				"headers":  map[string]string{},
+				"response": nil,
+			})
+			continue
+		}
+		for k, v := range sub.Headers {
+			r.Header.Set(k, v)
+		}
+		r.Header.Set("Authorization", c.r.Header.Get("Authorization"))
+		if r.Header.Get("As-User") == "" && c.r.Header.Get("As-User") != "" {
+			r.Header.Set("As-User", c.r.Header.Get("As-User"))
+		}
+		if len(reqBody) != 0 {
+			r.Header.Set("Content-Type", "application/json")
+		}
+
+		rec := httptest.NewRecorder()
+		s.serve(rec, r)
+
+		headers := map[string]string{}
+		for k := range rec.Header() {
+			headers[k] = rec.Header().Get(k)
+		}
+		var response interface{}
+		if b := rec.Body.Bytes(); len(b) != 0 && json.Valid(b) {
+			response = json.RawMessage(b)
+		}
+		responses = append(responses, map[string]interface{}{
+			"status":   rec.Code,
+			"headers":  headers,
+			"response": response,
+		})
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{"responses": responses})
+}
@@ -0,0 +1,754 @@
+// Package boxtest provides an in-memory, stateful emulator of the Box API endpoints supported by goboxer.
+//
+// The emulator keeps files (and their versions, comments and tasks), folders, watermarks, metadata (with templates and cascade policies), users, groups, memberships, collaborations and events in memory,
+// and implements the semantics goboxer relies on: etags (If-Match / If-None-Match), 409 name conflicts,
+// offset based pagination, search, the trash, the batch endpoint and the OAuth2 token endpoint.
+// Faults (429, 5xx...) can be injected to exercise retry logic.
+//
+//	srv := boxtest.NewServer()
+//	defer srv.Close()
+//
+//	apiConn := srv.NewAPIConn()
+//	folder, err := goboxer.NewFolder(apiConn).Create("0", "new folder", nil)
+//
+// Access control is not emulated: every authenticated user can read and write every item.
+// The "fields" query parameter is ignored and full representations are always returned.
+package boxtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jparound30/goboxer"
+)
+
+// Server is an in-memory Box API emulator.
+type Server struct {
+	// URL is the root URL of the emulator. Use goboxer.NewEndpoints(URL) to point APIConn to the emulator.
+	URL string
+
+	// Credentials accepted by the token endpoint.
+	ClientID     string
+	ClientSecret string
+
+	// Initial tokens of the admin user.
+	AccessToken  string
+	RefreshToken string
+
+	// AdminUserID is the id of the user that owns the initial tokens and the root folder.
+	AdminUserID string
+
+	// EnterpriseID is the id of the enterprise, used in the enterprise scope of metadata. (e.g. "enterprise_12345")
+	EnterpriseID string
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	lastID   int64
+	requests []string
+	faults   []*Fault
+
+	accessTokens  map[string]string // access token -> user id
+	refreshTokens map[string]string // refresh token -> user id
+
+	items          map[string]*item
+	users          map[string]record
+	userOrder      []string
+	groups         map[string]record
+	groupOrder     []string
+	memberships    map[string]record
+	membershipOrd  []string
+	collaborations map[string]record
+	collabOrder    []string
+	comments       map[string]*comment
+	commentOrder   []string
+	tasks          map[string]*task
+	taskOrder      []string
+	assignments    map[string]*taskAssignment
+	templates      map[string]*metadataTemplate
+	templateOrder  []string
+	policies       map[string]record
+	policyOrder    []string
+	events         []*event // user events
+	adminEvents    []*event // enterprise events
+}
+
+// NewServer starts and returns a new emulator.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		ClientID:       "CLIENT_ID",
+		ClientSecret:   "CLIENT_SECRET",
+		EnterpriseID:   "12345",
+		accessTokens:   map[string]string{},
+		refreshTokens:  map[string]string{},
+		items:          map[string]*item{},
+		users:          map[string]record{},
+		groups:         map[string]record{},
+		memberships:    map[string]record{},
+		collaborations: map[string]record{},
+		comments:       map[string]*comment{},
+		tasks:          map[string]*task{},
+		assignments:    map[string]*taskAssignment{},
+		templates:      map[string]*metadataTemplate{},
+		policies:       map[string]record{},
+	}
+	admin := s.newUser(record{"name": "Admin", "login": "admin@example.com", "role": "admin"})
+	s.AdminUserID = admin.id()
+	s.AccessToken, s.RefreshToken = s.issueTokens(s.AdminUserID)
+
+	now := s.now()
+	s.items["0"] = &item{typ: "folder", id: "0", name: "All Files", createdAt: now, modifiedAt: now, ownerID: s.AdminUserID, createdByID: s.AdminUserID, modifiedByID: s.AdminUserID}
+
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the emulator.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Endpoints returns the endpoints of this emulator.
+func (s *Server) Endpoints() goboxer.Endpoints {
+	return goboxer.NewEndpoints(s.URL)
+}
+
+// NewAPIConn returns a new connection to this emulator authenticated as the admin user.
+func (s *Server) NewAPIConn() *goboxer.APIConn {
+	apiConn := goboxer.NewAPIConnWithRefreshToken(s.ClientID, s.ClientSecret, s.AccessToken, s.RefreshToken)
+	apiConn.LastRefresh = time.Now()
+	apiConn.Expires = 3600
+	return apiConn.SetEndpoints(s.Endpoints())
+}
+
+// Requests returns the requests received by the emulator in "METHOD /path" form.
+// The path is relative to the API root (e.g. "GET /folders/0"). Sub requests of a batch request are included.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := make([]string, len(s.requests))
+	copy(r, s.requests)
+	return r
+}
+
+// Fault makes the emulator fail matching requests instead of handling them.
+type Fault struct {
+	// Method to match. Empty matches any method.
+	Method string
+	// Path relative to the API root (e.g. "/folders/0/items") to match.
+	// Empty matches any path, a trailing "*" matches by prefix.
+	Path string
+	// StatusCode returned instead of handling the request (429, 500, 503...).
+	StatusCode int
+	// RetryAfter is set as "Retry-After" header (in seconds). It is always set for 429 as Box does, otherwise only if positive.
+	RetryAfter int
+	// Times is the number of matching requests to fail. Zero or less means once.
+	Times int
+}
+
+// InjectFault registers a fault. Faults are matched in registration order.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.Times <= 0 {
+		f.Times = 1
+	}
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all registered faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+func (s *Server) matchFault(method string, path string) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if f.Path != "" {
+			if strings.HasSuffix(f.Path, "*") {
+				if !strings.HasPrefix(path, strings.TrimSuffix(f.Path, "*")) {
+					continue
+				}
+			} else if f.Path != path {
+				continue
+			}
+		}
+		f.Times--
+		if f.Times <= 0 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler, so the emulator can also be mounted on another server.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serve(w, r)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch path {
+	case "/oauth2/token":
+		s.requests = append(s.requests, r.Method+" "+path)
+		s.token(w, r)
+		return
+	case "/oauth2/revoke":
+		s.requests = append(s.requests, r.Method+" "+path)
+		s.revoke(w, r)
+		return
+	}
+
+	var apiPath string
+	switch {
+	case strings.HasPrefix(path, "/2.0/"):
+		apiPath = strings.TrimPrefix(path, "/2.0")
+	case strings.HasPrefix(path, "/api/2.0/"):
+		apiPath = strings.TrimPrefix(path, "/api/2.0")
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "Not Found", nil)
+		return
+	}
+	s.requests = append(s.requests, r.Method+" "+apiPath)
+
+	if f := s.matchFault(r.Method, apiPath); f != nil {
+		if f.RetryAfter > 0 || f.StatusCode == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", strconv.Itoa(f.RetryAfter))
+		}
+		code := "internal_server_error"
+		if f.StatusCode == http.StatusTooManyRequests {
+			code = "rate_limit_exceeded"
+		}
+		writeError(w, f.StatusCode, code, "Injected fault", nil)
+		return
+	}
+
+	userID, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	c := &call{w: w, r: r, userID: userID, query: r.URL.Query()}
+	s.dispatch(c, apiPath)
+}
+
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	userID, ok := s.accessTokens[strings.TrimPrefix(auth, "Bearer ")]
+	if !strings.HasPrefix(auth, "Bearer ") || !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="Service", error="invalid_token", error_description="The access token provided is invalid."`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return "", false
+	}
+	if asUser := r.Header.Get("As-User"); asUser != "" {
+		if _, ok := s.users[asUser]; !ok {
+			writeError(w, http.StatusForbidden, "access_denied_insufficient_permissions", "Access denied - insufficient permission", nil)
+			return "", false
+		}
+		userID = asUser
+	}
+	return userID, true
+}
+
+// call is a request being handled.
+type call struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	userID string
+	query  url.Values
+	// path parameters (":id", ":sub" and ":key", e.g. the version id of "/files/:id/versions/:sub")
+	id    string
+	subID string
+	key   string
+}
+
+// itemType returns "file" for the requests to /files/..., otherwise "folder".
+func (c *call) itemType() string {
+	if strings.HasPrefix(strings.TrimPrefix(strings.TrimPrefix(c.r.URL.Path, "/api"), "/2.0"), "/files/") {
+		return "file"
+	}
+	return "folder"
+}
+
+func (c *call) decodeBody(v interface{}) bool {
+	if err := json.NewDecoder(c.r.Body).Decode(v); err != nil {
+		writeError(c.w, http.StatusBadRequest, "bad_request", "Invalid JSON body: "+err.Error(), nil)
+		return false
+	}
+	return true
+}
+
+type route struct {
+	method  string
+	pattern string
+	handle  func(s *Server, c *call)
+}
+
+// routes are matched in order, so static segments must precede ":id".
+var routes []route
+
+// routes refer to the batch handler that dispatches by routes, so they are initialized in init.
+func init() {
+	routes = []route{
+		{http.MethodPost, "/files/content", (*Server).uploadFile},
+		{http.MethodOptions, "/files/content", (*Server).preflightCheck},
+		{http.MethodGet, "/files/:id", (*Server).getFile},
+		{http.MethodPut, "/files/:id", (*Server).updateFile},
+		{http.MethodDelete, "/files/:id", (*Server).deleteFile},
+		{http.MethodGet, "/files/:id/content", (*Server).downloadFile},
+		{http.MethodPost, "/files/:id/content", (*Server).uploadFileVersion},
+		{http.MethodPost, "/files/:id/copy", (*Server).copyFile},
+		{http.MethodGet, "/files/:id/collaborations", (*Server).itemCollaborations},
+		{http.MethodGet, "/files/:id/comments", (*Server).fileComments},
+		{http.MethodGet, "/files/:id/tasks", (*Server).fileTasks},
+		{http.MethodGet, "/files/:id/metadata", (*Server).allMetadata},
+		{http.MethodGet, "/files/:id/metadata/:sub/:key", (*Server).getMetadata},
+		{http.MethodPost, "/files/:id/metadata/:sub/:key", (*Server).createMetadata},
+		{http.MethodPut, "/files/:id/metadata/:sub/:key", (*Server).updateMetadata},
+		{http.MethodDelete, "/files/:id/metadata/:sub/:key", (*Server).deleteMetadata},
+		{http.MethodGet, "/files/:id/watermark", (*Server).getWatermark},
+		{http.MethodPut, "/files/:id/watermark", (*Server).applyWatermark},
+		{http.MethodDelete, "/files/:id/watermark", (*Server).removeWatermark},
+		{http.MethodPost, "/files/:id", (*Server).restoreItem},
+		{http.MethodGet, "/files/:id/trash", (*Server).getTrashedItem},
+		{http.MethodDelete, "/files/:id/trash", (*Server).purgeItem},
+		{http.MethodGet, "/files/:id/versions", (*Server).listVersions},
+		{http.MethodPost, "/files/:id/versions/current", (*Server).promoteVersion},
+		{http.MethodGet, "/files/:id/versions/:sub", (*Server).getVersion},
+		{http.MethodPut, "/files/:id/versions/:sub", (*Server).restoreVersion},
+		{http.MethodDelete, "/files/:id/versions/:sub", (*Server).deleteVersion},
+
+		{http.MethodPost, "/folders", (*Server).createFolder},
+		{http.MethodGet, "/folders/trash/items", (*Server).trashedItems},
+		{http.MethodGet, "/folders/:id", (*Server).getFolder},
+		{http.MethodPut, "/folders/:id", (*Server).updateFolder},
+		{http.MethodDelete, "/folders/:id", (*Server).deleteFolder},
+		{http.MethodGet, "/folders/:id/items", (*Server).folderItems},
+		{http.MethodPost, "/folders/:id/copy", (*Server).copyFolder},
+		{http.MethodGet, "/folders/:id/collaborations", (*Server).itemCollaborations},
+		{http.MethodGet, "/folders/:id/metadata", (*Server).allMetadata},
+		{http.MethodGet, "/folders/:id/metadata/:sub/:key", (*Server).getMetadata},
+		{http.MethodPost, "/folders/:id/metadata/:sub/:key", (*Server).createMetadata},
+		{http.MethodPut, "/folders/:id/metadata/:sub/:key", (*Server).updateMetadata},
+		{http.MethodDelete, "/folders/:id/metadata/:sub/:key", (*Server).deleteMetadata},
+		{http.MethodGet, "/folders/:id/watermark", (*Server).getWatermark},
+		{http.MethodPut, "/folders/:id/watermark", (*Server).applyWatermark},
+		{http.MethodDelete, "/folders/:id/watermark", (*Server).removeWatermark},
+		{http.MethodPost, "/folders/:id", (*Server).restoreItem},
+		{http.MethodGet, "/folders/:id/trash", (*Server).getTrashedItem},
+		{http.MethodDelete, "/folders/:id/trash", (*Server).purgeItem},
+
+		{http.MethodGet, "/users", (*Server).listUsers},
+		{http.MethodPost, "/users", (*Server).createUser},
+		{http.MethodGet, "/users/me", (*Server).getCurrentUser},
+		{http.MethodGet, "/users/:id", (*Server).getUser},
+		{http.MethodPut, "/users/:id", (*Server).updateUser},
+		{http.MethodDelete, "/users/:id", (*Server).deleteUser},
+		{http.MethodGet, "/users/:id/memberships", (*Server).userMemberships},
+
+		{http.MethodGet, "/groups", (*Server).listGroups},
+		{http.MethodPost, "/groups", (*Server).createGroup},
+		{http.MethodGet, "/groups/:id", (*Server).getGroup},
+		{http.MethodPut, "/groups/:id", (*Server).updateGroup},
+		{http.MethodDelete, "/groups/:id", (*Server).deleteGroup},
+		{http.MethodGet, "/groups/:id/memberships", (*Server).groupMemberships},
+		{http.MethodGet, "/groups/:id/collaborations", (*Server).groupCollaborations},
+
+		{http.MethodPost, "/group_memberships", (*Server).createMembership},
+		{http.MethodGet, "/group_memberships/:id", (*Server).getMembership},
+		{http.MethodPut, "/group_memberships/:id", (*Server).updateMembership},
+		{http.MethodDelete, "/group_memberships/:id", (*Server).deleteMembership},
+
+		{http.MethodGet, "/collaborations", (*Server).pendingCollaborations},
+		{http.MethodPost, "/collaborations", (*Server).createCollaboration},
+		{http.MethodGet, "/collaborations/:id", (*Server).getCollaboration},
+		{http.MethodPut, "/collaborations/:id", (*Server).updateCollaboration},
+		{http.MethodDelete, "/collaborations/:id", (*Server).deleteCollaboration},
+
+		{http.MethodPost, "/comments", (*Server).createComment},
+		{http.MethodGet, "/comments/:id", (*Server).getComment},
+		{http.MethodPut, "/comments/:id", (*Server).updateComment},
+		{http.MethodDelete, "/comments/:id", (*Server).deleteComment},
+
+		{http.MethodPost, "/tasks", (*Server).createTask},
+		{http.MethodGet, "/tasks/:id", (*Server).getTask},
+		{http.MethodPut, "/tasks/:id", (*Server).updateTask},
+		{http.MethodDelete, "/tasks/:id", (*Server).deleteTask},
+		{http.MethodGet, "/tasks/:id/assignments", (*Server).taskAssignmentList},
+
+		{http.MethodPost, "/task_assignments", (*Server).createTaskAssignment},
+		{http.MethodGet, "/task_assignments/:id", (*Server).getTaskAssignment},
+		{http.MethodPut, "/task_assignments/:id", (*Server).updateTaskAssignment},
+		{http.MethodDelete, "/task_assignments/:id", (*Server).deleteTaskAssignment},
+
+		{http.MethodGet, "/metadata_templates/enterprise", (*Server).enterpriseTemplates},
+		{http.MethodPost, "/metadata_templates/schema", (*Server).createTemplate},
+		{http.MethodGet, "/metadata_templates/:id", (*Server).getTemplateByID},
+		{http.MethodGet, "/metadata_templates/:sub/:key/schema", (*Server).getTemplate},
+		{http.MethodPut, "/metadata_templates/:sub/:key/schema", (*Server).updateTemplate},
+		{http.MethodDelete, "/metadata_templates/:sub/:key/schema", (*Server).deleteTemplate},
+
+		{http.MethodPost, "/metadata_queries/execute_read", (*Server).executeMetadataQuery},
+
+		{http.MethodGet, "/metadata_cascade_policies", (*Server).listCascadePolicies},
+		{http.MethodPost, "/metadata_cascade_policies", (*Server).createCascadePolicy},
+		{http.MethodGet, "/metadata_cascade_policies/:id", (*Server).getCascadePolicy},
+		{http.MethodDelete, "/metadata_cascade_policies/:id", (*Server).deleteCascadePolicy},
+		{http.MethodPost, "/metadata_cascade_policies/:id/apply", (*Server).applyCascadePolicy},
+
+		{http.MethodGet, "/search", (*Server).search},
+
+		{http.MethodGet, "/events", (*Server).getEvents},
+		{http.MethodPost, "/batch", (*Server).batch},
+	}
+}
+
+func matchPattern(pattern string, path string) (id string, subID string, key string, ok bool) {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(ps) != len(segs) {
+		return "", "", "", false
+	}
+	for i, p := range ps {
+		if p == ":id" || p == ":sub" || p == ":key" {
+			if segs[i] == "" {
+				return "", "", "", false
+			}
+			switch p {
+			case ":id":
+				id = segs[i]
+			case ":sub":
+				subID = segs[i]
+			default:
+				key = segs[i]
+			}
+			continue
+		}
+		if p != segs[i] {
+			return "", "", "", false
+		}
+	}
+	return id, subID, key, true
+}
+
+func (s *Server) dispatch(c *call, path string) {
+	pathMatched := false
+	for _, rt := range routes {
+		id, subID, key, ok := matchPattern(rt.pattern, path)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rt.method != c.r.Method {
+			continue
+		}
+		c.id = id
+		c.subID = subID
+		c.key = key
+		rt.handle(s, c)
+		return
+	}
+	if pathMatched {
+		writeError(c.w, http.StatusMethodNotAllowed, "method_not_allowed", "Method Not Allowed", nil)
+		return
+	}
+	writeError(c.w, http.StatusNotFound, "not_found", "Not Found", nil)
+}
+
+func (s *Server) nextID() string {
+	s.lastID++
+	return strconv.FormatInt(s.lastID+10000, 10)
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// token is the OAuth2 token endpoint.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, "invalid_request", err.Error())
+		return
+	}
+	if s.ClientID != "" && (r.PostForm.Get("client_id") != s.ClientID || r.PostForm.Get("client_secret") != s.ClientSecret) {
+		grantType := r.PostForm.Get("grant_type")
+		// jwt assertion is signed by the client, client_secret is still required
+		if grantType != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.PostForm.Get("client_id") != s.ClientID {
+			writeOAuthError(w, "invalid_client", "The client credentials are invalid")
+			return
+		}
+	}
+
+	var userID string
+	switch r.PostForm.Get("grant_type") {
+	case "refresh_token":
+		var ok bool
+		userID, ok = s.refreshTokens[r.PostForm.Get("refresh_token")]
+		if !ok {
+			writeOAuthError(w, "invalid_grant", "Invalid refresh token")
+			return
+		}
+		delete(s.refreshTokens, r.PostForm.Get("refresh_token"))
+	case "authorization_code", "urn:ietf:params:oauth:grant-type:jwt-bearer", "client_credentials":
+		userID = s.AdminUserID
+	default:
+		writeOAuthError(w, "unsupported_grant_type", "Grant type is not supported")
+		return
+	}
+
+	accessToken, refreshToken := s.issueTokens(userID)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"expires_in":    3600,
+		"restricted_to": []interface{}{},
+		"token_type":    "bearer",
+	})
+}
+
+// revoke is the OAuth2 revoke endpoint.
+func (s *Server) revoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, "invalid_request", err.Error())
+		return
+	}
+	token := r.PostForm.Get("token")
+	delete(s.accessTokens, token)
+	delete(s.refreshTokens, token)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) issueTokens(userID string) (accessToken string, refreshToken string) {
+	s.lastID++
+	accessToken = fmt.Sprintf("ACCESS_TOKEN_%d", s.lastID)
+	refreshToken = fmt.Sprintf("REFRESH_TOKEN_%d", s.lastID)
+	s.accessTokens[accessToken] = userID
+	s.refreshTokens[refreshToken] = userID
+	return accessToken, refreshToken
+}
+
+func writeOAuthError(w http.ResponseWriter, code string, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	var buf bytes.Buffer
+	err := encodeJSON(&buf, v)
+	b := buf.Bytes()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_server_error", err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+// encodeJSON encodes v like json.Marshal, but writes "type" and "id" first in objects as Box does.
+// goboxer.ParseResource expects "type" near the beginning of the object.
+func encodeJSON(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case record:
+		return encodeJSON(buf, map[string]interface{}(v))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			if k != "type" && k != "id" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range []string{"id", "type"} {
+			if _, ok := v[k]; ok {
+				keys = append([]string{k}, keys...)
+			}
+		}
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			b, _ := json.Marshal(k)
+			buf.Write(b)
+			buf.WriteByte(':')
+			if err := encodeJSON(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case []interface{}:
+		if v == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeJSON(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+}
+
+var requestSeq int64
+
+// writeError writes the Box error object.
+func writeError(w http.ResponseWriter, status int, code string, message string, contextInfo map[string]interface{}) {
+	seq := atomic.AddInt64(&requestSeq, 1)
+	body := map[string]interface{}{
+		"type":       "error",
+		"status":     status,
+		"code":       code,
+		"help_url":   "http://developers.box.com/docs/#errors",
+		"message":    message,
+		"request_id": fmt.Sprintf("boxtest%d", seq),
+	}
+	if contextInfo != nil {
+		body["context_info"] = contextInfo
+	}
+	writeJSON(w, status, body)
+}
+
+func notFound(c *call) {
+	writeError(c.w, http.StatusNotFound, "not_found", "Not Found", nil)
+}
+
+func badRequest(c *call, message string) {
+	writeError(c.w, http.StatusBadRequest, "bad_request", message, nil)
+}
+
+// pagination returns offset and limit of the request. default limit is 100 and the maximum is 1000.
+func pagination(c *call) (offset int, limit int, ok bool) {
+	offset, limit = 0, 100
+	var err error
+	if v := c.query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			badRequest(c, "Invalid value '"+v+"'. 'offset' must be a non-negative integer")
+			return 0, 0, false
+		}
+	}
+	if v := c.query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			badRequest(c, "Invalid value '"+v+"'. 'limit' must be a non-negative integer")
+			return 0, 0, false
+		}
+	}
+	if limit == 0 || limit > 1000 {
+		if limit == 0 {
+			limit = 100
+		} else {
+			limit = 1000
+		}
+	}
+	return offset, limit, true
+}
+
+// page returns the window [offset, offset+limit) of n entries.
+func page(n int, offset int, limit int) (from int, to int) {
+	if offset > n {
+		offset = n
+	}
+	to = offset + limit
+	if to > n {
+		to = n
+	}
+	return offset, to
+}
+
+func collection(entries []interface{}, total int, offset int, limit int) map[string]interface{} {
+	return map[string]interface{}{
+		"total_count": total,
+		"entries":     nonNil(entries),
+		"offset":      offset,
+		"limit":       limit,
+	}
+}
+
+// markerPage returns the window of n entries for the marker based pagination.
+// The marker is the opaque string returned as "next_marker", empty if there are no more entries.
+func markerPage(c *call, n int) (from int, to int, nextMarker string, ok bool) {
+	return markerPageOf(c, n, c.query.Get("limit"), c.query.Get("marker"))
+}
+
+// markerPageOf is markerPage with the limit and the marker given in the body of the request.
+func markerPageOf(c *call, n int, v string, marker string) (from int, to int, nextMarker string, ok bool) {
+	limit := 100
+	if v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 0 {
+			badRequest(c, "Invalid value '"+v+"'. 'limit' must be a non-negative integer")
+			return 0, 0, "", false
+		}
+		if l > 1000 {
+			l = 1000
+		}
+		if l > 0 {
+			limit = l
+		}
+	}
+	if marker != "" {
+		b, err := base64.RawURLEncoding.DecodeString(marker)
+		if err == nil {
+			from, err = strconv.Atoi(strings.TrimPrefix(string(b), "offset:"))
+		}
+		if err != nil || !strings.HasPrefix(string(b), "offset:") || from < 0 {
+			writeError(c.w, http.StatusBadRequest, "invalid_parameter", "Invalid value '"+marker+"' for 'marker'", nil)
+			return 0, 0, "", false
+		}
+	}
+	from, to = page(n, from, limit)
+	if to < n {
+		nextMarker = base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(to)))
+	}
+	return from, to, nextMarker, true
+}
+
+func nonNil(entries []interface{}) []interface{} {
+	if entries == nil {
+		return []interface{}{}
+	}
+	return entries
+}
+
+func formatTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,1205 @@
+package boxtest_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jparound30/goboxer"
+	"github.com/jparound30/goboxer/boxtest"
+	"golang.org/x/xerrors"
+)
+
+func TestServer_Folders(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	folder, err := goboxer.NewFolder(apiConn).Create("0", "parent", nil)
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	if folder.Parent == nil || *folder.Parent.ID != "0" {
+		t.Errorf("unexpected parent: %v", folder.Parent)
+	}
+
+	_, err = goboxer.NewFolder(apiConn).Create("0", "PARENT", nil)
+	if !xerrors.Is(err, goboxer.ErrItemNameInUse) {
+		t.Errorf("expected ErrItemNameInUse, got %v", err)
+	}
+
+	for _, name := range []string{"c", "a", "b"} {
+		if _, err := goboxer.NewFolder(apiConn).Create(*folder.ID, name, nil); err != nil {
+			t.Fatalf("Create failed: %+v", err)
+		}
+	}
+	var names []string
+	for offset := 0; ; offset += 2 {
+		items, _, _, total, err := goboxer.NewFolder(apiConn).FolderItem(*folder.ID, offset, 2, "name", "ASC", nil)
+		if err != nil {
+			t.Fatalf("FolderItem failed: %+v", err)
+		}
+		if total != 3 {
+			t.Errorf("total_count = %d, want 3", total)
+		}
+		for _, it := range items {
+			names = append(names, *it.(*goboxer.Folder).Name)
+		}
+		if offset+2 >= total {
+			break
+		}
+	}
+	if got := strings.Join(names, ","); got != "a,b,c" {
+		t.Errorf("items = %s, want a,b,c", got)
+	}
+
+	got, err := goboxer.NewFolder(apiConn).GetInfo(*folder.ID, nil)
+	if err != nil {
+		t.Fatalf("GetInfo failed: %+v", err)
+	}
+	if got.ItemCollection == nil || got.ItemCollection.TotalCount != 3 {
+		t.Errorf("unexpected item_collection: %v", got.ItemCollection)
+	}
+}
+
+func TestServer_Files(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	file, err := goboxer.NewFile(apiConn).UploadFile("a.txt", strings.NewReader("hello"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+	_, err = goboxer.NewFile(apiConn).UploadFile("a.txt", strings.NewReader("hello"), "0", nil, nil, nil)
+	if !xerrors.Is(err, goboxer.ErrItemNameInUse) {
+		t.Errorf("expected ErrItemNameInUse, got %v", err)
+	}
+
+	info, err := goboxer.NewFile(apiConn).GetFileInfo(*file.ID, false, nil)
+	if err != nil {
+		t.Fatalf("GetFileInfo failed: %+v", err)
+	}
+	if info.Size != 5 || *info.Name != "a.txt" {
+		t.Errorf("unexpected file: %v", info)
+	}
+
+	etag := *info.ETag
+	updated, err := goboxer.NewFile(apiConn).SetName("b.txt").Update(*file.ID, etag, nil)
+	if err != nil {
+		t.Fatalf("Update failed: %+v", err)
+	}
+	if *updated.ETag == etag {
+		t.Errorf("etag is not changed by the update")
+	}
+
+	err = goboxer.NewFile(apiConn).Delete(*file.ID, etag)
+	if !xerrors.Is(err, goboxer.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed, got %v", err)
+	}
+	if err := goboxer.NewFile(apiConn).Delete(*file.ID, *updated.ETag); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	_, err = goboxer.NewFile(apiConn).GetFileInfo(*file.ID, false, nil)
+	if !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestServer_FileVersions(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	file, err := goboxer.NewFile(apiConn).UploadFile("a.txt", strings.NewReader("v1"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+	for _, content := range []string{"v2", "v3"} {
+		if _, err := goboxer.NewFile(apiConn).UploadFileVersion(*file.ID, strings.NewReader(content), nil, nil, nil, nil); err != nil {
+			t.Fatalf("UploadFileVersion failed: %+v", err)
+		}
+	}
+
+	fv := goboxer.NewFileVersion(apiConn)
+	versions, _, _, total, err := fv.GetVersions(*file.ID, 0, 100, goboxer.FileVersionAllFields)
+	if err != nil {
+		t.Fatalf("GetVersions failed: %+v", err)
+	}
+	// the current version (v3) is not listed, newest first
+	if total != 2 || len(versions) != 2 || *versions[0].Size != 2 || versions[1].ModifiedBy == nil {
+		t.Fatalf("unexpected versions: %d %v", total, versions)
+	}
+	oldest := versions[1]
+
+	if err := fv.Delete(*file.ID, oldest.ID, "0"); !xerrors.Is(err, goboxer.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed, got %v", err)
+	}
+	if err := fv.Delete(*file.ID, oldest.ID, ""); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	info, err := fv.GetVersionInfo(*file.ID, oldest.ID, nil)
+	if err != nil || !info.IsTrashed() || info.TrashedBy == nil {
+		t.Fatalf("GetVersionInfo() = %v, %v, want trashed version", info, err)
+	}
+	var statusErr *goboxer.ApiStatusError
+	if _, err := fv.Promote(*file.ID, oldest.ID, nil); !xerrors.As(err, &statusErr) || statusErr.Status != http.StatusBadRequest {
+		t.Errorf("expected 400 for the trashed version, got %v", err)
+	}
+	restored, err := fv.Restore(*file.ID, oldest.ID, nil)
+	if err != nil || restored.IsTrashed() || restored.RestoredAt == nil {
+		t.Fatalf("Restore() = %v, %v", restored, err)
+	}
+
+	// rolled back to v1
+	current, err := fv.Promote(*file.ID, oldest.ID, nil)
+	if err != nil {
+		t.Fatalf("Promote failed: %+v", err)
+	}
+	if current.Sha1 != oldest.Sha1 || current.ID == oldest.ID {
+		t.Errorf("unexpected promoted version: %v", current)
+	}
+	_, _, _, total, _ = fv.GetVersions(*file.ID, 0, 100, nil)
+	if total != 3 {
+		t.Errorf("%d past versions, want 3", total)
+	}
+	for version, want := range map[*goboxer.FileVersion]string{nil: "v1", versions[0]: "v2"} {
+		resp, err := goboxer.NewFile(apiConn).DownloadFile(*file.ID, version, "")
+		if err != nil || string(resp.Body) != want {
+			t.Errorf("DownloadFile(%v) = %v, want %s", version, err, want)
+		}
+	}
+}
+
+func TestServer_Comments(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	file, err := goboxer.NewFile(apiConn).UploadFile("a.txt", strings.NewReader("hello"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+	cm := goboxer.NewComment(apiConn)
+	first, err := cm.Create(goboxer.CommentItemFile, *file.ID, "LGTM "+goboxer.Mention(srv.AdminUserID, "Admin"), goboxer.CommentAllFields)
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	if *first.Message != "LGTM @Admin" || first.TaggedMessage == nil || *first.IsReplyComment {
+		t.Errorf("unexpected comment: %s / %v", *first.Message, first.TaggedMessage)
+	}
+	reply, err := cm.Reply(*first.ID, "thanks", nil)
+	if err != nil || !*reply.IsReplyComment || *reply.Item.Type != goboxer.CommentItemComment {
+		t.Fatalf("Reply() = %v, %v", reply, err)
+	}
+	if _, err := cm.Update(*reply.ID, "thank you", nil); err != nil {
+		t.Fatalf("Update failed: %+v", err)
+	}
+
+	comments, err := goboxer.NewFile(apiConn).CommentsAll(*file.ID, nil)
+	if err != nil || len(comments) != 2 || *comments[1].Message != "thank you" {
+		t.Fatalf("CommentsAll() = %v, %v", comments, err)
+	}
+	info, _ := goboxer.NewFile(apiConn).GetFileInfo(*file.ID, false, nil)
+	if *info.CommentCount != 2 {
+		t.Errorf("comment_count = %d, want 2", *info.CommentCount)
+	}
+
+	if err := cm.Delete(*first.ID); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	if _, err := cm.GetInfo(*first.ID, nil); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	events, _, err := goboxer.NewEvent(apiConn).UserEvent(goboxer.All, "", 100)
+	if err != nil {
+		t.Fatalf("UserEvent failed: %+v", err)
+	}
+	created := 0
+	for _, e := range events {
+		if e.EventType == goboxer.UE_COMMENT_CREATE {
+			if _, ok := e.Source().(*goboxer.Comment); !ok {
+				t.Errorf("source of the event is %T, want *Comment", e.Source())
+			}
+			created++
+		}
+	}
+	if created != 2 {
+		t.Errorf("%d COMMENT_CREATE events, want 2", created)
+	}
+}
+
+func TestServer_Tasks(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	file, err := goboxer.NewFile(apiConn).UploadFile("a.txt", strings.NewReader("hello"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+	user, err := goboxer.NewUser(apiConn).SetLogin("user@example.com").SetName("User").CreateUser(nil)
+	if err != nil {
+		t.Fatalf("CreateUser failed: %+v", err)
+	}
+
+	task, err := goboxer.NewTask(apiConn).SetMessage("please review").SetDueAt(time.Now().Add(24*time.Hour)).Create(*file.ID, goboxer.TaskAllFields)
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	if *task.Action != goboxer.TaskActionReview || *task.CompletionRule != goboxer.TaskCompletionRuleAllAssignees || *task.IsCompleted {
+		t.Errorf("unexpected task: %v %v %v", task.Action, task.CompletionRule, *task.IsCompleted)
+	}
+
+	ta := goboxer.NewTaskAssignment(apiConn)
+	login := "user@example.com"
+	byLogin, err := ta.Create(*task.ID, goboxer.UserGroupMini{Login: &login}, nil)
+	if err != nil || *byLogin.AssignedTo.ID != *user.ID || *byLogin.ResolutionState != goboxer.ResolutionStateIncomplete {
+		t.Fatalf("Create() = %v, %v", byLogin, err)
+	}
+	byID, err := ta.Create(*task.ID, goboxer.UserGroupMini{ID: &srv.AdminUserID}, nil)
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	var statusErr *goboxer.ApiStatusError
+	if _, err := ta.Update(*byID.ID, "", goboxer.ResolutionStateCompleted, nil); !xerrors.As(err, &statusErr) || statusErr.Status != http.StatusBadRequest {
+		t.Errorf("completed is not a resolution of a review task, got %v", err)
+	}
+	approved, err := ta.Update(*byID.ID, "OK", goboxer.ResolutionStateApproved, nil)
+	if err != nil || *approved.ResolutionState != goboxer.ResolutionStateApproved || approved.CompletedAt == nil {
+		t.Fatalf("Update() = %v, %v", approved, err)
+	}
+
+	assignments, err := goboxer.NewTask(apiConn).Assignments(*task.ID)
+	if err != nil || len(assignments) != 2 {
+		t.Fatalf("Assignments() = %v, %v", assignments, err)
+	}
+	// all assignees must resolve the task
+	task, _ = goboxer.NewTask(apiConn).GetInfo(*task.ID, nil)
+	if *task.IsCompleted || task.TaskAssignmentCollection.TotalCount != 2 {
+		t.Errorf("task is completed with an incomplete assignment")
+	}
+	task, err = goboxer.NewTask(apiConn).SetCompletionRule(goboxer.TaskCompletionRuleAnyAssignee).Update(*task.ID, nil)
+	if err != nil || !*task.IsCompleted {
+		t.Fatalf("Update() = %v, %v", task, err)
+	}
+
+	if err := ta.Delete(*byLogin.ID); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	tasks, err := goboxer.NewFile(apiConn).Tasks(*file.ID, nil)
+	if err != nil || len(tasks) != 1 || tasks[0].TaskAssignmentCollection.TotalCount != 1 {
+		t.Fatalf("Tasks() = %v, %v", tasks, err)
+	}
+	if err := goboxer.NewTask(apiConn).Delete(*task.ID); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	if _, err := ta.GetInfo(*byID.ID, nil); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("assignment of the deleted task: expected ErrNotFound, got %v", err)
+	}
+
+	events, _, err := goboxer.NewEvent(apiConn).UserEvent(goboxer.All, "", 100)
+	if err != nil {
+		t.Fatalf("UserEvent failed: %+v", err)
+	}
+	found := map[goboxer.EventType]int{}
+	for _, e := range events {
+		switch e.EventType {
+		case goboxer.UE_TASK_CREATE:
+			if _, ok := e.Source().(*goboxer.Task); !ok {
+				t.Errorf("source of the event is %T, want *Task", e.Source())
+			}
+		case goboxer.UE_TASK_ASSIGNMENT_CREATE:
+			if _, ok := e.Source().(*goboxer.TaskAssignment); !ok {
+				t.Errorf("source of the event is %T, want *TaskAssignment", e.Source())
+			}
+		}
+		found[e.EventType]++
+	}
+	if found[goboxer.UE_TASK_CREATE] != 1 || found[goboxer.UE_TASK_ASSIGNMENT_CREATE] != 2 {
+		t.Errorf("task events = %v", found)
+	}
+}
+
+func TestServer_UsersGroups(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	me, err := goboxer.NewUser(apiConn).GetCurrentUser(nil)
+	if err != nil {
+		t.Fatalf("GetCurrentUser failed: %+v", err)
+	}
+	if *me.ID != srv.AdminUserID {
+		t.Errorf("current user = %s, want %s", *me.ID, srv.AdminUserID)
+	}
+
+	user, err := goboxer.NewUser(apiConn).SetLogin("user@example.com").SetName("User").CreateUser(nil)
+	if err != nil {
+		t.Fatalf("CreateUser failed: %+v", err)
+	}
+	_, err = goboxer.NewUser(apiConn).SetLogin("user@example.com").SetName("User").CreateUser(nil)
+	if !xerrors.Is(err, goboxer.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+
+	group, err := goboxer.NewGroup(apiConn).SetName("group").CreateGroup(nil)
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %+v", err)
+	}
+	if _, err := goboxer.NewMembership(apiConn).SetUser(*user.ID).SetGroup(*group.ID).CreateMembership(); err != nil {
+		t.Fatalf("CreateMembership failed: %+v", err)
+	}
+	memberships, _, _, total, err := goboxer.NewMembership(apiConn).GetMembershipForGroup(*group.ID, 0, 100)
+	if err != nil {
+		t.Fatalf("GetMembershipForGroup failed: %+v", err)
+	}
+	if total != 1 || *memberships[0].User.ID != *user.ID {
+		t.Errorf("unexpected memberships: %v", memberships)
+	}
+
+	// As-User
+	asUser, err := goboxer.NewUser(apiConn.AsUser(*user.ID)).GetCurrentUser(nil)
+	if err != nil {
+		t.Fatalf("GetCurrentUser failed: %+v", err)
+	}
+	if *asUser.ID != *user.ID {
+		t.Errorf("current user = %s, want %s", *asUser.ID, *user.ID)
+	}
+}
+
+func TestServer_MarkerPaging(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	folder, err := goboxer.NewFolder(apiConn).Create("0", "large", nil)
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	for i := 0; i < 25; i++ {
+		if _, err := goboxer.NewFolder(apiConn).Create(*folder.ID, fmt.Sprintf("f%02d", i), nil); err != nil {
+			t.Fatalf("Create failed: %+v", err)
+		}
+	}
+	it := goboxer.NewFolder(apiConn).FolderItemMarkerIter(*folder.ID, nil, 10)
+	var names []string
+	for it.Next() {
+		names = append(names, *it.Item().(*goboxer.Folder).Name)
+	}
+	if it.Err() != nil {
+		t.Fatalf("FolderItemMarkerIter failed: %+v", it.Err())
+	}
+	if len(names) != 25 || names[0] != "f00" || names[24] != "f24" {
+		t.Errorf("unexpected items: %v", names)
+	}
+
+	// app users with external_app_user_id, which User does not set
+	for i := 0; i < 3; i++ {
+		body := fmt.Sprintf(`{"name":"app%d","is_platform_access_only":true,"external_app_user_id":"ext-%d"}`, i, i)
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/2.0/users", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+srv.AccessToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil || resp.StatusCode != http.StatusCreated {
+			t.Fatalf("create app user failed: %v, %v", resp, err)
+		}
+		_ = resp.Body.Close()
+	}
+	users, err := goboxer.NewUser(apiConn).GetEnterpriseUsersMarkerAll("", goboxer.UserTypeAll, "", nil)
+	if err != nil {
+		t.Fatalf("GetEnterpriseUsersMarkerAll failed: %+v", err)
+	}
+	if len(users) != 4 {
+		t.Errorf("%d users, want 4 (admin and app users)", len(users))
+	}
+	users, next, err := goboxer.NewUser(apiConn).GetEnterpriseUsersMarker("", "", "ext-1", "", 10, nil)
+	if err != nil {
+		t.Fatalf("GetEnterpriseUsersMarker failed: %+v", err)
+	}
+	if len(users) != 1 || *users[0].Name != "app1" || next != "" {
+		t.Errorf("unexpected users: %v, next_marker %q", users, next)
+	}
+	users, _, err = goboxer.NewUser(apiConn).GetEnterpriseUsersMarker("", goboxer.UserTypeExternal, "", "", 10, nil)
+	if err != nil || len(users) != 0 {
+		t.Errorf("external users = %v, %v", users, err)
+	}
+}
+
+func TestServer_CollaborationsEvents(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	_, position, err := goboxer.NewEvent(apiConn).UserEvent(goboxer.All, "now", 100)
+	if err != nil {
+		t.Fatalf("UserEvent failed: %+v", err)
+	}
+
+	folder, err := goboxer.NewFolder(apiConn).Create("0", "shared", nil)
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	user, err := goboxer.NewUser(apiConn).SetLogin("user@example.com").SetName("User").CreateUser(nil)
+	if err != nil {
+		t.Fatalf("CreateUser failed: %+v", err)
+	}
+	collab, err := goboxer.NewCollaboration(apiConn).Create(
+		goboxer.ItemMini{Type: setItemType(goboxer.TYPE_FOLDER), ID: folder.ID},
+		goboxer.UserGroupMini{Type: setUserGroupType(goboxer.TYPE_USER), ID: user.ID},
+		goboxer.EDITOR, nil, nil, false)
+	if err != nil {
+		t.Fatalf("Create collaboration failed: %+v", err)
+	}
+	if *collab.Item.ID != *folder.ID || *collab.AccessibleBy.ID != *user.ID {
+		t.Errorf("unexpected collaboration: %v", collab)
+	}
+
+	events, _, err := goboxer.NewEvent(apiConn).UserEvent(goboxer.All, position, 100)
+	if err != nil {
+		t.Fatalf("UserEvent failed: %+v", err)
+	}
+	var types []string
+	for _, e := range events {
+		types = append(types, string(e.EventType))
+	}
+	if got := strings.Join(types, ","); got != "ITEM_CREATE,COLLAB_ADD_COLLABORATOR" {
+		t.Errorf("events = %s", got)
+	}
+}
+
+func TestServer_Batch(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	folder := goboxer.NewFolder(apiConn)
+	resp, err := goboxer.NewBatchRequest(apiConn).ExecuteBatch([]*goboxer.Request{
+		folder.CreateReq("0", "batch", nil),
+		folder.GetInfoReq("999", nil),
+	})
+	if err != nil {
+		t.Fatalf("ExecuteBatch failed: %+v", err)
+	}
+	if len(resp.Responses) != 2 {
+		t.Fatalf("len(responses) = %d, want 2", len(resp.Responses))
+	}
+	if resp.Responses[0].ResponseCode != http.StatusCreated || resp.Responses[1].ResponseCode != http.StatusNotFound {
+		t.Errorf("unexpected status: %d, %d", resp.Responses[0].ResponseCode, resp.Responses[1].ResponseCode)
+	}
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	srv.InjectFault(boxtest.Fault{Method: http.MethodGet, Path: "/folders/*", StatusCode: http.StatusTooManyRequests, Times: 2})
+	if _, err := goboxer.NewFolder(apiConn).GetInfo("0", nil); err != nil {
+		t.Fatalf("GetInfo failed: %+v", err)
+	}
+	var n int
+	for _, r := range srv.Requests() {
+		if r == "GET /folders/0" {
+			n++
+		}
+	}
+	if n != 3 {
+		t.Errorf("GET /folders/0 requested %d times, want 3", n)
+	}
+
+	srv.InjectFault(boxtest.Fault{StatusCode: http.StatusForbidden})
+	_, err := goboxer.NewFolder(apiConn).GetInfo("0", nil)
+	if !xerrors.Is(err, goboxer.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	if err := apiConn.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %+v", err)
+	}
+	if apiConn.AccessToken == srv.AccessToken {
+		t.Errorf("access token is not refreshed")
+	}
+	if _, err := goboxer.NewFolder(apiConn).GetInfo("0", nil); err != nil {
+		t.Fatalf("GetInfo with the refreshed token failed: %+v", err)
+	}
+
+	invalid := goboxer.NewAPIConnWithAccessToken("INVALID_TOKEN").SetEndpoints(srv.Endpoints())
+	_, err := goboxer.NewFolder(invalid).GetInfo("0", nil)
+	if !xerrors.Is(err, goboxer.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func setItemType(t goboxer.ItemType) *goboxer.ItemType {
+	return &t
+}
+
+func setUserGroupType(t goboxer.UserGroupType) *goboxer.UserGroupType {
+	return &t
+}
+
+func TestServer_Watermarks(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	folder, err := goboxer.NewFolder(apiConn).Create("0", "deal room", nil)
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	file, err := goboxer.NewFile(apiConn).UploadFile("a.txt", strings.NewReader("hello"), *folder.ID, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+	if _, err := goboxer.NewFolder(apiConn).GetWatermark(*folder.ID); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	applied, err := goboxer.NewFolder(apiConn).ApplyWatermark(*folder.ID)
+	if err != nil || applied.CreatedAt == nil {
+		t.Fatalf("ApplyWatermark() = %v, %v", applied, err)
+	}
+	// the watermark of the folder applies to the files in it
+	info, _ := goboxer.NewFile(apiConn).GetFileInfo(*file.ID, false, nil)
+	if !info.WatermarkInfo.IsWatermarked {
+		t.Errorf("file in the watermarked folder is not watermarked")
+	}
+	if _, err := goboxer.NewFile(apiConn).GetWatermark(*file.ID); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("watermark is not applied to the file itself, got %v", err)
+	}
+	if _, err := goboxer.NewFile(apiConn).ApplyWatermark(*file.ID); err != nil {
+		t.Fatalf("ApplyWatermark failed: %+v", err)
+	}
+
+	if err := goboxer.NewFolder(apiConn).RemoveWatermark(*folder.ID); err != nil {
+		t.Fatalf("RemoveWatermark failed: %+v", err)
+	}
+	if err := goboxer.NewFolder(apiConn).RemoveWatermark(*folder.ID); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	got, err := goboxer.NewFolder(apiConn).GetInfo(*folder.ID, nil)
+	if err != nil || got.WatermarkInfo.IsWatermarked {
+		t.Errorf("folder is still watermarked: %v, %v", got.WatermarkInfo, err)
+	}
+	if _, err := goboxer.NewFile(apiConn).GetWatermark(*file.ID); err != nil {
+		t.Errorf("GetWatermark failed: %+v", err)
+	}
+}
+
+func TestServer_Metadata(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	if _, err := goboxer.NewMetadataTemplate(apiConn).SetTemplateKey("contract").SetDisplayName("Contract").
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldString, "vendor", "Vendor")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldFloat, "amount", "Amount")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldString, "status", "Status")).
+		Create(goboxer.MetadataScopeEnterprise); err != nil {
+		t.Fatalf("Create template failed: %+v", err)
+	}
+	if _, err := goboxer.NewMetadataTemplate(apiConn).SetDisplayName("Records").
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldFloat, "year", "Year")).
+		Create(goboxer.MetadataScopeEnterprise); err != nil {
+		t.Fatalf("Create template failed: %+v", err)
+	}
+
+	file, err := goboxer.NewFile(apiConn).UploadFile("contract.pdf", strings.NewReader("pdf"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+	type contract struct {
+		Vendor string  `json:"vendor"`
+		Amount float64 `json:"amount"`
+	}
+	f := goboxer.NewFile(apiConn)
+	created, err := f.CreateMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contract", contract{Vendor: "Acme", Amount: 100})
+	if err != nil {
+		t.Fatalf("CreateMetadata failed: %+v", err)
+	}
+	if created.Scope() != "enterprise_"+srv.EnterpriseID || created.Template() != "contract" || created.Parent() != "file_"+*file.ID {
+		t.Errorf("unexpected instance: %v", created)
+	}
+	if _, err := f.CreateMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contract", nil); !xerrors.Is(err, goboxer.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if _, err := f.CreateMetadata(*file.ID, goboxer.MetadataScopeGlobal, "properties", map[string]string{"owner": "legal"}); err != nil {
+		t.Fatalf("CreateMetadata failed: %+v", err)
+	}
+
+	// a failed test operation rejects the whole update
+	_, err = f.UpdateMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contract", []goboxer.MetadataOperation{
+		goboxer.MetadataReplace("/amount", 200),
+		goboxer.MetadataTest("/vendor", "Other"),
+	})
+	if err == nil {
+		t.Errorf("update with a failed test succeeded")
+	}
+	updated, err := f.UpdateMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contract", []goboxer.MetadataOperation{
+		goboxer.MetadataTest("/vendor", "Acme"),
+		goboxer.MetadataReplace("/amount", 200),
+		goboxer.MetadataAdd("status", "signed"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateMetadata failed: %+v", err)
+	}
+	var c contract
+	if err := updated.Unmarshal(&c); err != nil || c.Amount != 200 || updated.Version() != 1 || updated.Values()["status"] != "signed" {
+		t.Errorf("unexpected instance: %v, %v", updated, err)
+	}
+
+	all, err := f.GetAllMetadata(*file.ID)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("GetAllMetadata() = %v, %v", all, err)
+	}
+	info, err := f.GetFileInfo(*file.ID, false, []string{"name", goboxer.MetadataFields(goboxer.MetadataScopeEnterprise, "contract")})
+	if err != nil {
+		t.Fatalf("GetFileInfo failed: %+v", err)
+	}
+	if instance := info.Metadata.Instance(goboxer.MetadataScopeEnterprise, "contract"); instance == nil || instance.ID() != updated.ID() {
+		t.Errorf("metadata of the file = %v", info.Metadata)
+	}
+
+	if err := f.DeleteMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contract"); err != nil {
+		t.Fatalf("DeleteMetadata failed: %+v", err)
+	}
+	if _, err := f.GetMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contract"); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	folder, _ := goboxer.NewFolder(apiConn).Create("0", "records", nil)
+	if _, err := goboxer.NewFolder(apiConn).CreateMetadata(*folder.ID, goboxer.MetadataScopeEnterprise, "records", map[string]int{"year": 2020}); err != nil {
+		t.Fatalf("CreateMetadata failed: %+v", err)
+	}
+	instance, err := goboxer.NewFolder(apiConn).GetMetadata(*folder.ID, goboxer.MetadataScopeEnterprise, "records")
+	if err != nil || instance.Values()["year"] != float64(2020) {
+		t.Errorf("GetMetadata() = %v, %v", instance, err)
+	}
+}
+
+func TestServer_MetadataTemplates(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	mt := goboxer.NewMetadataTemplate(apiConn)
+	created, err := goboxer.NewMetadataTemplate(apiConn).SetDisplayName("Contract Info").
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldString, "vendor", "Vendor")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldEnum, "status", "Status", "draft", "signed")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldMultiSelect, "tags", "Tags", "legal", "hr")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldDate, "signedAt", "Signed at")).
+		Create(goboxer.MetadataScopeEnterprise)
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	if created.TemplateKey != "contractInfo" || created.Scope != "enterprise_"+srv.EnterpriseID || len(created.Fields) != 4 || created.Field("status").Options[0].ID == "" {
+		t.Errorf("unexpected template: %+v", created)
+	}
+	if _, err := goboxer.NewMetadataTemplate(apiConn).SetTemplateKey("contractInfo").SetDisplayName("Other").Create(goboxer.MetadataScopeEnterprise); !xerrors.Is(err, goboxer.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if got, err := mt.GetInfoById(created.ID); err != nil || got.TemplateKey != "contractInfo" {
+		t.Errorf("GetInfoById() = %+v, %v", got, err)
+	}
+	if got, err := mt.GetInfo(goboxer.MetadataScopeGlobal, "properties"); err != nil || got.DisplayName != "Properties" {
+		t.Errorf("GetInfo(global, properties) = %+v, %v", got, err)
+	}
+
+	// instances are validated against the template
+	f := goboxer.NewFile(apiConn)
+	file, _ := f.UploadFile("a.txt", strings.NewReader("a"), "0", nil, nil, nil)
+	invalid := []map[string]interface{}{
+		{"unknown": "x"},
+		{"status": "expired"},
+		{"tags": []string{"legal", "sales"}},
+		{"signedAt": "yesterday"},
+		{"vendor": 1},
+	}
+	for _, values := range invalid {
+		if _, err := f.CreateMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contractInfo", values); err == nil {
+			t.Errorf("CreateMetadata(%v) succeeded", values)
+		}
+	}
+	if _, err := f.CreateMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "missing", nil); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := f.CreateMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contractInfo", map[string]interface{}{
+		"vendor": "Acme", "status": "draft", "tags": []string{"legal"}, "signedAt": "2020-01-02T03:04:05Z"}); err != nil {
+		t.Fatalf("CreateMetadata failed: %+v", err)
+	}
+
+	// a failing operation rejects the whole update
+	if _, err := mt.Update(goboxer.MetadataScopeEnterprise, "contractInfo", []goboxer.MetadataTemplateOperation{
+		goboxer.EditMetadataTemplate(map[string]interface{}{"displayName": "Renamed"}),
+		goboxer.RemoveMetadataField("missing"),
+	}); err == nil {
+		t.Errorf("update with an invalid operation succeeded")
+	}
+	updated, err := mt.Update(goboxer.MetadataScopeEnterprise, "contractInfo", []goboxer.MetadataTemplateOperation{
+		goboxer.EditMetadataTemplate(map[string]interface{}{"displayName": "Contract"}),
+		goboxer.AddMetadataField(goboxer.NewMetadataField(goboxer.MetadataFieldFloat, "amount", "Amount")),
+		goboxer.EditMetadataField("vendor", map[string]interface{}{"key": "supplier"}),
+		goboxer.RemoveMetadataField("signedAt"),
+		goboxer.AddMetadataEnumOption("status", "expired"),
+		goboxer.RemoveMetadataMultiSelectOption("tags", "hr"),
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %+v", err)
+	}
+	if updated.DisplayName != "Contract" || updated.Field("supplier") == nil || updated.Field("signedAt") != nil ||
+		len(updated.Field("status").Options) != 3 || len(updated.Field("tags").Options) != 1 {
+		t.Errorf("unexpected template: %+v", updated)
+	}
+	// the instances follow the renamed and removed fields
+	instance, err := f.GetMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contractInfo")
+	if values := instance.Values(); err != nil || values["supplier"] != "Acme" || values["vendor"] != nil || values["signedAt"] != nil {
+		t.Errorf("GetMetadata() = %v, %v", instance, err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := goboxer.NewMetadataTemplate(apiConn).SetDisplayName(fmt.Sprintf("Template %d", i)).Create(goboxer.MetadataScopeEnterprise); err != nil {
+			t.Fatalf("Create failed: %+v", err)
+		}
+	}
+	templates, next, err := mt.EnterpriseTemplates("", 2)
+	if err != nil || len(templates) != 2 || next == "" {
+		t.Errorf("EnterpriseTemplates() = %v, %q, %v", templates, next, err)
+	}
+	all, err := mt.EnterpriseTemplatesAll()
+	if err != nil || len(all) != 4 || all[3].TemplateKey != "template2" {
+		t.Errorf("EnterpriseTemplatesAll() = %v, %v", all, err)
+	}
+
+	if err := mt.Delete(goboxer.MetadataScopeEnterprise, "contractInfo"); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	if _, err := mt.GetInfo(goboxer.MetadataScopeEnterprise, "contractInfo"); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := f.GetMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contractInfo"); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("instance of the deleted template remains: %v", err)
+	}
+}
+
+func TestServer_MetadataCascadePolicies(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	if _, err := goboxer.NewMetadataTemplate(apiConn).SetTemplateKey("project").SetDisplayName("Project").SetCopyInstanceOnItemCopy(true).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldString, "code", "Code")).
+		Create(goboxer.MetadataScopeEnterprise); err != nil {
+		t.Fatalf("Create template failed: %+v", err)
+	}
+	fo := goboxer.NewFolder(apiConn)
+	f := goboxer.NewFile(apiConn)
+	folder, _ := fo.Create("0", "project", nil)
+	sub, _ := fo.Create(*folder.ID, "sub", nil)
+	file, _ := f.UploadFile("a.txt", strings.NewReader("a"), *sub.ID, nil, nil, nil)
+	other, _ := f.UploadFile("b.txt", strings.NewReader("b"), *folder.ID, nil, nil, nil)
+	if _, err := f.CreateMetadata(*other.ID, goboxer.MetadataScopeEnterprise, "project", map[string]string{"code": "OLD"}); err != nil {
+		t.Fatalf("CreateMetadata failed: %+v", err)
+	}
+
+	mcp := goboxer.NewMetadataCascadePolicy(apiConn)
+	// the folder must have the instance
+	if _, err := mcp.Create(*folder.ID, goboxer.MetadataScopeEnterprise, "project"); err == nil {
+		t.Errorf("Create without the instance on the folder succeeded")
+	}
+	if _, err := fo.CreateMetadata(*folder.ID, goboxer.MetadataScopeEnterprise, "project", map[string]string{"code": "P1"}); err != nil {
+		t.Fatalf("CreateMetadata failed: %+v", err)
+	}
+	policy, err := mcp.Create(*folder.ID, goboxer.MetadataScopeEnterprise, "project")
+	if err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	if policy.Parent == nil || *policy.Parent.ID != *folder.ID || policy.OwnerEnterprise.ID != srv.EnterpriseID || policy.Scope != "enterprise_"+srv.EnterpriseID {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+	if _, err := mcp.Create(*folder.ID, goboxer.MetadataScopeEnterprise, "project"); !xerrors.Is(err, goboxer.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+
+	code := func(fileID string) interface{} {
+		instance, err := f.GetMetadata(fileID, goboxer.MetadataScopeEnterprise, "project")
+		if err != nil {
+			return err
+		}
+		return instance.Values()["code"]
+	}
+	// the existing instances are kept on create
+	if got := code(*file.ID); got != "P1" {
+		t.Errorf("cascaded value = %v", got)
+	}
+	if got := code(*other.ID); got != "OLD" {
+		t.Errorf("existing value = %v", got)
+	}
+	if _, err := fo.UpdateMetadata(*folder.ID, goboxer.MetadataScopeEnterprise, "project", []goboxer.MetadataOperation{goboxer.MetadataReplace("code", "P2")}); err != nil {
+		t.Fatalf("UpdateMetadata failed: %+v", err)
+	}
+	if err := mcp.ForceApply(policy.ID, goboxer.ConflictResolutionNone); err != nil {
+		t.Fatalf("ForceApply failed: %+v", err)
+	}
+	if got := code(*file.ID); got != "P1" {
+		t.Errorf("value after force apply with none = %v", got)
+	}
+	if err := mcp.ForceApply(policy.ID, goboxer.ConflictResolutionOverwrite); err != nil {
+		t.Fatalf("ForceApply failed: %+v", err)
+	}
+	if got, got2 := code(*file.ID), code(*other.ID); got != "P2" || got2 != "P2" {
+		t.Errorf("values after force apply with overwrite = %v, %v", got, got2)
+	}
+	if err := mcp.ForceApply(policy.ID, goboxer.ConflictResolution("merge")); err == nil {
+		t.Errorf("ForceApply with an invalid conflict resolution succeeded")
+	}
+
+	if got, err := mcp.GetInfo(policy.ID); err != nil || got.TemplateKey != "project" {
+		t.Errorf("GetInfo() = %+v, %v", got, err)
+	}
+	policies, err := mcp.ListAll(*folder.ID, "")
+	if err != nil || len(policies) != 1 || policies[0].ID != policy.ID {
+		t.Errorf("ListAll() = %v, %v", policies, err)
+	}
+	if policies, err := mcp.ListAll(*sub.ID, ""); err != nil || len(policies) != 0 {
+		t.Errorf("ListAll(sub) = %v, %v", policies, err)
+	}
+
+	// instances of the template are copied with the item
+	copied, err := f.Copy(*file.ID, "0", "copied.txt", "", "", nil)
+	if err != nil {
+		t.Fatalf("Copy failed: %+v", err)
+	}
+	if got := code(*copied.ID); got != "P2" {
+		t.Errorf("value of the copy = %v", got)
+	}
+
+	if err := mcp.Delete(policy.ID); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	if _, err := mcp.GetInfo(policy.ID); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if got := code(*file.ID); got != "P2" {
+		t.Errorf("cascaded value after delete = %v", got)
+	}
+}
+
+func TestServer_MetadataQuery(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	if _, err := goboxer.NewMetadataTemplate(apiConn).SetTemplateKey("contract").SetDisplayName("Contract").
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldString, "vendor", "Vendor")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldFloat, "amount", "Amount")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldDate, "signedAt", "Signed at")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldMultiSelect, "tags", "Tags", "legal", "hr")).
+		Create(goboxer.MetadataScopeEnterprise); err != nil {
+		t.Fatalf("Create template failed: %+v", err)
+	}
+	fo := goboxer.NewFolder(apiConn)
+	f := goboxer.NewFile(apiConn)
+	root, _ := fo.Create("0", "contracts", nil)
+	sub, _ := fo.Create(*root.ID, "2020", nil)
+	outside, _ := f.UploadFile("outside.pdf", strings.NewReader("x"), "0", nil, nil, nil)
+	contracts := []map[string]interface{}{
+		{"vendor": "Acme", "amount": 100, "signedAt": "2020-01-10T00:00:00Z", "tags": []string{"legal"}},
+		{"vendor": "acme labs", "amount": 300, "tags": []string{"legal", "hr"}},
+		{"vendor": "Other", "amount": 200, "signedAt": "2020-03-01T00:00:00Z"},
+		{"vendor": "Acme' OR vendor = 'Other", "amount": 50},
+	}
+	ids := map[string]string{}
+	for i, values := range contracts {
+		parent := *root.ID
+		if i%2 == 1 {
+			parent = *sub.ID
+		}
+		file, err := f.UploadFile(fmt.Sprintf("c%d.pdf", i), strings.NewReader("x"), parent, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("UploadFile failed: %+v", err)
+		}
+		if _, err := f.CreateMetadata(*file.ID, goboxer.MetadataScopeEnterprise, "contract", values); err != nil {
+			t.Fatalf("CreateMetadata failed: %+v", err)
+		}
+		ids[*file.ID] = *file.Name
+	}
+	if _, err := f.CreateMetadata(*outside.ID, goboxer.MetadataScopeEnterprise, "contract", map[string]interface{}{"vendor": "Acme"}); err != nil {
+		t.Fatalf("CreateMetadata failed: %+v", err)
+	}
+	if _, err := fo.CreateMetadata(*sub.ID, goboxer.MetadataScopeEnterprise, "contract", map[string]interface{}{"vendor": "Folder"}); err != nil {
+		t.Fatalf("CreateMetadata failed: %+v", err)
+	}
+
+	md := goboxer.NewMetadata(apiConn)
+	from := goboxer.MetadataQueryFrom("enterprise_"+srv.EnterpriseID, "contract")
+	byAmount := []goboxer.MetadataQueryOrder{{FieldKey: "amount", Direction: "ASC"}}
+	names := func(items []goboxer.BoxResource) string {
+		var r []string
+		for _, item := range items {
+			switch v := item.(type) {
+			case *goboxer.File:
+				r = append(r, *v.Name)
+			case *goboxer.Folder:
+				r = append(r, *v.Name)
+			}
+		}
+		return strings.Join(r, ",")
+	}
+	b := goboxer.NewMetadataQueryBuilder
+	tests := []struct {
+		name    string
+		builder *goboxer.MetadataQueryBuilder
+		want    string
+	}{
+		{"equal", b().Equal("vendor", "Acme"), "c0.pdf"},
+		{"injection is bound as a value", b().Equal("vendor", "Acme' OR vendor = 'Other"), "c3.pdf"},
+		{"range", b().GreaterThan("amount", 50).LessThanOrEqual("amount", 200), "c0.pdf,c2.pdf"},
+		{"ilike", b().ILike("vendor", "acme%"), "c3.pdf,c0.pdf,c1.pdf"},
+		{"in", b().In("vendor", "Other", "Folder"), "c2.pdf,2020"},
+		{"date", b().GreaterThanOrEqual("signedAt", time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)), "c2.pdf"},
+		{"multiSelect", b().Equal("tags", "hr"), "c1.pdf"},
+		{"or and null", b().Or(b().IsNull("amount"), b().Equal("vendor", "Other")), "c2.pdf,2020"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, params, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("Build failed: %+v", err)
+			}
+			items, err := md.QueryAll(from, *root.ID, query, params, byAmount, []string{"name"})
+			if err != nil {
+				t.Fatalf("QueryAll failed: %+v", err)
+			}
+			if got := names(items); got != tt.want {
+				t.Errorf("QueryAll(%s) = %s, want %s", query, got, tt.want)
+			}
+		})
+	}
+
+	// paginated by marker, with the instances requested by fields
+	items, next, err := md.Query(from, *root.ID, "", nil, []goboxer.MetadataQueryOrder{{FieldKey: "amount", Direction: "DESC"}},
+		[]string{"name", goboxer.MetadataFields("enterprise_"+srv.EnterpriseID, "contract")}, "", 2)
+	if err != nil || len(items) != 2 || next == "" {
+		t.Fatalf("Query() = %v, %q, %v", items, next, err)
+	}
+	file, ok := items[0].(*goboxer.File)
+	if !ok || *file.Name != "c1.pdf" {
+		t.Fatalf("unexpected first item: %+v", items[0])
+	}
+	if instance := file.Metadata.Instance(goboxer.MetadataScopeEnterprise, "contract"); instance == nil || instance.Values()["vendor"] != "acme labs" {
+		t.Errorf("metadata of the item = %v", file.Metadata)
+	}
+	rest, next, err := md.Query(from, *root.ID, "", nil, nil, nil, next, 100)
+	if err != nil || len(rest) != 3 || next != "" {
+		t.Errorf("Query(next) = %v, %q, %v", rest, next, err)
+	}
+
+	invalid := []struct {
+		query  string
+		params map[string]interface{}
+	}{
+		{"vendor = 'Acme'", nil},
+		{"vendor = :missing", nil},
+		{"vendor = :v AND", map[string]interface{}{"v": "Acme"}},
+		{"(vendor = :v", map[string]interface{}{"v": "Acme"}},
+		{"vendor LIKE", nil},
+	}
+	for _, tt := range invalid {
+		if _, _, err := md.Query(from, *root.ID, tt.query, tt.params, nil, nil, "", 100); err == nil {
+			t.Errorf("Query(%q) succeeded", tt.query)
+		}
+	}
+	if _, _, err := md.Query(goboxer.MetadataQueryFrom("enterprise_"+srv.EnterpriseID, "missing"), *root.ID, "", nil, nil, nil, "", 100); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestServer_Search(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	fo := goboxer.NewFolder(apiConn)
+	f := goboxer.NewFile(apiConn)
+	reports, _ := fo.Create("0", "reports", nil)
+	archive, _ := fo.Create(*reports.ID, "archive", nil)
+	upload := func(name string, content string, parentID string) *goboxer.File {
+		file, err := f.UploadFile(name, strings.NewReader(content), parentID, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("UploadFile failed: %+v", err)
+		}
+		return file
+	}
+	annual := upload("annual-report.pdf", "revenue grew", *reports.ID)
+	notes := upload("notes.txt", "annual summary", *reports.ID)
+	old := upload("old-report.docx", "legacy", *archive.ID)
+	upload("report.pdf", "x", "0")
+	deleted := upload("deleted-report.pdf", "gone", *reports.ID)
+	if err := f.Delete(*deleted.ID, ""); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	if _, err := goboxer.NewComment(apiConn).Create(goboxer.CommentItemFile, *notes.ID, "quarterly numbers", nil); err != nil {
+		t.Fatalf("Create comment failed: %+v", err)
+	}
+	if _, err := goboxer.NewMetadataTemplate(apiConn).SetTemplateKey("contract").SetDisplayName("Contract").
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldString, "vendor", "Vendor")).
+		AddField(goboxer.NewMetadataField(goboxer.MetadataFieldFloat, "amount", "Amount")).
+		Create(goboxer.MetadataScopeEnterprise); err != nil {
+		t.Fatalf("Create template failed: %+v", err)
+	}
+	for id, amount := range map[string]int{*annual.ID: 100, *old.ID: 300} {
+		if _, err := f.CreateMetadata(id, goboxer.MetadataScopeEnterprise, "contract", map[string]interface{}{"vendor": "Acme", "amount": amount}); err != nil {
+			t.Fatalf("CreateMetadata failed: %+v", err)
+		}
+	}
+
+	names := func(items []goboxer.BoxResource) string {
+		var r []string
+		for _, item := range items {
+			switch v := item.(type) {
+			case *goboxer.File:
+				r = append(r, *v.Name)
+			case *goboxer.Folder:
+				r = append(r, *v.Name)
+			}
+		}
+		return strings.Join(r, ",")
+	}
+	search := func() *goboxer.Search { return goboxer.NewSearch(apiConn) }
+	tests := []struct {
+		name   string
+		search *goboxer.Search
+		want   string
+	}{
+		{"query", search().SetQuery("REPORT"), "reports,annual-report.pdf,old-report.docx,report.pdf"},
+		{"type and ancestor", search().SetQuery("report").SetType(goboxer.TYPE_FILE).SetAncestorFolderIds(*reports.ID), "annual-report.pdf,old-report.docx"},
+		{"extensions", search().SetQuery("report").SetFileExtensions("pdf"), "annual-report.pdf,report.pdf"},
+		{"all terms", search().SetQuery("annual revenue"), "annual-report.pdf"},
+		{"content", search().SetQuery("annual"), "annual-report.pdf,notes.txt"},
+		{"content types", search().SetQuery("annual").SetContentTypes(goboxer.SearchContentTypeFileContent), "notes.txt"},
+		{"comments", search().SetQuery("quarterly"), "notes.txt"},
+		{"trashed only", search().SetQuery("report").SetTrashContent(goboxer.SearchTrashContentTrashedOnly), "deleted-report.pdf"},
+		{"size range", search().SetQuery("report").SetType(goboxer.TYPE_FILE).SetSizeRange(5, 20), "annual-report.pdf,old-report.docx"},
+		{"owner", search().SetQuery("report").SetOwnerUserIds("999"), ""},
+		{"created at range", search().SetQuery("report").SetCreatedAtRange(time.Now().Add(time.Hour), time.Time{}), ""},
+		{"metadata filter", search().AddMetadataFilter(goboxer.NewMetadataSearchFilter(goboxer.MetadataScopeEnterprise, "contract").
+			Equal("vendor", "Acme").Range("amount", 200, nil)), "old-report.docx"},
+		{"metadata filter with query", search().SetQuery("annual").AddMetadataFilter(goboxer.NewMetadataSearchFilter(goboxer.MetadataScopeEnterprise, "contract").
+			Equal("vendor", []string{"Other", "Acme"})), "annual-report.pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := tt.search.ExecuteAll(nil)
+			if err != nil {
+				t.Fatalf("ExecuteAll failed: %+v", err)
+			}
+			if got := names(items); got != tt.want {
+				t.Errorf("ExecuteAll() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// paginated by offset
+	it := search().SetQuery("report").ExecuteIter([]string{"name"}, 1)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() != nil || count != 4 {
+		t.Errorf("ExecuteIter() iterated %d items, %v", count, it.Err())
+	}
+	items, offset, _, total, err := search().SetQuery("report").Execute(3, 2, nil)
+	if err != nil || len(items) != 1 || offset != 3 || total != 4 {
+		t.Errorf("Execute() = %v, %d, %d, %v", items, offset, total, err)
+	}
+	if _, _, _, _, err := search().Execute(0, 100, nil); err == nil {
+		t.Errorf("search without query succeeded")
+	}
+}
+
+func TestServer_Trash(t *testing.T) {
+	srv := boxtest.NewServer()
+	defer srv.Close()
+	apiConn := srv.NewAPIConn()
+
+	fo := goboxer.NewFolder(apiConn)
+	f := goboxer.NewFile(apiConn)
+	docs, _ := fo.Create("0", "docs", nil)
+	inner, err := f.UploadFile("inner.txt", strings.NewReader("inner"), *docs.ID, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+	memo, err := f.UploadFile("memo.txt", strings.NewReader("memo"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+	if err := f.Delete(*memo.ID, ""); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	if err := fo.Delete(*docs.ID, true, ""); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+
+	// only the items moved to the trash themselves are listed
+	items, err := fo.TrashedItemsAll("name", "DESC", nil)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("TrashedItemsAll() = %v, %v", items, err)
+	}
+	if file, ok := items[0].(*goboxer.File); !ok || *file.Name != "memo.txt" {
+		t.Errorf("TrashedItemsAll()[0] = %v, want memo.txt", items[0])
+	}
+	if trashed, err := fo.GetTrashedInfo(*docs.ID, nil); err != nil || *trashed.ItemStatus != "trashed" {
+		t.Errorf("GetTrashedInfo() = %v, %v", trashed, err)
+	}
+	if _, err := f.GetTrashedInfo(*inner.ID, nil); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("GetTrashedInfo of a file in a trashed folder: expected ErrNotFound, got %v", err)
+	}
+	if _, err := f.GetTrashedInfo(*memo.ID, nil); err != nil {
+		t.Errorf("GetTrashedInfo failed: %+v", err)
+	}
+
+	// restore with the original name conflicts with the new item
+	if _, err := fo.Create("0", "docs", nil); err != nil {
+		t.Fatalf("Create failed: %+v", err)
+	}
+	if _, err := fo.Restore(*docs.ID, "", "", nil); !xerrors.Is(err, goboxer.ErrConflict) {
+		t.Errorf("Restore with a conflict: expected ErrConflict, got %v", err)
+	}
+	restored, err := fo.Restore(*docs.ID, "docs (restored)", "", nil)
+	if err != nil || *restored.Name != "docs (restored)" || *restored.ItemStatus != "active" {
+		t.Fatalf("Restore() = %v, %v", restored, err)
+	}
+	if got, err := f.GetFileInfo(*inner.ID, false, nil); err != nil || *got.ItemStatus != "active" {
+		t.Errorf("GetFileInfo of the item in the restored folder = %v, %v", got, err)
+	}
+	if restoredFile, err := f.Restore(*memo.ID, "", *docs.ID, nil); err != nil || *restoredFile.Parent.ID != *docs.ID {
+		t.Errorf("Restore() to the new parent = %v, %v", restoredFile, err)
+	}
+	if _, err := f.Restore(*memo.ID, "", "", nil); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("Restore of an active file: expected ErrNotFound, got %v", err)
+	}
+
+	// permanently deleted items can not be restored
+	if err := f.Delete(*inner.ID, ""); err != nil {
+		t.Fatalf("Delete failed: %+v", err)
+	}
+	if err := f.DeletePermanently(*inner.ID); err != nil {
+		t.Fatalf("DeletePermanently failed: %+v", err)
+	}
+	if _, err := f.Restore(*inner.ID, "", "", nil); !xerrors.Is(err, goboxer.ErrNotFound) {
+		t.Errorf("Restore of a deleted file: expected ErrNotFound, got %v", err)
+	}
+	items, _, _, total, err := fo.TrashedItems(0, 100, "", "", nil)
+	if err != nil || len(items) != 0 || total != 0 {
+		t.Errorf("TrashedItems() = %v, %d, %v", items, total, err)
+	}
+}
@@ -0,0 +1,260 @@
+package boxtest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// collab renders the collaboration with the current representation of its item.
+func (s *Server) collab(r record) record {
+	out := record{}
+	for k, v := range r {
+		out[k] = v
+	}
+	if it, ok := s.items[miniID(r["item"])]; ok {
+		out["item"] = s.mini(it)
+	}
+	return out
+}
+
+func (s *Server) hasCollaborations(itemID string) bool {
+	for _, id := range s.collabOrder {
+		if miniID(s.collaborations[id]["item"]) == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getCollaboration(c *call) {
+	r, ok := s.collaborations[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, s.collab(r))
+}
+
+func (s *Server) createCollaboration(c *call) {
+	var body struct {
+		Item *struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		} `json:"item"`
+		AccessibleBy *struct {
+			Type  string `json:"type"`
+			ID    string `json:"id"`
+			Login string `json:"login"`
+		} `json:"accessible_by"`
+		Role        string `json:"role"`
+		CanViewPath *bool  `json:"can_view_path"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Item == nil || body.AccessibleBy == nil || body.Role == "" {
+		badRequest(c, "'item', 'accessible_by' and 'role' are required")
+		return
+	}
+	it, ok := s.liveItem(c, body.Item.Type, body.Item.ID)
+	if !ok {
+		return
+	}
+
+	status := "accepted"
+	var accessibleBy interface{}
+	var inviteEmail interface{}
+	switch body.AccessibleBy.Type {
+	case "group":
+		if _, ok := s.groups[body.AccessibleBy.ID]; !ok {
+			notFound(c)
+			return
+		}
+		accessibleBy = s.groupMini(body.AccessibleBy.ID)
+	case "user", "":
+		userID := body.AccessibleBy.ID
+		if userID == "" && body.AccessibleBy.Login != "" {
+			if u := s.findUserByLogin(body.AccessibleBy.Login); u != nil {
+				userID = u.id()
+			}
+		}
+		switch {
+		case userID != "":
+			if _, ok := s.users[userID]; !ok {
+				notFound(c)
+				return
+			}
+			accessibleBy = s.userMini(userID)
+		case body.AccessibleBy.Login != "":
+			// invitation to the user not (yet) in Box
+			status = "pending"
+			inviteEmail = body.AccessibleBy.Login
+		default:
+			badRequest(c, "'accessible_by' requires 'id' or 'login'")
+			return
+		}
+	default:
+		badRequest(c, "Invalid value '"+body.AccessibleBy.Type+"' for 'accessible_by.type'")
+		return
+	}
+
+	for _, id := range s.collabOrder {
+		r := s.collaborations[id]
+		if miniID(r["item"]) != it.id {
+			continue
+		}
+		if (accessibleBy != nil && miniID(r["accessible_by"]) == miniID(accessibleBy)) ||
+			(inviteEmail != nil && strings.EqualFold(r.str("invite_email"), body.AccessibleBy.Login)) {
+			writeError(c.w, http.StatusBadRequest, "user_already_collaborator", "User is already a collaborator", nil)
+			return
+		}
+	}
+
+	now := formatTime(s.now())
+	r := record{
+		"type":            "collaboration",
+		"id":              s.nextID(),
+		"created_by":      s.userMini(c.userID),
+		"created_at":      now,
+		"modified_at":     now,
+		"expires_at":      nil,
+		"status":          status,
+		"accessible_by":   accessibleBy,
+		"invite_email":    inviteEmail,
+		"role":            body.Role,
+		"acknowledged_at": nil,
+		"item":            map[string]interface{}{"type": it.typ, "id": it.id},
+		"can_view_path":   body.CanViewPath != nil && *body.CanViewPath,
+	}
+	if status == "accepted" {
+		r["acknowledged_at"] = now
+	}
+	s.collaborations[r.id()] = r
+	s.collabOrder = append(s.collabOrder, r.id())
+	if status == "pending" {
+		s.addEvent("COLLAB_INVITE_COLLABORATOR", c.userID, s.full(it))
+	} else {
+		s.addEvent("COLLAB_ADD_COLLABORATOR", c.userID, s.full(it))
+	}
+	writeJSON(c.w, http.StatusCreated, s.collab(r))
+}
+
+func (s *Server) updateCollaboration(c *call) {
+	r, ok := s.collaborations[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	var body struct {
+		Role        string `json:"role"`
+		Status      string `json:"status"`
+		CanViewPath *bool  `json:"can_view_path"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Role != "" && body.Role != r.str("role") {
+		r["role"] = body.Role
+		if it, ok := s.items[miniID(r["item"])]; ok {
+			s.addEvent("COLLAB_ROLE_CHANGE", c.userID, s.full(it))
+		}
+	}
+	if body.Status != "" && body.Status != r.str("status") {
+		if r.str("status") != "pending" {
+			badRequest(c, "Only pending collaborations can be accepted or rejected")
+			return
+		}
+		r["status"] = body.Status
+		r["acknowledged_at"] = formatTime(s.now())
+		if body.Status == "accepted" {
+			r["accessible_by"] = s.userMini(c.userID)
+		}
+	}
+	if body.CanViewPath != nil {
+		r["can_view_path"] = *body.CanViewPath
+	}
+	r["modified_at"] = formatTime(s.now())
+	writeJSON(c.w, http.StatusOK, s.collab(r))
+}
+
+func (s *Server) deleteCollaboration(c *call) {
+	r, ok := s.collaborations[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	if it, ok := s.items[miniID(r["item"])]; ok {
+		s.addEvent("COLLAB_REMOVE_COLLABORATOR", c.userID, s.full(it))
+	}
+	s.removeCollaboration(r.id())
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeCollaboration(id string) {
+	delete(s.collaborations, id)
+	s.collabOrder = removeID(s.collabOrder, id)
+}
+
+// pendingCollaborations returns the pending invitations of the current user.
+func (s *Server) pendingCollaborations(c *call) {
+	if c.query.Get("status") != "pending" {
+		badRequest(c, "'status' must be 'pending'")
+		return
+	}
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	login := s.users[c.userID].str("login")
+	var matched []interface{}
+	for _, id := range s.collabOrder {
+		r := s.collaborations[id]
+		if r.str("status") == "pending" && strings.EqualFold(r.str("invite_email"), login) {
+			matched = append(matched, s.collab(r))
+		}
+	}
+	from, to := page(len(matched), offset, limit)
+	writeJSON(c.w, http.StatusOK, collection(matched[from:to], len(matched), offset, limit))
+}
+
+func (s *Server) itemCollaborations(c *call) {
+	typ := c.itemType()
+	it, ok := s.liveItem(c, typ, c.id)
+	if !ok {
+		return
+	}
+	var matched []interface{}
+	for _, id := range s.collabOrder {
+		if r := s.collaborations[id]; miniID(r["item"]) == it.id {
+			matched = append(matched, s.collab(r))
+		}
+	}
+	if typ == "folder" {
+		writeJSON(c.w, http.StatusOK, map[string]interface{}{"total_count": len(matched), "entries": nonNil(matched)})
+		return
+	}
+	from, to, next, ok := markerPage(c, len(matched))
+	if !ok {
+		return
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{"next_marker": next, "entries": nonNil(matched[from:to])})
+}
+
+func (s *Server) groupCollaborations(c *call) {
+	if _, ok := s.groups[c.id]; !ok {
+		notFound(c)
+		return
+	}
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	var matched []interface{}
+	for _, id := range s.collabOrder {
+		if r := s.collaborations[id]; miniID(r["accessible_by"]) == c.id {
+			matched = append(matched, s.collab(r))
+		}
+	}
+	from, to := page(len(matched), offset, limit)
+	writeJSON(c.w, http.StatusOK, collection(matched[from:to], len(matched), offset, limit))
+}
@@ -0,0 +1,154 @@
+package boxtest
+
+import (
+	"net/http"
+	"regexp"
+)
+
+type comment struct {
+	record
+	// fileID is the file of the comment, or of the comment replied to.
+	fileID string
+}
+
+var mentionPattern = regexp.MustCompile(`@\[\d+:([^\]]*)\]`)
+
+// commentsOf returns the comments (including replies) on the file, in order of creation.
+func (s *Server) commentsOf(fileID string) []*comment {
+	var comments []*comment
+	for _, id := range s.commentOrder {
+		if cm := s.comments[id]; cm.fileID == fileID {
+			comments = append(comments, cm)
+		}
+	}
+	return comments
+}
+
+func (s *Server) getComment(c *call) {
+	cm, ok := s.comments[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, cm.record)
+}
+
+func (s *Server) createComment(c *call) {
+	var body struct {
+		Item *struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		} `json:"item"`
+		Message       string `json:"message"`
+		TaggedMessage string `json:"tagged_message"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Item == nil || (body.Message == "" && body.TaggedMessage == "") {
+		badRequest(c, "'item' and 'message' or 'tagged_message' are required")
+		return
+	}
+	var fileID string
+	switch body.Item.Type {
+	case "file":
+		it, ok := s.liveItem(c, "file", body.Item.ID)
+		if !ok {
+			return
+		}
+		fileID = it.id
+	case "comment":
+		parent, ok := s.comments[body.Item.ID]
+		if !ok {
+			notFound(c)
+			return
+		}
+		fileID = parent.fileID
+	default:
+		badRequest(c, "Invalid value '"+body.Item.Type+"' for 'item.type'")
+		return
+	}
+
+	message := body.Message
+	var taggedMessage interface{}
+	if body.TaggedMessage != "" {
+		// the plain message has the names of the mentioned users
+		message = mentionPattern.ReplaceAllString(body.TaggedMessage, "@$1")
+		taggedMessage = body.TaggedMessage
+	}
+	now := formatTime(s.now())
+	cm := &comment{
+		record: record{
+			"type":             "comment",
+			"id":               s.nextID(),
+			"is_reply_comment": body.Item.Type == "comment",
+			"message":          message,
+			"tagged_message":   taggedMessage,
+			"created_by":       s.userMini(c.userID),
+			"created_at":       now,
+			"modified_at":      now,
+			"item":             map[string]interface{}{"type": body.Item.Type, "id": body.Item.ID},
+		},
+		fileID: fileID,
+	}
+	s.comments[cm.id()] = cm
+	s.commentOrder = append(s.commentOrder, cm.id())
+	s.addEvent("COMMENT_CREATE", c.userID, cm.record)
+	writeJSON(c.w, http.StatusCreated, cm.record)
+}
+
+func (s *Server) updateComment(c *call) {
+	cm, ok := s.comments[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	var body struct {
+		Message string `json:"message"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Message == "" {
+		badRequest(c, "'message' is required")
+		return
+	}
+	cm.record["message"] = body.Message
+	cm.record["tagged_message"] = nil
+	cm.record["modified_at"] = formatTime(s.now())
+	writeJSON(c.w, http.StatusOK, cm.record)
+}
+
+func (s *Server) deleteComment(c *call) {
+	if _, ok := s.comments[c.id]; !ok {
+		notFound(c)
+		return
+	}
+	delete(s.comments, c.id)
+	for i, id := range s.commentOrder {
+		if id == c.id {
+			s.commentOrder = append(s.commentOrder[:i], s.commentOrder[i+1:]...)
+			break
+		}
+	}
+	s.addEvent("COMMENT_DELETE", c.userID, record{"type": "comment", "id": c.id})
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) fileComments(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok {
+		return
+	}
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	comments := s.commentsOf(it.id)
+	from, to := page(len(comments), offset, limit)
+	var entries []interface{}
+	for _, cm := range comments[from:to] {
+		entries = append(entries, cm.record)
+	}
+	writeJSON(c.w, http.StatusOK, collection(entries, len(comments), offset, limit))
+}
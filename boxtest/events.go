@@ -0,0 +1,138 @@
+package boxtest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type event struct {
+	id          string
+	eventType   string
+	createdByID string
+	createdAt   time.Time
+	// snapshot of the source at the time of the event
+	source interface{}
+}
+
+// addEvent appends the event to the user event stream.
+func (s *Server) addEvent(eventType string, userID string, source interface{}) {
+	s.events = append(s.events, s.newEvent(eventType, userID, source))
+}
+
+// addAdminEvent appends the event to the enterprise event stream (admin_logs).
+func (s *Server) addAdminEvent(eventType string, userID string, source interface{}) {
+	s.adminEvents = append(s.adminEvents, s.newEvent(eventType, userID, source))
+}
+
+func (s *Server) newEvent(eventType string, userID string, source interface{}) *event {
+	return &event{
+		id:          "event" + s.nextID(),
+		eventType:   eventType,
+		createdByID: userID,
+		createdAt:   s.now(),
+		source:      source,
+	}
+}
+
+func (s *Server) renderEvent(e *event) map[string]interface{} {
+	return map[string]interface{}{
+		"type":               "event",
+		"event_id":           e.id,
+		"created_by":         s.userMini(e.createdByID),
+		"created_at":         formatTime(e.createdAt),
+		"recorded_at":        formatTime(e.createdAt),
+		"event_type":         e.eventType,
+		"session_id":         nil,
+		"source":             e.source,
+		"additional_details": nil,
+	}
+}
+
+// events not included in "changes" and "sync" streams
+var nonChangeEvents = map[string]bool{
+	"ITEM_DOWNLOAD": true,
+	"ITEM_PREVIEW":  true,
+}
+
+func (s *Server) getEvents(c *call) {
+	limit := 100
+	if v := c.query.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 0 {
+			badRequest(c, "Invalid value '"+v+"'. 'limit' must be a non-negative integer")
+			return
+		}
+		if l > 500 {
+			l = 500
+		}
+		if l > 0 {
+			limit = l
+		}
+	}
+
+	streamType := c.query.Get("stream_type")
+	admin := streamType == "admin_logs"
+	events := s.events
+	if admin {
+		events = s.adminEvents
+	}
+
+	position := 0
+	switch v := c.query.Get("stream_position"); v {
+	case "", "0":
+	case "now":
+		position = len(events)
+	default:
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 0 {
+			writeError(c.w, http.StatusBadRequest, "invalid_parameter", "Invalid value '"+v+"' for 'stream_position'", nil)
+			return
+		}
+		position = p
+	}
+	if position > len(events) {
+		position = len(events)
+	}
+
+	eventTypes := map[string]bool{}
+	for _, t := range strings.Split(c.query.Get("event_type"), ",") {
+		if t != "" {
+			eventTypes[t] = true
+		}
+	}
+	var createdAfter, createdBefore time.Time
+	if admin {
+		createdAfter, _ = time.Parse(time.RFC3339, c.query.Get("created_after"))
+		createdBefore, _ = time.Parse(time.RFC3339, c.query.Get("created_before"))
+	}
+
+	var entries []interface{}
+	for position < len(events) && len(entries) < limit {
+		e := events[position]
+		position++
+		switch {
+		case !admin && streamType != "all" && streamType != "" && nonChangeEvents[e.eventType]:
+			continue
+		case len(eventTypes) != 0 && !eventTypes[e.eventType]:
+			continue
+		case !createdAfter.IsZero() && e.createdAt.Before(createdAfter):
+			continue
+		case !createdBefore.IsZero() && e.createdAt.After(createdBefore):
+			continue
+		}
+		entries = append(entries, s.renderEvent(e))
+	}
+
+	r := map[string]interface{}{
+		"chunk_size": len(entries),
+		"entries":    nonNil(entries),
+	}
+	if admin {
+		r["next_stream_position"] = strconv.Itoa(position)
+	} else {
+		r["next_stream_position"] = position
+	}
+	writeJSON(c.w, http.StatusOK, r)
+}
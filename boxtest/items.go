@@ -0,0 +1,955 @@
+package boxtest
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// item is a file or a folder.
+type item struct {
+	typ         string
+	id          string
+	name        string
+	description string
+	parentID    string
+	// sequence_id and etag
+	seq int
+
+	createdAt         time.Time
+	modifiedAt        time.Time
+	trashedAt         time.Time
+	contentCreatedAt  time.Time
+	contentModifiedAt time.Time
+
+	ownerID      string
+	createdByID  string
+	modifiedByID string
+
+	tags       []string
+	sharedLink map[string]interface{}
+	// created_at and modified_at of the watermark, nil if not watermarked
+	watermark map[string]interface{}
+	// metadata instances in order of creation
+	metadata []record
+
+	// file only. the current version is the last one.
+	versions []*version
+	lock     map[string]interface{}
+
+	// folder only. folder_upload_email, sync_state, can_non_owners_invite...
+	extras map[string]interface{}
+}
+
+type version struct {
+	id           string
+	content      []byte
+	sha1         string
+	createdAt    time.Time
+	modifiedByID string
+
+	trashedAt    time.Time
+	trashedByID  string
+	restoredAt   time.Time
+	restoredByID string
+}
+
+func (it *item) etag() string {
+	return strconv.Itoa(it.seq)
+}
+
+func (it *item) current() *version {
+	if len(it.versions) == 0 {
+		return nil
+	}
+	return it.versions[len(it.versions)-1]
+}
+
+func (s *Server) touch(it *item, userID string) {
+	it.seq++
+	it.modifiedAt = s.now()
+	it.modifiedByID = userID
+}
+
+func (s *Server) newVersion(content []byte, userID string) *version {
+	sum := sha1.Sum(content)
+	return &version{
+		id:           s.nextID(),
+		content:      content,
+		sha1:         hex.EncodeToString(sum[:]),
+		createdAt:    s.now(),
+		modifiedByID: userID,
+	}
+}
+
+// isTrashed reports whether the item or one of its ancestors is in the trash.
+func (s *Server) isTrashed(it *item) bool {
+	for p := it; p != nil; p = s.items[p.parentID] {
+		if !p.trashedAt.IsZero() {
+			return true
+		}
+		if p.id == "0" {
+			break
+		}
+	}
+	return false
+}
+
+// isAncestor reports whether ancestorID is the item itself or one of its ancestors.
+func (s *Server) isAncestor(ancestorID string, it *item) bool {
+	for p := it; p != nil; p = s.items[p.parentID] {
+		if p.id == ancestorID {
+			return true
+		}
+		if p.id == "0" {
+			break
+		}
+	}
+	return false
+}
+
+// liveItem returns the item that is not in the trash, or writes 404.
+func (s *Server) liveItem(c *call, typ string, id string) (*item, bool) {
+	it, ok := s.items[id]
+	if !ok || it.typ != typ {
+		notFound(c)
+		return nil, false
+	}
+	if s.isTrashed(it) {
+		writeError(c.w, http.StatusNotFound, "trashed", "Item is trashed", nil)
+		return nil, false
+	}
+	return it, true
+}
+
+// children returns the live items in the folder ordered by id.
+func (s *Server) children(folderID string) []*item {
+	var r []*item
+	for _, it := range s.items {
+		if it.parentID == folderID && it.id != "0" && it.trashedAt.IsZero() {
+			r = append(r, it)
+		}
+	}
+	sort.Slice(r, func(i, j int) bool { return lessID(r[i].id, r[j].id) })
+	return r
+}
+
+func lessID(a string, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func (s *Server) findConflict(parentID string, name string, excludeID string) *item {
+	for _, it := range s.children(parentID) {
+		if it.id != excludeID && strings.EqualFold(it.name, name) {
+			return it
+		}
+	}
+	return nil
+}
+
+// writeConflict writes 409 item_name_in_use. Box returns the conflicting folder in an array, a file as an object.
+func (s *Server) writeConflict(c *call, conflict *item) {
+	var conflicts interface{} = s.mini(conflict)
+	if conflict.typ == "folder" {
+		conflicts = []interface{}{s.mini(conflict)}
+	}
+	writeError(c.w, http.StatusConflict, "item_name_in_use", "Item with the same name already exists",
+		map[string]interface{}{"conflicts": conflicts})
+}
+
+func validateName(c *call, name string) bool {
+	switch {
+	case name == "":
+		badRequest(c, "'name' is required")
+		return false
+	case len(name) > 255:
+		writeError(c.w, http.StatusBadRequest, "item_name_too_long", "Item name too long", nil)
+		return false
+	case name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.TrimSpace(name) != name:
+		writeError(c.w, http.StatusBadRequest, "item_name_invalid", "Item name invalid", nil)
+		return false
+	}
+	return true
+}
+
+// checkIfMatch writes 412 if "If-Match" header does not match the etag of the item.
+func checkIfMatch(c *call, it *item) bool {
+	ifMatch := c.r.Header.Get("If-Match")
+	if ifMatch != "" && ifMatch != it.etag() {
+		writeError(c.w, http.StatusPreconditionFailed, "precondition_failed", "The resource has been modified. Please retrieve the resource again and retry", nil)
+		return false
+	}
+	return true
+}
+
+// notModified writes 304 if "If-None-Match" header matches the etag of the item.
+func notModified(c *call, it *item) bool {
+	ifNoneMatch := c.r.Header.Get("If-None-Match")
+	if ifNoneMatch != "" && ifNoneMatch == it.etag() {
+		c.w.Header().Set("ETag", it.etag())
+		c.w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+func (s *Server) mini(it *item) map[string]interface{} {
+	m := map[string]interface{}{
+		"type":        it.typ,
+		"id":          it.id,
+		"sequence_id": it.etag(),
+		"etag":        it.etag(),
+		"name":        it.name,
+	}
+	if it.id == "0" {
+		m["sequence_id"] = nil
+		m["etag"] = nil
+	}
+	if v := it.current(); v != nil {
+		m["sha1"] = v.sha1
+		m["file_version"] = map[string]interface{}{"type": "file_version", "id": v.id, "sha1": v.sha1}
+	}
+	return m
+}
+
+func (s *Server) size(it *item) int {
+	if it.typ == "file" {
+		return len(it.current().content)
+	}
+	size := 0
+	for _, child := range s.children(it.id) {
+		size += s.size(child)
+	}
+	return size
+}
+
+func (s *Server) pathCollection(it *item) map[string]interface{} {
+	var entries []interface{}
+	if it.id != "0" {
+		for p := s.items[it.parentID]; p != nil; p = s.items[p.parentID] {
+			entries = append([]interface{}{s.mini(p)}, entries...)
+			if p.id == "0" {
+				break
+			}
+		}
+	}
+	if entries == nil {
+		entries = []interface{}{}
+	}
+	return map[string]interface{}{"total_count": len(entries), "entries": entries}
+}
+
+// full returns the full representation of the item.
+func (s *Server) full(it *item) map[string]interface{} {
+	m := s.mini(it)
+	m["description"] = it.description
+	m["size"] = s.size(it)
+	m["path_collection"] = s.pathCollection(it)
+	m["created_at"] = formatTime(it.createdAt)
+	m["modified_at"] = formatTime(it.modifiedAt)
+	m["trashed_at"] = formatTime(it.trashedAt)
+	m["purged_at"] = nil
+	m["content_created_at"] = formatTime(it.contentCreatedAt)
+	m["content_modified_at"] = formatTime(it.contentModifiedAt)
+	m["created_by"] = s.userMini(it.createdByID)
+	m["modified_by"] = s.userMini(it.modifiedByID)
+	m["owned_by"] = s.userMini(it.ownerID)
+	if it.sharedLink != nil {
+		m["shared_link"] = it.sharedLink
+	} else {
+		m["shared_link"] = nil
+	}
+	if p, ok := s.items[it.parentID]; ok && it.id != "0" {
+		m["parent"] = s.mini(p)
+	} else {
+		m["parent"] = nil
+	}
+	if !s.isTrashed(it) {
+		m["item_status"] = "active"
+	} else {
+		m["item_status"] = "trashed"
+	}
+	tags := it.tags
+	if tags == nil {
+		tags = []string{}
+	}
+	m["tags"] = tags
+	m["has_collaborations"] = s.hasCollaborations(it.id)
+	m["watermark_info"] = map[string]interface{}{"is_watermarked": s.isWatermarked(it)}
+
+	if it.typ == "file" {
+		m["version_number"] = strconv.Itoa(len(it.versions))
+		if it.lock != nil {
+			m["lock"] = it.lock
+		} else {
+			m["lock"] = nil
+		}
+		if i := strings.LastIndex(it.name, "."); i >= 0 {
+			m["extension"] = it.name[i+1:]
+		} else {
+			m["extension"] = ""
+		}
+		m["comment_count"] = len(s.commentsOf(it.id))
+	} else {
+		children := s.children(it.id)
+		var entries []interface{}
+		for i, child := range children {
+			if i == 100 {
+				break
+			}
+			entries = append(entries, s.mini(child))
+		}
+		m["item_collection"] = collection(entries, len(children), 0, 100)
+		for k, v := range it.extras {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+func (s *Server) getFile(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok || notModified(c, it) {
+		return
+	}
+	m := s.full(it)
+	if metadata := s.requestedMetadata(strings.Split(c.query.Get("fields"), ","), it); metadata != nil {
+		m["metadata"] = metadata
+	}
+	writeJSON(c.w, http.StatusOK, m)
+}
+
+func (s *Server) getFolder(c *call) {
+	it, ok := s.liveItem(c, "folder", c.id)
+	if !ok || notModified(c, it) {
+		return
+	}
+	m := s.full(it)
+	if metadata := s.requestedMetadata(strings.Split(c.query.Get("fields"), ","), it); metadata != nil {
+		m["metadata"] = metadata
+	}
+	writeJSON(c.w, http.StatusOK, m)
+}
+
+type itemRef struct {
+	ID string `json:"id"`
+}
+
+func (s *Server) createFolder(c *call) {
+	var body struct {
+		Name   string   `json:"name"`
+		Parent *itemRef `json:"parent"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Parent == nil {
+		badRequest(c, "'parent' is required")
+		return
+	}
+	parent, ok := s.liveItem(c, "folder", body.Parent.ID)
+	if !ok || !validateName(c, body.Name) {
+		return
+	}
+	if conflict := s.findConflict(parent.id, body.Name, ""); conflict != nil {
+		s.writeConflict(c, conflict)
+		return
+	}
+	now := s.now()
+	it := &item{
+		typ:          "folder",
+		id:           s.nextID(),
+		name:         body.Name,
+		parentID:     parent.id,
+		createdAt:    now,
+		modifiedAt:   now,
+		ownerID:      c.userID,
+		createdByID:  c.userID,
+		modifiedByID: c.userID,
+	}
+	s.items[it.id] = it
+	s.addEvent("ITEM_CREATE", c.userID, s.full(it))
+	writeJSON(c.w, http.StatusCreated, s.full(it))
+}
+
+func (s *Server) updateFile(c *call) {
+	s.updateItem(c, "file")
+}
+
+func (s *Server) updateFolder(c *call) {
+	s.updateItem(c, "folder")
+}
+
+// folder fields stored as is
+var folderExtraFields = []string{"folder_upload_email", "sync_state", "can_non_owners_invite", "is_collaboration_restricted_to_enterprise"}
+
+func (s *Server) updateItem(c *call, typ string) {
+	it, ok := s.liveItem(c, typ, c.id)
+	if !ok || !checkIfMatch(c, it) {
+		return
+	}
+	var body map[string]json.RawMessage
+	if !c.decodeBody(&body) {
+		return
+	}
+
+	var events []string
+	name := it.name
+	parentID := it.parentID
+	if raw, ok := body["name"]; ok {
+		if err := json.Unmarshal(raw, &name); err != nil || !validateName(c, name) {
+			if err != nil {
+				badRequest(c, "'name' must be a string")
+			}
+			return
+		}
+	}
+	if raw, ok := body["parent"]; ok {
+		var parent itemRef
+		if err := json.Unmarshal(raw, &parent); err != nil {
+			badRequest(c, "'parent' must be an object")
+			return
+		}
+		target, ok := s.liveItem(c, "folder", parent.ID)
+		if !ok {
+			return
+		}
+		if it.typ == "folder" && s.isAncestor(it.id, target) {
+			badRequest(c, "Cannot move a folder into itself or one of its subfolders")
+			return
+		}
+		parentID = target.id
+	}
+	if name != it.name || parentID != it.parentID {
+		if it.id == "0" {
+			writeError(c.w, http.StatusForbidden, "access_denied_insufficient_permissions", "Access denied - insufficient permission", nil)
+			return
+		}
+		if conflict := s.findConflict(parentID, name, it.id); conflict != nil {
+			s.writeConflict(c, conflict)
+			return
+		}
+		if name != it.name {
+			events = append(events, "ITEM_RENAME")
+		}
+		if parentID != it.parentID {
+			events = append(events, "ITEM_MOVE")
+		}
+		it.name = name
+		it.parentID = parentID
+	}
+
+	if raw, ok := body["description"]; ok {
+		_ = json.Unmarshal(raw, &it.description)
+	}
+	if raw, ok := body["tags"]; ok {
+		it.tags = nil
+		_ = json.Unmarshal(raw, &it.tags)
+	}
+	if raw, ok := body["shared_link"]; ok {
+		if string(raw) == "null" {
+			if it.sharedLink != nil {
+				events = append(events, "ITEM_SHARED_UNSHARE")
+			}
+			it.sharedLink = nil
+		} else {
+			var sl map[string]interface{}
+			if err := json.Unmarshal(raw, &sl); err != nil {
+				badRequest(c, "'shared_link' must be an object")
+				return
+			}
+			if it.sharedLink == nil {
+				events = append(events, "ITEM_SHARED_CREATE")
+			}
+			it.sharedLink = s.sharedLink(it, sl)
+		}
+	}
+	if raw, ok := body["lock"]; ok && it.typ == "file" {
+		if string(raw) == "null" {
+			if it.lock != nil {
+				events = append(events, "LOCK_DESTROY")
+			}
+			it.lock = nil
+		} else {
+			var lock map[string]interface{}
+			if err := json.Unmarshal(raw, &lock); err != nil {
+				badRequest(c, "'lock' must be an object")
+				return
+			}
+			if lock["type"] == "unlock" {
+				if it.lock != nil {
+					events = append(events, "LOCK_DESTROY")
+				}
+				it.lock = nil
+			} else {
+				isDownloadPrevented, _ := lock["is_download_prevented"].(bool)
+				it.lock = map[string]interface{}{
+					"type":                  "lock",
+					"id":                    s.nextID(),
+					"created_by":            s.userMini(c.userID),
+					"created_at":            formatTime(s.now()),
+					"expires_at":            lock["expires_at"],
+					"is_download_prevented": isDownloadPrevented,
+				}
+				events = append(events, "LOCK_CREATE")
+			}
+		}
+	}
+	if it.typ == "folder" {
+		for _, f := range folderExtraFields {
+			if raw, ok := body[f]; ok {
+				var v interface{}
+				_ = json.Unmarshal(raw, &v)
+				if it.extras == nil {
+					it.extras = map[string]interface{}{}
+				}
+				it.extras[f] = v
+			}
+		}
+	}
+
+	s.touch(it, c.userID)
+	for _, e := range events {
+		s.addEvent(e, c.userID, s.full(it))
+	}
+	writeJSON(c.w, http.StatusOK, s.full(it))
+}
+
+// sharedLink returns the shared link object Box would return for the requested one.
+// The password is never returned.
+func (s *Server) sharedLink(it *item, req map[string]interface{}) map[string]interface{} {
+	access, _ := req["access"].(string)
+	if access == "" {
+		access = "open"
+	}
+	isPasswordEnabled := false
+	if it.sharedLink != nil {
+		isPasswordEnabled, _ = it.sharedLink["is_password_enabled"].(bool)
+	}
+	if password, ok := req["password"]; ok {
+		isPasswordEnabled = password != nil && password != ""
+	}
+	canDownload := true
+	if perm, ok := req["permissions"].(map[string]interface{}); ok {
+		if v, ok := perm["can_download"].(bool); ok {
+			canDownload = v
+		}
+	}
+	sl := map[string]interface{}{
+		"url":                 s.URL + "/s/" + it.id,
+		"download_url":        nil,
+		"vanity_url":          nil,
+		"effective_access":    access,
+		"is_password_enabled": isPasswordEnabled,
+		"unshared_at":         req["unshared_at"],
+		"download_count":      0,
+		"preview_count":       0,
+		"access":              access,
+		"permissions": map[string]interface{}{
+			"can_download": canDownload,
+			"can_preview":  true,
+		},
+	}
+	if it.typ == "file" {
+		sl["download_url"] = s.URL + "/shared/static/" + it.id
+	}
+	return sl
+}
+
+func (s *Server) deleteFile(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok || !checkIfMatch(c, it) {
+		return
+	}
+	s.trash(it, c.userID)
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteFolder(c *call) {
+	it, ok := s.liveItem(c, "folder", c.id)
+	if !ok || !checkIfMatch(c, it) {
+		return
+	}
+	if it.id == "0" {
+		writeError(c.w, http.StatusForbidden, "access_denied_insufficient_permissions", "Access denied - insufficient permission", nil)
+		return
+	}
+	if c.query.Get("recursive") != "true" && len(s.children(it.id)) != 0 {
+		writeError(c.w, http.StatusBadRequest, "folder_not_empty", "Cannot delete – folder not empty", nil)
+		return
+	}
+	s.trash(it, c.userID)
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) trash(it *item, userID string) {
+	s.touch(it, userID)
+	it.trashedAt = s.now()
+	s.addEvent("ITEM_TRASH", userID, s.full(it))
+}
+
+func (s *Server) folderItems(c *call) {
+	folder, ok := s.liveItem(c, "folder", c.id)
+	if !ok {
+		return
+	}
+	children := s.children(folder.id)
+
+	by := c.query.Get("sort")
+	direction := strings.ToUpper(c.query.Get("direction"))
+	if c.query.Get("usemarker") == "true" {
+		// sorting is ignored with marker-based paging
+		by, direction = "", ""
+	}
+	if direction == "" {
+		direction = "ASC"
+	}
+	var less func(a, b *item) bool
+	switch by {
+	case "", "id":
+		by = "id"
+		less = func(a, b *item) bool { return lessID(a.id, b.id) }
+	case "name":
+		less = func(a, b *item) bool { return strings.ToLower(a.name) < strings.ToLower(b.name) }
+	case "date":
+		less = func(a, b *item) bool { return a.modifiedAt.Before(b.modifiedAt) }
+	default:
+		badRequest(c, "Invalid value '"+by+"' for 'sort'")
+		return
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		if direction == "DESC" {
+			return less(children[j], children[i])
+		}
+		return less(children[i], children[j])
+	})
+
+	var r map[string]interface{}
+	if c.query.Get("usemarker") == "true" {
+		from, to, next, ok := markerPage(c, len(children))
+		if !ok {
+			return
+		}
+		r = map[string]interface{}{"entries": nonNil(s.minis(children[from:to])), "next_marker": next}
+	} else {
+		offset, limit, ok := pagination(c)
+		if !ok {
+			return
+		}
+		from, to := page(len(children), offset, limit)
+		r = collection(s.minis(children[from:to]), len(children), offset, limit)
+	}
+	r["order"] = []interface{}{map[string]interface{}{"by": by, "direction": direction}}
+	writeJSON(c.w, http.StatusOK, r)
+}
+
+func (s *Server) minis(items []*item) []interface{} {
+	var r []interface{}
+	for _, it := range items {
+		r = append(r, s.mini(it))
+	}
+	return r
+}
+
+type copyRequest struct {
+	Name    string   `json:"name"`
+	Parent  *itemRef `json:"parent"`
+	Version string   `json:"version"`
+}
+
+func (s *Server) copyFile(c *call) {
+	src, ok := s.liveItem(c, "file", c.id)
+	if !ok {
+		return
+	}
+	var body copyRequest
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Parent == nil {
+		badRequest(c, "'parent' is required")
+		return
+	}
+	parent, ok := s.liveItem(c, "folder", body.Parent.ID)
+	if !ok {
+		return
+	}
+	v := src.current()
+	if body.Version != "" {
+		v = nil
+		for _, sv := range src.versions {
+			if sv.id == body.Version {
+				v = sv
+			}
+		}
+		if v == nil {
+			notFound(c)
+			return
+		}
+	}
+	name := src.name
+	if body.Name != "" {
+		name = body.Name
+	}
+	if !validateName(c, name) {
+		return
+	}
+	if conflict := s.findConflict(parent.id, name, ""); conflict != nil {
+		s.writeConflict(c, conflict)
+		return
+	}
+	it := s.copyItem(src, parent.id, name, c.userID)
+	it.versions = []*version{s.newVersion(v.content, c.userID)}
+	s.addEvent("ITEM_COPY", c.userID, s.full(it))
+	writeJSON(c.w, http.StatusCreated, s.full(it))
+}
+
+func (s *Server) copyFolder(c *call) {
+	src, ok := s.liveItem(c, "folder", c.id)
+	if !ok {
+		return
+	}
+	var body copyRequest
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Parent == nil {
+		badRequest(c, "'parent' is required")
+		return
+	}
+	parent, ok := s.liveItem(c, "folder", body.Parent.ID)
+	if !ok {
+		return
+	}
+	if src.id == "0" || s.isAncestor(src.id, parent) {
+		badRequest(c, "Cannot copy a folder into itself or one of its subfolders")
+		return
+	}
+	name := src.name
+	if body.Name != "" {
+		name = body.Name
+	}
+	if !validateName(c, name) {
+		return
+	}
+	if conflict := s.findConflict(parent.id, name, ""); conflict != nil {
+		s.writeConflict(c, conflict)
+		return
+	}
+	it := s.copyTree(src, parent.id, name, c.userID)
+	s.addEvent("ITEM_COPY", c.userID, s.full(it))
+	writeJSON(c.w, http.StatusCreated, s.full(it))
+}
+
+func (s *Server) copyItem(src *item, parentID string, name string, userID string) *item {
+	now := s.now()
+	it := &item{
+		typ:               src.typ,
+		id:                s.nextID(),
+		name:              name,
+		description:       src.description,
+		parentID:          parentID,
+		createdAt:         now,
+		modifiedAt:        now,
+		contentCreatedAt:  src.contentCreatedAt,
+		contentModifiedAt: src.contentModifiedAt,
+		ownerID:           userID,
+		createdByID:       userID,
+		modifiedByID:      userID,
+		tags:              append([]string(nil), src.tags...),
+	}
+	// metadata instances are copied with the item if the template says so
+	for _, m := range src.metadata {
+		if t := s.findTemplate(m.str("$scope"), m.str("$template")); t == nil || !t.CopyInstanceOnItemCopy {
+			continue
+		}
+		r := record{}
+		for k, v := range m {
+			r[k] = v
+		}
+		r["$id"] = s.nextID()
+		r["$parent"] = it.typ + "_" + it.id
+		it.metadata = append(it.metadata, r)
+	}
+	s.items[it.id] = it
+	return it
+}
+
+func (s *Server) copyTree(src *item, parentID string, name string, userID string) *item {
+	children := s.children(src.id)
+	it := s.copyItem(src, parentID, name, userID)
+	if src.typ == "file" {
+		it.versions = []*version{s.newVersion(src.current().content, userID)}
+		return it
+	}
+	for _, child := range children {
+		s.copyTree(child, it.id, child.name, userID)
+	}
+	return it
+}
+
+type uploadAttributes struct {
+	Name              string     `json:"name"`
+	Parent            *itemRef   `json:"parent"`
+	ContentCreatedAt  *time.Time `json:"content_created_at"`
+	ContentModifiedAt *time.Time `json:"content_modified_at"`
+}
+
+// readUpload reads the multipart/form-data body of the upload request.
+func readUpload(c *call) (attr uploadAttributes, content []byte, ok bool) {
+	if err := c.r.ParseMultipartForm(32 << 20); err != nil {
+		badRequest(c, "Invalid multipart body: "+err.Error())
+		return attr, nil, false
+	}
+	if err := json.Unmarshal([]byte(c.r.FormValue("attributes")), &attr); err != nil {
+		badRequest(c, "Invalid 'attributes': "+err.Error())
+		return attr, nil, false
+	}
+	f, _, err := c.r.FormFile("file")
+	if err != nil {
+		badRequest(c, "'file' is required")
+		return attr, nil, false
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	content, err = ioutil.ReadAll(f)
+	if err != nil {
+		badRequest(c, err.Error())
+		return attr, nil, false
+	}
+	return attr, content, true
+}
+
+func (s *Server) uploadFile(c *call) {
+	attr, content, ok := readUpload(c)
+	if !ok {
+		return
+	}
+	if attr.Parent == nil {
+		badRequest(c, "'parent' is required")
+		return
+	}
+	parent, ok := s.liveItem(c, "folder", attr.Parent.ID)
+	if !ok || !validateName(c, attr.Name) {
+		return
+	}
+	if conflict := s.findConflict(parent.id, attr.Name, ""); conflict != nil {
+		s.writeConflict(c, conflict)
+		return
+	}
+	now := s.now()
+	it := &item{
+		typ:               "file",
+		id:                s.nextID(),
+		name:              attr.Name,
+		parentID:          parent.id,
+		createdAt:         now,
+		modifiedAt:        now,
+		contentCreatedAt:  now,
+		contentModifiedAt: now,
+		ownerID:           c.userID,
+		createdByID:       c.userID,
+		modifiedByID:      c.userID,
+	}
+	if attr.ContentCreatedAt != nil {
+		it.contentCreatedAt = attr.ContentCreatedAt.UTC()
+	}
+	if attr.ContentModifiedAt != nil {
+		it.contentModifiedAt = attr.ContentModifiedAt.UTC()
+	}
+	it.versions = []*version{s.newVersion(content, c.userID)}
+	s.items[it.id] = it
+	s.addEvent("ITEM_UPLOAD", c.userID, s.full(it))
+	writeJSON(c.w, http.StatusCreated, collection([]interface{}{s.full(it)}, 1, 0, 1))
+}
+
+func (s *Server) uploadFileVersion(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok || !checkIfMatch(c, it) {
+		return
+	}
+	attr, content, ok := readUpload(c)
+	if !ok {
+		return
+	}
+	if attr.Name != "" && attr.Name != it.name {
+		if !validateName(c, attr.Name) {
+			return
+		}
+		if conflict := s.findConflict(it.parentID, attr.Name, it.id); conflict != nil {
+			s.writeConflict(c, conflict)
+			return
+		}
+		it.name = attr.Name
+	}
+	it.contentModifiedAt = s.now()
+	if attr.ContentModifiedAt != nil {
+		it.contentModifiedAt = attr.ContentModifiedAt.UTC()
+	}
+	it.versions = append(it.versions, s.newVersion(content, c.userID))
+	s.touch(it, c.userID)
+	s.addEvent("ITEM_UPLOAD", c.userID, s.full(it))
+	writeJSON(c.w, http.StatusCreated, collection([]interface{}{s.full(it)}, 1, 0, 1))
+}
+
+func (s *Server) preflightCheck(c *call) {
+	var body struct {
+		Name   string   `json:"name"`
+		Parent *itemRef `json:"parent"`
+		Size   int      `json:"size"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Parent == nil {
+		badRequest(c, "'parent' is required")
+		return
+	}
+	parent, ok := s.liveItem(c, "folder", body.Parent.ID)
+	if !ok || !validateName(c, body.Name) {
+		return
+	}
+	if conflict := s.findConflict(parent.id, body.Name, ""); conflict != nil {
+		s.writeConflict(c, conflict)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{
+		"upload_url":   s.URL + "/api/2.0/files/content",
+		"upload_token": nil,
+	})
+}
+
+func (s *Server) downloadFile(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok {
+		return
+	}
+	v := it.current()
+	if id := c.query.Get("version"); id != "" {
+		v = nil
+		for _, fv := range it.versions {
+			if fv.id == id {
+				v = fv
+			}
+		}
+		if v == nil {
+			notFound(c)
+			return
+		}
+	}
+	s.addEvent("ITEM_DOWNLOAD", c.userID, s.full(it))
+	c.w.Header().Set("Content-Type", "application/octet-stream")
+	c.w.WriteHeader(http.StatusOK)
+	_, _ = bytes.NewReader(v.content).WriteTo(c.w)
+}
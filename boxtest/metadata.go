@@ -0,0 +1,243 @@
+package boxtest
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// metadataScope resolves the scope, "enterprise" is the enterprise of the server.
+func (s *Server) metadataScope(c *call, scope string) (string, bool) {
+	switch scope {
+	case "global", "enterprise_" + s.EnterpriseID:
+		return scope, true
+	case "enterprise":
+		return "enterprise_" + s.EnterpriseID, true
+	}
+	badRequest(c, "Invalid value '"+scope+"' for 'scope'")
+	return "", false
+}
+
+// metadataInstance returns the index of the instance of the template in the item, or -1.
+func metadataInstance(it *item, scope string, templateKey string) int {
+	for i, r := range it.metadata {
+		if r.str("$scope") == scope && r.str("$template") == templateKey {
+			return i
+		}
+	}
+	return -1
+}
+
+// metadataTarget returns the item and the scope of the request to /files/:id/metadata/:sub/:key.
+func (s *Server) metadataTarget(c *call) (*item, string, bool) {
+	it, ok := s.liveItem(c, c.itemType(), c.id)
+	if !ok {
+		return nil, "", false
+	}
+	scope, ok := s.metadataScope(c, c.subID)
+	if !ok {
+		return nil, "", false
+	}
+	return it, scope, true
+}
+
+func (s *Server) allMetadata(c *call) {
+	it, ok := s.liveItem(c, c.itemType(), c.id)
+	if !ok {
+		return
+	}
+	entries := []interface{}{}
+	for _, r := range it.metadata {
+		entries = append(entries, r)
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{"entries": entries, "limit": 100})
+}
+
+func (s *Server) getMetadata(c *call) {
+	it, scope, ok := s.metadataTarget(c)
+	if !ok {
+		return
+	}
+	i := metadataInstance(it, scope, c.key)
+	if i < 0 {
+		writeError(c.w, http.StatusNotFound, "instance_not_found", "Instance not found", nil)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, it.metadata[i])
+}
+
+func (s *Server) createMetadata(c *call) {
+	it, scope, ok := s.metadataTarget(c)
+	if !ok {
+		return
+	}
+	var body map[string]interface{}
+	if !c.decodeBody(&body) {
+		return
+	}
+	t := s.findTemplate(scope, c.key)
+	if t == nil {
+		writeError(c.w, http.StatusNotFound, "instance_tuple_not_found", "Template not found", nil)
+		return
+	}
+	if metadataInstance(it, scope, c.key) >= 0 {
+		writeError(c.w, http.StatusConflict, "tuple_already_exists", "A resource with this value already exists", nil)
+		return
+	}
+	if message := validateMetadataValues(t, body); message != "" {
+		badRequest(c, message)
+		return
+	}
+	r := s.addMetadataInstance(it, scope, c.key, body)
+	s.addAdminEvent("METADATA_INSTANCE_CREATE", c.userID, s.mini(it))
+	writeJSON(c.w, http.StatusCreated, r)
+}
+
+// addMetadataInstance applies the new instance of the template with the values (except "$" fields) to the item.
+func (s *Server) addMetadataInstance(it *item, scope string, templateKey string, values map[string]interface{}) record {
+	r := record{}
+	for k, v := range values {
+		if !strings.HasPrefix(k, "$") {
+			r[k] = v
+		}
+	}
+	r["$id"] = s.nextID()
+	r["$parent"] = it.typ + "_" + it.id
+	r["$type"] = templateKey + "-" + r.str("$id")
+	r["$typeVersion"] = 0
+	r["$version"] = 0
+	r["$template"] = templateKey
+	r["$scope"] = scope
+	r["$canEdit"] = true
+	it.metadata = append(it.metadata, r)
+	return r
+}
+
+type metadataOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// applyMetadataOperations applies the JSON-Patch operations to the copy of the instance.
+// Only the top level fields can be patched, as the metadata instances are flat.
+func applyMetadataOperations(instance record, ops []metadataOperation) (record, string) {
+	r := record{}
+	for k, v := range instance {
+		r[k] = v
+	}
+	for _, op := range ops {
+		if !strings.HasPrefix(op.Path, "/") || strings.Count(op.Path, "/") != 1 {
+			return nil, "Invalid path '" + op.Path + "'"
+		}
+		field := strings.NewReplacer("~1", "/", "~0", "~").Replace(op.Path[1:])
+		if strings.HasPrefix(field, "$") {
+			return nil, "Cannot modify '" + field + "'"
+		}
+		current, exists := r[field]
+		switch op.Op {
+		case "add":
+			r[field] = op.Value
+		case "replace":
+			if !exists {
+				return nil, "Field '" + field + "' does not exist"
+			}
+			r[field] = op.Value
+		case "remove":
+			if !exists {
+				return nil, "Field '" + field + "' does not exist"
+			}
+			delete(r, field)
+		case "test":
+			if !exists || !reflect.DeepEqual(current, op.Value) {
+				return nil, "Test failed for '" + field + "'"
+			}
+		default:
+			return nil, "Invalid value '" + op.Op + "' for 'op'"
+		}
+	}
+	return r, ""
+}
+
+func (s *Server) updateMetadata(c *call) {
+	it, scope, ok := s.metadataTarget(c)
+	if !ok {
+		return
+	}
+	if mediaType, _, _ := mime.ParseMediaType(c.r.Header.Get("Content-Type")); mediaType != "application/json-patch+json" {
+		badRequest(c, "Content-Type must be application/json-patch+json")
+		return
+	}
+	i := metadataInstance(it, scope, c.key)
+	if i < 0 {
+		writeError(c.w, http.StatusNotFound, "instance_not_found", "Instance not found", nil)
+		return
+	}
+	var ops []metadataOperation
+	if err := json.NewDecoder(c.r.Body).Decode(&ops); err != nil {
+		badRequest(c, "Invalid JSON-Patch body: "+err.Error())
+		return
+	}
+	// all or nothing
+	r, message := applyMetadataOperations(it.metadata[i], ops)
+	if r == nil {
+		badRequest(c, message)
+		return
+	}
+	if t := s.findTemplate(scope, c.key); t != nil {
+		if message := validateMetadataValues(t, r); message != "" {
+			badRequest(c, message)
+			return
+		}
+	}
+	version, _ := r["$version"].(int)
+	r["$version"] = version + 1
+	it.metadata[i] = r
+	s.addAdminEvent("METADATA_INSTANCE_UPDATE", c.userID, s.mini(it))
+	writeJSON(c.w, http.StatusOK, r)
+}
+
+func (s *Server) deleteMetadata(c *call) {
+	it, scope, ok := s.metadataTarget(c)
+	if !ok {
+		return
+	}
+	i := metadataInstance(it, scope, c.key)
+	if i < 0 {
+		writeError(c.w, http.StatusNotFound, "instance_not_found", "Instance not found", nil)
+		return
+	}
+	it.metadata = append(it.metadata[:i], it.metadata[i+1:]...)
+	s.addAdminEvent("METADATA_INSTANCE_DELETE", c.userID, s.mini(it))
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// requestedMetadata returns the instances requested by the fields (e.g. "metadata.global.properties"),
+// or nil if not requested. The field of the instance (e.g. "metadata.global.properties.owner") requests the whole instance.
+func (s *Server) requestedMetadata(fields []string, it *item) map[string]interface{} {
+	var metadata map[string]interface{}
+	for _, field := range fields {
+		parts := strings.Split(field, ".")
+		if len(parts) < 3 || parts[0] != "metadata" {
+			continue
+		}
+		scope := parts[1]
+		if scope == "enterprise" {
+			scope = "enterprise_" + s.EnterpriseID
+		}
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		instances, _ := metadata[scope].(map[string]interface{})
+		if instances == nil {
+			instances = map[string]interface{}{}
+			metadata[scope] = instances
+		}
+		if i := metadataInstance(it, scope, parts[2]); i >= 0 {
+			instances[parts[2]] = it.metadata[i]
+		}
+	}
+	return metadata
+}
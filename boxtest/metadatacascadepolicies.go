@@ -0,0 +1,182 @@
+package boxtest
+
+import (
+	"net/http"
+	"strings"
+)
+
+func (s *Server) createCascadePolicy(c *call) {
+	var body struct {
+		FolderID    string `json:"folder_id"`
+		Scope       string `json:"scope"`
+		TemplateKey string `json:"templateKey"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.FolderID == "" || body.Scope == "" || body.TemplateKey == "" {
+		badRequest(c, "'folder_id', 'scope' and 'templateKey' are required")
+		return
+	}
+	folder, ok := s.liveItem(c, "folder", body.FolderID)
+	if !ok {
+		return
+	}
+	scope, ok := s.metadataScope(c, body.Scope)
+	if !ok {
+		return
+	}
+	if s.findTemplate(scope, body.TemplateKey) == nil {
+		writeError(c.w, http.StatusNotFound, "instance_tuple_not_found", "Template not found", nil)
+		return
+	}
+	if metadataInstance(folder, scope, body.TemplateKey) < 0 {
+		badRequest(c, "The folder does not have the instance of the template")
+		return
+	}
+	for _, id := range s.policyOrder {
+		if p := s.policies[id]; p.str("scope") == scope && p.str("templateKey") == body.TemplateKey && s.policyFolderID(p) == folder.id {
+			writeError(c.w, http.StatusConflict, "conflict", "A cascade policy of the template already exists on the folder", nil)
+			return
+		}
+	}
+	p := record{
+		"id":               s.nextID(),
+		"type":             "metadata_cascade_policy",
+		"owner_enterprise": map[string]interface{}{"type": "enterprise", "id": s.EnterpriseID},
+		"parent":           map[string]interface{}{"type": "folder", "id": folder.id},
+		"scope":            scope,
+		"templateKey":      body.TemplateKey,
+	}
+	s.policies[p.id()] = p
+	s.policyOrder = append(s.policyOrder, p.id())
+	// the instance is cascaded to the existing items without overwriting
+	s.cascadeMetadata(folder, scope, body.TemplateKey, false)
+	writeJSON(c.w, http.StatusCreated, p)
+}
+
+func (s *Server) policyFolderID(p record) string {
+	parent, _ := p["parent"].(map[string]interface{})
+	id, _ := parent["id"].(string)
+	return id
+}
+
+func (s *Server) listCascadePolicies(c *call) {
+	folderID := c.query.Get("folder_id")
+	if folderID == "" {
+		badRequest(c, "'folder_id' is required")
+		return
+	}
+	if _, ok := s.liveItem(c, "folder", folderID); !ok {
+		return
+	}
+	if owner := c.query.Get("owner_enterprise_id"); owner != "" && owner != s.EnterpriseID {
+		writeJSON(c.w, http.StatusOK, map[string]interface{}{"next_marker": "", "entries": []interface{}{}})
+		return
+	}
+	var entries []interface{}
+	for _, id := range s.policyOrder {
+		if p := s.policies[id]; s.policyFolderID(p) == folderID {
+			entries = append(entries, p)
+		}
+	}
+	from, to, next, ok := markerPage(c, len(entries))
+	if !ok {
+		return
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{"next_marker": next, "entries": nonNil(entries[from:to])})
+}
+
+func (s *Server) getCascadePolicy(c *call) {
+	p, ok := s.policies[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, p)
+}
+
+func (s *Server) deleteCascadePolicy(c *call) {
+	if _, ok := s.policies[c.id]; !ok {
+		notFound(c)
+		return
+	}
+	// the instances already cascaded are kept
+	s.removeCascadePolicy(c.id)
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeCascadePolicy(id string) {
+	delete(s.policies, id)
+	for i, pid := range s.policyOrder {
+		if pid == id {
+			s.policyOrder = append(s.policyOrder[:i], s.policyOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *Server) applyCascadePolicy(c *call) {
+	p, ok := s.policies[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	var body struct {
+		ConflictResolution string `json:"conflict_resolution"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.ConflictResolution != "none" && body.ConflictResolution != "overwrite" {
+		badRequest(c, "Invalid value '"+body.ConflictResolution+"' for 'conflict_resolution'")
+		return
+	}
+	folder, ok := s.liveItem(c, "folder", s.policyFolderID(p))
+	if !ok {
+		return
+	}
+	if metadataInstance(folder, p.str("scope"), p.str("templateKey")) < 0 {
+		badRequest(c, "The folder does not have the instance of the template")
+		return
+	}
+	// Box applies the policy asynchronously, the emulator applies it before responding.
+	s.cascadeMetadata(folder, p.str("scope"), p.str("templateKey"), body.ConflictResolution == "overwrite")
+	c.w.WriteHeader(http.StatusAccepted)
+}
+
+// cascadeMetadata applies the values of the instance on the folder to all the items in the folder.
+// The values of the instances already applied are replaced only if overwrite is true.
+func (s *Server) cascadeMetadata(folder *item, scope string, templateKey string, overwrite bool) {
+	values := map[string]interface{}{}
+	for k, v := range folder.metadata[metadataInstance(folder, scope, templateKey)] {
+		if !strings.HasPrefix(k, "$") {
+			values[k] = v
+		}
+	}
+	var walk func(parentID string)
+	walk = func(parentID string) {
+		for _, child := range s.children(parentID) {
+			if i := metadataInstance(child, scope, templateKey); i < 0 {
+				s.addMetadataInstance(child, scope, templateKey, values)
+			} else if overwrite {
+				r := record{}
+				for k, v := range child.metadata[i] {
+					if strings.HasPrefix(k, "$") {
+						r[k] = v
+					}
+				}
+				for k, v := range values {
+					r[k] = v
+				}
+				version, _ := r["$version"].(int)
+				r["$version"] = version + 1
+				child.metadata[i] = r
+			}
+			if child.typ == "folder" {
+				walk(child.id)
+			}
+		}
+	}
+	walk(folder.id)
+}
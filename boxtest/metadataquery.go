@@ -0,0 +1,399 @@
+package boxtest
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// metadataPredicate reports whether the metadata instance matches the query.
+type metadataPredicate func(r record) bool
+
+// metadataQueryParser parses the query of the metadata query, e.g. "amount >= :min AND (status = :s OR status IS NULL)".
+// Values must be bound by params, literals are not accepted.
+type metadataQueryParser struct {
+	tokens []string
+	pos    int
+	params map[string]interface{}
+}
+
+func tokenizeMetadataQuery(query string) ([]string, string) {
+	var tokens []string
+	rs := []rune(query)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',' || r == '=':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '<' || r == '>' || r == '!':
+			op := string(r)
+			if i+1 < len(rs) && (rs[i+1] == '=' || (r == '<' && rs[i+1] == '>')) {
+				op += string(rs[i+1])
+			}
+			if op == "!" {
+				return nil, "Invalid operator '!'"
+			}
+			tokens = append(tokens, op)
+			i += len(op)
+		case r == ':' || r == '_' || r == '$' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(rs) && (rs[j] == '_' || unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			if j == i+1 && r == ':' {
+				return nil, "Missing the name of the param"
+			}
+			tokens = append(tokens, string(rs[i:j]))
+			i = j
+		default:
+			return nil, "Unexpected '" + string(r) + "' in the query, values must be bound by query_params"
+		}
+	}
+	return tokens, ""
+}
+
+func (p *metadataQueryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *metadataQueryParser) keyword(kw string) bool {
+	if strings.EqualFold(p.peek(), kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *metadataQueryParser) expect(token string) string {
+	if p.keyword(token) {
+		return ""
+	}
+	return "Expected '" + token + "' but got '" + p.peek() + "'"
+}
+
+func (p *metadataQueryParser) param() (interface{}, string) {
+	token := p.peek()
+	if !strings.HasPrefix(token, ":") {
+		return nil, "Expected a param but got '" + token + "'"
+	}
+	p.pos++
+	v, ok := p.params[token[1:]]
+	if !ok {
+		return nil, "Param '" + token[1:] + "' is not in query_params"
+	}
+	return v, ""
+}
+
+func (p *metadataQueryParser) parseOr() (metadataPredicate, string) {
+	left, msg := p.parseAnd()
+	for msg == "" && p.keyword("OR") {
+		var right metadataPredicate
+		if right, msg = p.parseAnd(); msg == "" {
+			l := left
+			left = func(r record) bool { return l(r) || right(r) }
+		}
+	}
+	return left, msg
+}
+
+func (p *metadataQueryParser) parseAnd() (metadataPredicate, string) {
+	left, msg := p.parseNot()
+	for msg == "" && p.keyword("AND") {
+		var right metadataPredicate
+		if right, msg = p.parseNot(); msg == "" {
+			l := left
+			left = func(r record) bool { return l(r) && right(r) }
+		}
+	}
+	return left, msg
+}
+
+func (p *metadataQueryParser) parseNot() (metadataPredicate, string) {
+	if p.keyword("NOT") {
+		pred, msg := p.parseNot()
+		if msg != "" {
+			return nil, msg
+		}
+		return func(r record) bool { return !pred(r) }, ""
+	}
+	if p.keyword("(") {
+		pred, msg := p.parseOr()
+		if msg == "" {
+			msg = p.expect(")")
+		}
+		return pred, msg
+	}
+	return p.parseCondition()
+}
+
+var metadataFieldPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_]*$`)
+
+func (p *metadataQueryParser) parseCondition() (metadataPredicate, string) {
+	field := p.peek()
+	if !metadataFieldPattern.MatchString(field) {
+		return nil, "Expected a field but got '" + field + "'"
+	}
+	p.pos++
+
+	if p.keyword("IS") {
+		not := p.keyword("NOT")
+		if msg := p.expect("NULL"); msg != "" {
+			return nil, msg
+		}
+		return func(r record) bool { return (r[field] == nil) != not }, ""
+	}
+	not := p.keyword("NOT")
+	switch {
+	case p.keyword("IN"):
+		if msg := p.expect("("); msg != "" {
+			return nil, msg
+		}
+		var values []interface{}
+		for {
+			v, msg := p.param()
+			if msg != "" {
+				return nil, msg
+			}
+			values = append(values, v)
+			if !p.keyword(",") {
+				break
+			}
+		}
+		if msg := p.expect(")"); msg != "" {
+			return nil, msg
+		}
+		return func(r record) bool {
+			for _, v := range values {
+				if metadataValueEqual(r[field], v) {
+					return !not
+				}
+			}
+			return not
+		}, ""
+	case strings.EqualFold(p.peek(), "LIKE") || strings.EqualFold(p.peek(), "ILIKE"):
+		ignoreCase := strings.EqualFold(p.peek(), "ILIKE")
+		p.pos++
+		v, msg := p.param()
+		if msg != "" {
+			return nil, msg
+		}
+		pattern, _ := v.(string)
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.NewReplacer("%", ".*", "_", ".").Replace(expr)
+		if ignoreCase {
+			expr = "(?i)" + expr
+		}
+		re := regexp.MustCompile("^" + expr + "$")
+		return func(r record) bool {
+			s, ok := r[field].(string)
+			return ok && re.MatchString(s) != not
+		}, ""
+	case not:
+		return nil, "Expected 'IN', 'LIKE' or 'ILIKE' after 'NOT'"
+	}
+
+	op := p.peek()
+	switch op {
+	case "=", "!=", "<>", ">", ">=", "<", "<=":
+		p.pos++
+	default:
+		return nil, "Invalid operator '" + op + "'"
+	}
+	v, msg := p.param()
+	if msg != "" {
+		return nil, msg
+	}
+	switch op {
+	case "=":
+		return func(r record) bool { return metadataValueEqual(r[field], v) }, ""
+	case ">", ">=", "<", "<=":
+		return func(r record) bool {
+			cmp, ok := compareMetadataValues(r[field], v)
+			if !ok {
+				return false
+			}
+			switch op {
+			case ">":
+				return cmp > 0
+			case ">=":
+				return cmp >= 0
+			case "<":
+				return cmp < 0
+			}
+			return cmp <= 0
+		}, ""
+	}
+	// "!=" and "<>"
+	return func(r record) bool { return r[field] != nil && !metadataValueEqual(r[field], v) }, ""
+}
+
+// compareMetadataValues compares numbers, dates or strings. ok is false if they are not comparable.
+func compareMetadataValues(a interface{}, b interface{}) (cmp int, ok bool) {
+	toFloat := func(v interface{}) (float64, bool) {
+		switch n := v.(type) {
+		case float64:
+			return n, true
+		case int:
+			return float64(n), true
+		}
+		return 0, false
+	}
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	sa, ok1 := a.(string)
+	sb, ok2 := b.(string)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	ta, err1 := time.Parse(time.RFC3339, sa)
+	tb, err2 := time.Parse(time.RFC3339, sb)
+	if err1 == nil && err2 == nil {
+		switch {
+		case ta.Before(tb):
+			return -1, true
+		case ta.After(tb):
+			return 1, true
+		}
+		return 0, true
+	}
+	return strings.Compare(sa, sb), true
+}
+
+// metadataValueEqual reports whether the value of the instance equals to v.
+// A multiSelect value equals to v if any of the selected options equals to v.
+func metadataValueEqual(value interface{}, v interface{}) bool {
+	if options, ok := value.([]interface{}); ok {
+		for _, o := range options {
+			if metadataValueEqual(o, v) {
+				return true
+			}
+		}
+		return false
+	}
+	cmp, ok := compareMetadataValues(value, v)
+	return ok && cmp == 0
+}
+
+func (s *Server) executeMetadataQuery(c *call) {
+	var body struct {
+		From             string                 `json:"from"`
+		Query            string                 `json:"query"`
+		QueryParams      map[string]interface{} `json:"query_params"`
+		AncestorFolderID string                 `json:"ancestor_folder_id"`
+		OrderBy          []struct {
+			FieldKey  string `json:"field_key"`
+			Direction string `json:"direction"`
+		} `json:"order_by"`
+		Limit  int      `json:"limit"`
+		Marker string   `json:"marker"`
+		Fields []string `json:"fields"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	i := strings.Index(body.From, ".")
+	if i < 0 || body.AncestorFolderID == "" {
+		badRequest(c, "'from' (scope.templateKey) and 'ancestor_folder_id' are required")
+		return
+	}
+	scope, ok := s.metadataScope(c, body.From[:i])
+	if !ok {
+		return
+	}
+	templateKey := body.From[i+1:]
+	if s.findTemplate(scope, templateKey) == nil {
+		writeError(c.w, http.StatusNotFound, "instance_tuple_not_found", "Template not found", nil)
+		return
+	}
+	ancestor, ok := s.liveItem(c, "folder", body.AncestorFolderID)
+	if !ok {
+		return
+	}
+	match := func(r record) bool { return true }
+	if body.Query != "" {
+		tokens, msg := tokenizeMetadataQuery(body.Query)
+		if msg == "" {
+			p := &metadataQueryParser{tokens: tokens, params: body.QueryParams}
+			if match, msg = p.parseOr(); msg == "" && p.pos != len(tokens) {
+				msg = "Unexpected '" + p.peek() + "' in the query"
+			}
+		}
+		if msg != "" {
+			badRequest(c, msg)
+			return
+		}
+	}
+
+	type hit struct {
+		it       *item
+		instance record
+	}
+	var hits []hit
+	var walk func(parentID string)
+	walk = func(parentID string) {
+		for _, child := range s.children(parentID) {
+			if i := metadataInstance(child, scope, templateKey); i >= 0 && match(child.metadata[i]) {
+				hits = append(hits, hit{child, child.metadata[i]})
+			}
+			if child.typ == "folder" {
+				walk(child.id)
+			}
+		}
+	}
+	walk(ancestor.id)
+	sort.SliceStable(hits, func(i, j int) bool {
+		for _, o := range body.OrderBy {
+			a, b := hits[i].instance[o.FieldKey], hits[j].instance[o.FieldKey]
+			if a == nil || b == nil {
+				// missing values come last
+				if (a == nil) != (b == nil) {
+					return b == nil
+				}
+				continue
+			}
+			cmp, _ := compareMetadataValues(a, b)
+			if cmp != 0 {
+				return (cmp < 0) != strings.EqualFold(o.Direction, "DESC")
+			}
+		}
+		return false
+	})
+
+	if body.Limit <= 0 || body.Limit > 100 {
+		body.Limit = 100
+	}
+	from, to, next, ok := markerPageOf(c, len(hits), strconv.Itoa(body.Limit), body.Marker)
+	if !ok {
+		return
+	}
+	entries := []interface{}{}
+	for _, h := range hits[from:to] {
+		m := s.full(h.it)
+		if metadata := s.requestedMetadata(body.Fields, h.it); metadata != nil {
+			m["metadata"] = metadata
+		}
+		entries = append(entries, m)
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{"entries": entries, "next_marker": next})
+}
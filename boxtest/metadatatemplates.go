@@ -0,0 +1,451 @@
+package boxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type templateOption struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+type templateField struct {
+	ID          string            `json:"id"`
+	Type        string            `json:"type"`
+	Key         string            `json:"key"`
+	DisplayName string            `json:"displayName"`
+	Description string            `json:"description,omitempty"`
+	Hidden      bool              `json:"hidden"`
+	Options     []*templateOption `json:"options,omitempty"`
+}
+
+type metadataTemplate struct {
+	ID                     string           `json:"id"`
+	Type                   string           `json:"type"`
+	Scope                  string           `json:"scope"`
+	TemplateKey            string           `json:"templateKey"`
+	DisplayName            string           `json:"displayName"`
+	Hidden                 bool             `json:"hidden"`
+	Fields                 []*templateField `json:"fields"`
+	CopyInstanceOnItemCopy bool             `json:"copyInstanceOnItemCopy"`
+}
+
+// propertiesTemplate is the free-form template in the global scope, available in all enterprises.
+var propertiesTemplate = &metadataTemplate{
+	ID:          "properties",
+	Type:        "metadata_template",
+	Scope:       "global",
+	TemplateKey: "properties",
+	DisplayName: "Properties",
+	Fields:      []*templateField{},
+}
+
+func (t *metadataTemplate) field(key string) *templateField {
+	for _, f := range t.Fields {
+		if f.Key == key {
+			return f
+		}
+	}
+	return nil
+}
+
+func (f *templateField) hasOption(key string) bool {
+	for _, o := range f.Options {
+		if o.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// findTemplate returns the template of the (resolved) scope and the template key, or nil.
+func (s *Server) findTemplate(scope string, templateKey string) *metadataTemplate {
+	if scope == "global" && templateKey == "properties" {
+		return propertiesTemplate
+	}
+	for _, id := range s.templateOrder {
+		if t := s.templates[id]; t.Scope == scope && t.TemplateKey == templateKey {
+			return t
+		}
+	}
+	return nil
+}
+
+// validateMetadataValues returns the error message if the values do not match the fields of the template.
+// The properties template accepts any string values.
+func validateMetadataValues(t *metadataTemplate, values record) string {
+	for k, v := range values {
+		if strings.HasPrefix(k, "$") {
+			continue
+		}
+		if t == propertiesTemplate {
+			if _, ok := v.(string); !ok {
+				return "Value of '" + k + "' must be a string"
+			}
+			continue
+		}
+		f := t.field(k)
+		if f == nil {
+			return "Field '" + k + "' is not defined in the template"
+		}
+		valid := false
+		switch f.Type {
+		case "string":
+			_, valid = v.(string)
+		case "float":
+			switch v.(type) {
+			case float64, int:
+				valid = true
+			}
+		case "date":
+			if str, ok := v.(string); ok {
+				_, err := time.Parse(time.RFC3339, str)
+				valid = err == nil
+			}
+		case "enum":
+			str, ok := v.(string)
+			valid = ok && f.hasOption(str)
+		case "multiSelect":
+			if options, ok := v.([]interface{}); ok {
+				valid = true
+				for _, o := range options {
+					str, ok := o.(string)
+					valid = valid && ok && f.hasOption(str)
+				}
+			}
+		}
+		if !valid {
+			return "Invalid value for the " + f.Type + " field '" + k + "'"
+		}
+	}
+	return ""
+}
+
+// templateKeyOf generates the template key from the display name. (e.g. "Contract Info" -> "contractInfo")
+func templateKeyOf(displayName string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range displayName {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			upper = b.Len() > 0
+		case b.Len() == 0:
+			b.WriteRune(unicode.ToLower(r))
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (s *Server) validField(c *call, f *templateField) bool {
+	switch f.Type {
+	case "string", "float", "date", "enum", "multiSelect":
+	default:
+		badRequest(c, "Invalid value '"+f.Type+"' for 'type' of the field")
+		return false
+	}
+	if f.Key == "" || f.DisplayName == "" || strings.HasPrefix(f.Key, "$") {
+		badRequest(c, "'key' and 'displayName' of the field are required")
+		return false
+	}
+	f.ID = s.nextID()
+	for _, o := range f.Options {
+		o.ID = s.nextID()
+	}
+	return true
+}
+
+func (s *Server) createTemplate(c *call) {
+	var t metadataTemplate
+	if !c.decodeBody(&t) {
+		return
+	}
+	if t.Scope == "" || t.DisplayName == "" {
+		badRequest(c, "'scope' and 'displayName' are required")
+		return
+	}
+	scope, ok := s.metadataScope(c, t.Scope)
+	if !ok {
+		return
+	}
+	if scope == "global" {
+		writeError(c.w, http.StatusForbidden, "forbidden", "Templates can not be created in the global scope", nil)
+		return
+	}
+	t.Scope = scope
+	if t.TemplateKey == "" {
+		t.TemplateKey = templateKeyOf(t.DisplayName)
+	}
+	if s.findTemplate(t.Scope, t.TemplateKey) != nil {
+		writeError(c.w, http.StatusConflict, "conflict", "A template with the key already exists", nil)
+		return
+	}
+	for _, f := range t.Fields {
+		if !s.validField(c, f) {
+			return
+		}
+	}
+	if t.Fields == nil {
+		t.Fields = []*templateField{}
+	}
+	t.ID = s.nextID()
+	t.Type = "metadata_template"
+	s.templates[t.ID] = &t
+	s.templateOrder = append(s.templateOrder, t.ID)
+	s.addAdminEvent("METADATA_TEMPLATE_CREATE", c.userID, map[string]interface{}{"type": "metadata_template", "id": t.ID})
+	writeJSON(c.w, http.StatusCreated, &t)
+}
+
+// templateOfPath returns the template of /metadata_templates/:sub/:key/schema.
+func (s *Server) templateOfPath(c *call) (*metadataTemplate, bool) {
+	scope, ok := s.metadataScope(c, c.subID)
+	if !ok {
+		return nil, false
+	}
+	t := s.findTemplate(scope, c.key)
+	if t == nil {
+		writeError(c.w, http.StatusNotFound, "instance_tuple_not_found", "Template not found", nil)
+		return nil, false
+	}
+	return t, true
+}
+
+func (s *Server) getTemplate(c *call) {
+	if t, ok := s.templateOfPath(c); ok {
+		writeJSON(c.w, http.StatusOK, t)
+	}
+}
+
+func (s *Server) getTemplateByID(c *call) {
+	t, ok := s.templates[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, t)
+}
+
+func (s *Server) enterpriseTemplates(c *call) {
+	var entries []interface{}
+	for _, id := range s.templateOrder {
+		entries = append(entries, s.templates[id])
+	}
+	from, to, next, ok := markerPage(c, len(entries))
+	if !ok {
+		return
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{"next_marker": next, "entries": nonNil(entries[from:to])})
+}
+
+type templateOperation struct {
+	Op                   string          `json:"op"`
+	Data                 json.RawMessage `json:"data"`
+	FieldKey             string          `json:"fieldKey"`
+	EnumOptionKey        string          `json:"enumOptionKey"`
+	MultiSelectOptionKey string          `json:"multiSelectOptionKey"`
+}
+
+// applyTemplateOperations applies the operations to the copy of the template.
+// It returns the updated template and the renamed (old key -> new key, or "" if removed) fields.
+func (s *Server) applyTemplateOperations(c *call, src *metadataTemplate, ops []templateOperation) (*metadataTemplate, map[string]string, bool) {
+	b, _ := json.Marshal(src)
+	t := &metadataTemplate{}
+	_ = json.Unmarshal(b, t)
+	renamed := map[string]string{}
+
+	for _, op := range ops {
+		var f *templateField
+		if op.FieldKey != "" {
+			if f = t.field(op.FieldKey); f == nil {
+				badRequest(c, "Field '"+op.FieldKey+"' is not defined in the template")
+				return nil, nil, false
+			}
+		}
+		if f == nil && op.Op != "editTemplate" && op.Op != "addField" {
+			badRequest(c, "'fieldKey' is required for '"+op.Op+"'")
+			return nil, nil, false
+		}
+		switch op.Op {
+		case "editTemplate":
+			var data struct {
+				DisplayName            *string `json:"displayName"`
+				Hidden                 *bool   `json:"hidden"`
+				CopyInstanceOnItemCopy *bool   `json:"copyInstanceOnItemCopy"`
+			}
+			_ = json.Unmarshal(op.Data, &data)
+			if data.DisplayName != nil {
+				t.DisplayName = *data.DisplayName
+			}
+			if data.Hidden != nil {
+				t.Hidden = *data.Hidden
+			}
+			if data.CopyInstanceOnItemCopy != nil {
+				t.CopyInstanceOnItemCopy = *data.CopyInstanceOnItemCopy
+			}
+		case "addField":
+			added := &templateField{}
+			if err := json.Unmarshal(op.Data, added); err != nil || !s.validField(c, added) {
+				if err != nil {
+					badRequest(c, "Invalid 'data' of addField")
+				}
+				return nil, nil, false
+			}
+			if t.field(added.Key) != nil {
+				badRequest(c, "Field '"+added.Key+"' already exists")
+				return nil, nil, false
+			}
+			t.Fields = append(t.Fields, added)
+		case "editField":
+			var data struct {
+				Key         *string `json:"key"`
+				DisplayName *string `json:"displayName"`
+				Description *string `json:"description"`
+				Hidden      *bool   `json:"hidden"`
+			}
+			_ = json.Unmarshal(op.Data, &data)
+			if data.Key != nil && *data.Key != f.Key {
+				if t.field(*data.Key) != nil {
+					badRequest(c, "Field '"+*data.Key+"' already exists")
+					return nil, nil, false
+				}
+				renamed[f.Key] = *data.Key
+				f.Key = *data.Key
+			}
+			if data.DisplayName != nil {
+				f.DisplayName = *data.DisplayName
+			}
+			if data.Description != nil {
+				f.Description = *data.Description
+			}
+			if data.Hidden != nil {
+				f.Hidden = *data.Hidden
+			}
+		case "removeField":
+			for i, tf := range t.Fields {
+				if tf == f {
+					t.Fields = append(t.Fields[:i], t.Fields[i+1:]...)
+					break
+				}
+			}
+			renamed[f.Key] = ""
+		case "addEnumOption", "addMultiSelectOption":
+			var data struct {
+				Key string `json:"key"`
+			}
+			_ = json.Unmarshal(op.Data, &data)
+			if data.Key == "" || f.hasOption(data.Key) {
+				badRequest(c, "Invalid option '"+data.Key+"'")
+				return nil, nil, false
+			}
+			f.Options = append(f.Options, &templateOption{ID: s.nextID(), Key: data.Key})
+		case "removeEnumOption", "removeMultiSelectOption":
+			key := op.EnumOptionKey
+			if op.Op == "removeMultiSelectOption" {
+				key = op.MultiSelectOptionKey
+			}
+			removed := false
+			for i, o := range f.Options {
+				if o.Key == key {
+					f.Options = append(f.Options[:i], f.Options[i+1:]...)
+					removed = true
+					break
+				}
+			}
+			if !removed {
+				badRequest(c, "Option '"+key+"' is not defined in the field")
+				return nil, nil, false
+			}
+		default:
+			badRequest(c, "Invalid value '"+op.Op+"' for 'op'")
+			return nil, nil, false
+		}
+	}
+	return t, renamed, true
+}
+
+func (s *Server) updateTemplate(c *call) {
+	src, ok := s.templateOfPath(c)
+	if !ok {
+		return
+	}
+	if src == propertiesTemplate {
+		writeError(c.w, http.StatusForbidden, "forbidden", "The properties template can not be updated", nil)
+		return
+	}
+	var ops []templateOperation
+	if !c.decodeBody(&ops) {
+		return
+	}
+	// all or nothing
+	t, renamed, ok := s.applyTemplateOperations(c, src, ops)
+	if !ok {
+		return
+	}
+	*src = *t
+	if len(renamed) != 0 {
+		s.eachMetadataInstance(src.Scope, src.TemplateKey, func(it *item, i int) {
+			r := it.metadata[i]
+			for from, to := range renamed {
+				if v, exists := r[from]; exists {
+					delete(r, from)
+					if to != "" {
+						r[to] = v
+					}
+				}
+			}
+		})
+	}
+	s.addAdminEvent("METADATA_TEMPLATE_UPDATE", c.userID, map[string]interface{}{"type": "metadata_template", "id": src.ID})
+	writeJSON(c.w, http.StatusOK, src)
+}
+
+func (s *Server) deleteTemplate(c *call) {
+	t, ok := s.templateOfPath(c)
+	if !ok {
+		return
+	}
+	if t == propertiesTemplate {
+		writeError(c.w, http.StatusForbidden, "forbidden", "The properties template can not be deleted", nil)
+		return
+	}
+	// the instances and the cascade policies of the template are deleted too
+	var removed []*item
+	s.eachMetadataInstance(t.Scope, t.TemplateKey, func(it *item, i int) {
+		removed = append(removed, it)
+	})
+	for _, it := range removed {
+		i := metadataInstance(it, t.Scope, t.TemplateKey)
+		it.metadata = append(it.metadata[:i], it.metadata[i+1:]...)
+	}
+	for _, id := range append([]string(nil), s.policyOrder...) {
+		if p := s.policies[id]; p.str("scope") == t.Scope && p.str("templateKey") == t.TemplateKey {
+			s.removeCascadePolicy(id)
+		}
+	}
+	delete(s.templates, t.ID)
+	for i, id := range s.templateOrder {
+		if id == t.ID {
+			s.templateOrder = append(s.templateOrder[:i], s.templateOrder[i+1:]...)
+			break
+		}
+	}
+	s.addAdminEvent("METADATA_TEMPLATE_DELETE", c.userID, map[string]interface{}{"type": "metadata_template", "id": t.ID})
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// eachMetadataInstance calls fn for the instances of the template, with the item and the index of the instance.
+func (s *Server) eachMetadataInstance(scope string, templateKey string, fn func(it *item, i int)) {
+	for _, it := range s.items {
+		if i := metadataInstance(it, scope, templateKey); i >= 0 {
+			fn(it, i)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package boxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchRange parses the range parameter "lower,upper" of the search. Either bound can be empty.
+func searchRange(c *call, name string, parse func(string) (interface{}, error)) ([2]interface{}, bool) {
+	var r [2]interface{}
+	v := c.query.Get(name)
+	if v == "" {
+		return r, true
+	}
+	bounds := strings.Split(v, ",")
+	if len(bounds) != 2 {
+		badRequest(c, "Invalid value '"+v+"' for '"+name+"'")
+		return r, false
+	}
+	for i, b := range bounds {
+		if b == "" {
+			continue
+		}
+		parsed, err := parse(b)
+		if err != nil {
+			badRequest(c, "Invalid value '"+v+"' for '"+name+"'")
+			return r, false
+		}
+		r[i] = parsed
+	}
+	return r, true
+}
+
+func inTimeRange(t time.Time, r [2]interface{}) bool {
+	if from, ok := r[0].(time.Time); ok && t.Before(from) {
+		return false
+	}
+	if to, ok := r[1].(time.Time); ok && t.After(to) {
+		return false
+	}
+	return true
+}
+
+func listParam(c *call, name string) map[string]bool {
+	var r map[string]bool
+	for _, v := range strings.Split(c.query.Get(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			if r == nil {
+				r = map[string]bool{}
+			}
+			r[v] = true
+		}
+	}
+	return r
+}
+
+type metadataSearchFilter struct {
+	Scope       string                 `json:"scope"`
+	TemplateKey string                 `json:"templateKey"`
+	Filters     map[string]interface{} `json:"filters"`
+}
+
+// matchMetadataFilter reports whether the item has the instance matching the filter.
+// A filter value is the value to equal, a list of values to equal any of them, or the inclusive range {"gt": x, "lt": y}.
+func matchMetadataFilter(it *item, scope string, f metadataSearchFilter) bool {
+	i := metadataInstance(it, scope, f.TemplateKey)
+	if i < 0 {
+		return false
+	}
+	instance := it.metadata[i]
+	for field, v := range f.Filters {
+		value := instance[field]
+		switch fv := v.(type) {
+		case map[string]interface{}:
+			if gt, ok := fv["gt"]; ok {
+				if cmp, ok := compareMetadataValues(value, gt); !ok || cmp < 0 {
+					return false
+				}
+			}
+			if lt, ok := fv["lt"]; ok {
+				if cmp, ok := compareMetadataValues(value, lt); !ok || cmp > 0 {
+					return false
+				}
+			}
+		case []interface{}:
+			matched := false
+			for _, alt := range fv {
+				matched = matched || metadataValueEqual(value, alt)
+			}
+			if !matched {
+				return false
+			}
+		default:
+			if !metadataValueEqual(value, fv) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// searchText returns the texts of the item searched for the content types.
+func (s *Server) searchText(it *item, contentTypes map[string]bool) []string {
+	all := contentTypes == nil
+	var texts []string
+	if all || contentTypes["name"] {
+		texts = append(texts, it.name)
+	}
+	if all || contentTypes["description"] {
+		texts = append(texts, it.description)
+	}
+	if all || contentTypes["tags"] {
+		texts = append(texts, it.tags...)
+	}
+	if it.typ == "file" {
+		if all || contentTypes["file_content"] {
+			texts = append(texts, string(it.current().content))
+		}
+		if all || contentTypes["comments"] {
+			for _, cm := range s.commentsOf(it.id) {
+				texts = append(texts, cm.str("message"))
+			}
+		}
+	}
+	return texts
+}
+
+// search finds the items by the query (all the terms must appear, case insensitive) and the filters.
+// The relevance is not emulated, the results are ordered by id unless sorted by modified_at.
+func (s *Server) search(c *call) {
+	q := c.query
+	terms := strings.Fields(strings.ToLower(strings.NewReplacer(`"`, " ").Replace(q.Get("query"))))
+
+	var mdFilters []metadataSearchFilter
+	if v := q.Get("mdfilters"); v != "" {
+		if err := json.Unmarshal([]byte(v), &mdFilters); err != nil || len(mdFilters) != 1 {
+			badRequest(c, "Invalid value '"+v+"' for 'mdfilters'")
+			return
+		}
+	}
+	if len(terms) == 0 && len(mdFilters) == 0 {
+		badRequest(c, "'query' or 'mdfilters' is required")
+		return
+	}
+	var mdScopes []string
+	for _, f := range mdFilters {
+		scope, ok := s.metadataScope(c, f.Scope)
+		if !ok {
+			return
+		}
+		mdScopes = append(mdScopes, scope)
+	}
+	parseTime := func(v string) (interface{}, error) { return time.Parse(time.RFC3339, v) }
+	parseSize := func(v string) (interface{}, error) { return strconv.Atoi(v) }
+	createdAt, ok := searchRange(c, "created_at_range", parseTime)
+	if !ok {
+		return
+	}
+	updatedAt, ok := searchRange(c, "updated_at_range", parseTime)
+	if !ok {
+		return
+	}
+	size, ok := searchRange(c, "size_range", parseSize)
+	if !ok {
+		return
+	}
+	typ := q.Get("type")
+	if typ != "" && typ != "file" && typ != "folder" && typ != "web_link" {
+		badRequest(c, "Invalid value '"+typ+"' for 'type'")
+		return
+	}
+	trash := q.Get("trash_content")
+	switch trash {
+	case "":
+		trash = "non_trashed_only"
+	case "non_trashed_only", "trashed_only", "all_items":
+	default:
+		badRequest(c, "Invalid value '"+trash+"' for 'trash_content'")
+		return
+	}
+	extensions := listParam(c, "file_extensions")
+	owners := listParam(c, "owner_user_ids")
+	ancestors := listParam(c, "ancestor_folder_ids")
+	contentTypes := listParam(c, "content_types")
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	var hits []*item
+Items:
+	for _, it := range s.items {
+		if it.id == "0" || (typ != "" && it.typ != typ) || (owners != nil && !owners[it.ownerID]) {
+			continue
+		}
+		if trashed := s.isTrashed(it); (trash == "non_trashed_only" && trashed) || (trash == "trashed_only" && !trashed) {
+			continue
+		}
+		if extensions != nil && (it.typ != "file" || !extensions[strings.TrimPrefix(path.Ext(it.name), ".")]) {
+			continue
+		}
+		if !inTimeRange(it.createdAt, createdAt) || !inTimeRange(it.modifiedAt, updatedAt) {
+			continue
+		}
+		if lower, ok := size[0].(int); ok && s.size(it) < lower {
+			continue
+		}
+		if upper, ok := size[1].(int); ok && s.size(it) > upper {
+			continue
+		}
+		if ancestors != nil {
+			found := false
+			for id := range ancestors {
+				found = found || (id != it.id && s.isAncestor(id, it))
+			}
+			if !found {
+				continue
+			}
+		}
+		for i, f := range mdFilters {
+			if !matchMetadataFilter(it, mdScopes[i], f) {
+				continue Items
+			}
+		}
+		text := strings.ToLower(strings.Join(s.searchText(it, contentTypes), "\n"))
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				continue Items
+			}
+		}
+		hits = append(hits, it)
+	}
+
+	desc := !strings.EqualFold(q.Get("direction"), "ASC")
+	sort.Slice(hits, func(i, j int) bool {
+		if q.Get("sort") == "modified_at" && !hits[i].modifiedAt.Equal(hits[j].modifiedAt) {
+			return hits[i].modifiedAt.After(hits[j].modifiedAt) == desc
+		}
+		return lessID(hits[i].id, hits[j].id)
+	})
+	from, to := page(len(hits), offset, limit)
+	var entries []interface{}
+	for _, it := range hits[from:to] {
+		entries = append(entries, s.full(it))
+	}
+	writeJSON(c.w, http.StatusOK, collection(entries, len(hits), offset, limit))
+}
@@ -0,0 +1,311 @@
+package boxtest
+
+import (
+	"net/http"
+)
+
+type task struct {
+	record
+	fileID        string
+	assignmentIDs []string
+}
+
+type taskAssignment struct {
+	record
+	taskID string
+}
+
+// resolutionStates are the resolution states allowed for each action of a task.
+var resolutionStates = map[string][]string{
+	"review":   {"incomplete", "approved", "rejected"},
+	"complete": {"incomplete", "completed"},
+}
+
+// renderTask returns the task with its assignments and completion state.
+func (s *Server) renderTask(t *task) record {
+	r := record{}
+	for k, v := range t.record {
+		r[k] = v
+	}
+	entries := []interface{}{}
+	resolved := 0
+	for _, id := range t.assignmentIDs {
+		a := s.assignments[id]
+		entries = append(entries, a.record)
+		if a.str("resolution_state") != "incomplete" {
+			resolved++
+		}
+	}
+	r["task_assignment_collection"] = map[string]interface{}{"total_count": len(entries), "entries": entries}
+	if t.str("completion_rule") == "any_assignee" {
+		r["is_completed"] = resolved > 0
+	} else {
+		r["is_completed"] = resolved > 0 && resolved == len(entries)
+	}
+	return r
+}
+
+func (s *Server) getTask(c *call) {
+	t, ok := s.tasks[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, s.renderTask(t))
+}
+
+type taskBody struct {
+	Action         string `json:"action"`
+	Message        string `json:"message"`
+	DueAt          string `json:"due_at"`
+	CompletionRule string `json:"completion_rule"`
+}
+
+func (b *taskBody) valid(c *call) bool {
+	if b.Action != "" {
+		if _, ok := resolutionStates[b.Action]; !ok {
+			badRequest(c, "Invalid value '"+b.Action+"' for 'action'")
+			return false
+		}
+	}
+	if b.CompletionRule != "" && b.CompletionRule != "all_assignees" && b.CompletionRule != "any_assignee" {
+		badRequest(c, "Invalid value '"+b.CompletionRule+"' for 'completion_rule'")
+		return false
+	}
+	return true
+}
+
+func (s *Server) createTask(c *call) {
+	var body struct {
+		taskBody
+		Item *struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		} `json:"item"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Item == nil {
+		badRequest(c, "'item' is required")
+		return
+	}
+	if !body.valid(c) {
+		return
+	}
+	it, ok := s.liveItem(c, "file", body.Item.ID)
+	if !ok {
+		return
+	}
+	if body.Action == "" {
+		body.Action = "review"
+	}
+	if body.CompletionRule == "" {
+		body.CompletionRule = "all_assignees"
+	}
+	var dueAt interface{}
+	if body.DueAt != "" {
+		dueAt = body.DueAt
+	}
+	t := &task{
+		record: record{
+			"type":            "task",
+			"id":              s.nextID(),
+			"item":            s.mini(it),
+			"due_at":          dueAt,
+			"action":          body.Action,
+			"message":         body.Message,
+			"created_by":      s.userMini(c.userID),
+			"created_at":      formatTime(s.now()),
+			"completion_rule": body.CompletionRule,
+		},
+		fileID: it.id,
+	}
+	s.tasks[t.id()] = t
+	s.taskOrder = append(s.taskOrder, t.id())
+	r := s.renderTask(t)
+	s.addEvent("TASK_CREATE", c.userID, r)
+	writeJSON(c.w, http.StatusCreated, r)
+}
+
+func (s *Server) updateTask(c *call) {
+	t, ok := s.tasks[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	var body taskBody
+	if !c.decodeBody(&body) || !body.valid(c) {
+		return
+	}
+	if body.Action != "" {
+		t.record["action"] = body.Action
+	}
+	if body.Message != "" {
+		t.record["message"] = body.Message
+	}
+	if body.DueAt != "" {
+		t.record["due_at"] = body.DueAt
+	}
+	if body.CompletionRule != "" {
+		t.record["completion_rule"] = body.CompletionRule
+	}
+	writeJSON(c.w, http.StatusOK, s.renderTask(t))
+}
+
+func (s *Server) deleteTask(c *call) {
+	t, ok := s.tasks[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	for _, id := range t.assignmentIDs {
+		delete(s.assignments, id)
+	}
+	delete(s.tasks, c.id)
+	for i, id := range s.taskOrder {
+		if id == c.id {
+			s.taskOrder = append(s.taskOrder[:i], s.taskOrder[i+1:]...)
+			break
+		}
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) fileTasks(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok {
+		return
+	}
+	entries := []interface{}{}
+	for _, id := range s.taskOrder {
+		if t := s.tasks[id]; t.fileID == it.id {
+			entries = append(entries, s.renderTask(t))
+		}
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{"total_count": len(entries), "entries": entries})
+}
+
+func (s *Server) taskAssignmentList(c *call) {
+	t, ok := s.tasks[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, s.renderTask(t)["task_assignment_collection"])
+}
+
+func (s *Server) getTaskAssignment(c *call) {
+	a, ok := s.assignments[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, a.record)
+}
+
+func (s *Server) createTaskAssignment(c *call) {
+	var body struct {
+		Task *struct {
+			ID string `json:"id"`
+		} `json:"task"`
+		AssignTo *struct {
+			ID    string `json:"id"`
+			Login string `json:"login"`
+		} `json:"assign_to"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Task == nil || body.AssignTo == nil || (body.AssignTo.ID == "" && body.AssignTo.Login == "") {
+		badRequest(c, "'task' and 'assign_to' with 'id' or 'login' are required")
+		return
+	}
+	t, ok := s.tasks[body.Task.ID]
+	if !ok {
+		notFound(c)
+		return
+	}
+	userID := body.AssignTo.ID
+	if userID == "" {
+		if u := s.findUserByLogin(body.AssignTo.Login); u != nil {
+			userID = u.id()
+		}
+	}
+	if _, ok := s.users[userID]; !ok {
+		notFound(c)
+		return
+	}
+
+	a := &taskAssignment{
+		record: record{
+			"type":             "task_assignment",
+			"id":               s.nextID(),
+			"item":             t.record["item"],
+			"assigned_to":      s.userMini(userID),
+			"message":          "",
+			"completed_at":     nil,
+			"assigned_at":      formatTime(s.now()),
+			"reminded_at":      nil,
+			"resolution_state": "incomplete",
+			"assigned_by":      s.userMini(c.userID),
+		},
+		taskID: t.id(),
+	}
+	s.assignments[a.id()] = a
+	t.assignmentIDs = append(t.assignmentIDs, a.id())
+	s.addEvent("TASK_ASSIGNMENT_CREATE", c.userID, a.record)
+	writeJSON(c.w, http.StatusCreated, a.record)
+}
+
+func (s *Server) updateTaskAssignment(c *call) {
+	a, ok := s.assignments[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	var body struct {
+		Message         string `json:"message"`
+		ResolutionState string `json:"resolution_state"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.ResolutionState != "" {
+		valid := false
+		for _, state := range resolutionStates[s.tasks[a.taskID].str("action")] {
+			valid = valid || state == body.ResolutionState
+		}
+		if !valid {
+			badRequest(c, "Invalid value '"+body.ResolutionState+"' for 'resolution_state'")
+			return
+		}
+		a.record["resolution_state"] = body.ResolutionState
+		if body.ResolutionState == "incomplete" {
+			a.record["completed_at"] = nil
+		} else {
+			a.record["completed_at"] = formatTime(s.now())
+		}
+	}
+	if body.Message != "" {
+		a.record["message"] = body.Message
+	}
+	writeJSON(c.w, http.StatusOK, a.record)
+}
+
+func (s *Server) deleteTaskAssignment(c *call) {
+	a, ok := s.assignments[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	t := s.tasks[a.taskID]
+	for i, id := range t.assignmentIDs {
+		if id == c.id {
+			t.assignmentIDs = append(t.assignmentIDs[:i], t.assignmentIDs[i+1:]...)
+			break
+		}
+	}
+	delete(s.assignments, c.id)
+	c.w.WriteHeader(http.StatusNoContent)
+}
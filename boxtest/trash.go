@@ -0,0 +1,130 @@
+package boxtest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trashedItem returns the item moved to the trash itself, or writes 404.
+// The items in a trashed folder can not be retrieved from the trash.
+func (s *Server) trashedItem(c *call, typ string, id string) (*item, bool) {
+	it, ok := s.items[id]
+	if !ok || it.typ != typ || it.trashedAt.IsZero() {
+		writeError(c.w, http.StatusNotFound, "not_found", "Trashed item not found", nil)
+		return nil, false
+	}
+	return it, true
+}
+
+func (s *Server) trashedItems(c *call) {
+	var trashed []*item
+	for _, it := range s.items {
+		if !it.trashedAt.IsZero() {
+			trashed = append(trashed, it)
+		}
+	}
+	var less func(a, b *item) bool
+	switch by := c.query.Get("sort"); by {
+	case "", "id":
+		less = func(a, b *item) bool { return lessID(a.id, b.id) }
+	case "name":
+		less = func(a, b *item) bool { return strings.ToLower(a.name) < strings.ToLower(b.name) }
+	case "date":
+		less = func(a, b *item) bool { return a.modifiedAt.Before(b.modifiedAt) }
+	case "size":
+		less = func(a, b *item) bool { return s.size(a) < s.size(b) }
+	default:
+		badRequest(c, "Invalid value '"+by+"' for 'sort'")
+		return
+	}
+	desc := strings.EqualFold(c.query.Get("direction"), "DESC")
+	sort.SliceStable(trashed, func(i, j int) bool {
+		if desc {
+			return less(trashed[j], trashed[i])
+		}
+		return less(trashed[i], trashed[j])
+	})
+
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	from, to := page(len(trashed), offset, limit)
+	var entries []interface{}
+	for _, it := range trashed[from:to] {
+		entries = append(entries, s.full(it))
+	}
+	writeJSON(c.w, http.StatusOK, collection(entries, len(trashed), offset, limit))
+}
+
+func (s *Server) getTrashedItem(c *call) {
+	if it, ok := s.trashedItem(c, c.itemType(), c.id); ok {
+		writeJSON(c.w, http.StatusOK, s.full(it))
+	}
+}
+
+// restoreItem restores the item (and the items in it) to the original or the given parent folder, optionally renamed.
+func (s *Server) restoreItem(c *call) {
+	it, ok := s.trashedItem(c, c.itemType(), c.id)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name   *string  `json:"name"`
+		Parent *itemRef `json:"parent"`
+	}
+	b, _ := ioutil.ReadAll(c.r.Body)
+	if len(b) != 0 {
+		if err := json.Unmarshal(b, &body); err != nil {
+			badRequest(c, "Invalid JSON body: "+err.Error())
+			return
+		}
+	}
+	name := it.name
+	if body.Name != nil {
+		if !validateName(c, *body.Name) {
+			return
+		}
+		name = *body.Name
+	}
+	parentID := it.parentID
+	if body.Parent != nil {
+		parentID = body.Parent.ID
+	}
+	// the original parent may be trashed or deleted permanently
+	if _, ok := s.liveItem(c, "folder", parentID); !ok {
+		return
+	}
+	if conflict := s.findConflict(parentID, name, it.id); conflict != nil {
+		s.writeConflict(c, conflict)
+		return
+	}
+	it.trashedAt = time.Time{}
+	it.name = name
+	it.parentID = parentID
+	s.touch(it, c.userID)
+	s.addEvent("ITEM_UNDELETE_VIA_TRASH", c.userID, s.full(it))
+	writeJSON(c.w, http.StatusCreated, s.full(it))
+}
+
+// purgeItem deletes the item in the trash and the items in it permanently.
+func (s *Server) purgeItem(c *call) {
+	it, ok := s.trashedItem(c, c.itemType(), c.id)
+	if !ok {
+		return
+	}
+	var purged []string
+	for id, descendant := range s.items {
+		if s.isAncestor(it.id, descendant) {
+			purged = append(purged, id)
+		}
+	}
+	for _, id := range purged {
+		delete(s.items, id)
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,491 @@
+package boxtest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// record is the json representation of a user, group, membership or collaboration.
+type record map[string]interface{}
+
+func (r record) id() string {
+	id, _ := r["id"].(string)
+	return id
+}
+
+func (r record) str(key string) string {
+	v, _ := r[key].(string)
+	return v
+}
+
+// merge copies the fields of body into the record, except read only fields.
+func (r record) merge(body map[string]interface{}, readOnly ...string) {
+	for k, v := range body {
+		skip := k == "type" || k == "id"
+		for _, ro := range readOnly {
+			skip = skip || k == ro
+		}
+		if !skip {
+			r[k] = v
+		}
+	}
+}
+
+func (s *Server) userMini(id string) interface{} {
+	if id == "" {
+		return nil
+	}
+	u, ok := s.users[id]
+	if !ok {
+		return map[string]interface{}{"type": "user", "id": id}
+	}
+	return map[string]interface{}{"type": "user", "id": id, "name": u["name"], "login": u["login"]}
+}
+
+func (s *Server) groupMini(id string) interface{} {
+	g, ok := s.groups[id]
+	if !ok {
+		return map[string]interface{}{"type": "group", "id": id}
+	}
+	return map[string]interface{}{"type": "group", "id": id, "name": g["name"]}
+}
+
+func (s *Server) newUser(body map[string]interface{}) record {
+	id := s.nextID()
+	now := formatTime(s.now())
+	u := record{
+		"type":                              "user",
+		"id":                                id,
+		"name":                              "",
+		"login":                             "",
+		"created_at":                        now,
+		"modified_at":                       now,
+		"language":                          "en",
+		"timezone":                          "America/Los_Angeles",
+		"space_amount":                      10737418240,
+		"space_used":                        0,
+		"max_upload_size":                   5368709120,
+		"status":                            "active",
+		"job_title":                         "",
+		"phone":                             "",
+		"address":                           "",
+		"avatar_url":                        "",
+		"role":                              "user",
+		"tracking_codes":                    []interface{}{},
+		"can_see_managed_users":             false,
+		"is_sync_enabled":                   true,
+		"is_external_collab_restricted":     false,
+		"is_exempt_from_device_limits":      false,
+		"is_exempt_from_login_verification": false,
+		"is_platform_access_only":           false,
+	}
+	u.merge(body, "notify")
+	s.users[id] = u
+	s.userOrder = append(s.userOrder, id)
+	return u
+}
+
+func (s *Server) getCurrentUser(c *call) {
+	writeJSON(c.w, http.StatusOK, s.users[c.userID])
+}
+
+func (s *Server) getUser(c *call) {
+	u, ok := s.users[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, u)
+}
+
+func (s *Server) findUserByLogin(login string) record {
+	for _, id := range s.userOrder {
+		if u := s.users[id]; strings.EqualFold(u.str("login"), login) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) createUser(c *call) {
+	var body map[string]interface{}
+	if !c.decodeBody(&body) {
+		return
+	}
+	name, _ := body["name"].(string)
+	login, _ := body["login"].(string)
+	appUser, _ := body["is_platform_access_only"].(bool)
+	if name == "" {
+		badRequest(c, "'name' is required")
+		return
+	}
+	if !appUser {
+		if login == "" {
+			badRequest(c, "'login' is required")
+			return
+		}
+		if s.findUserByLogin(login) != nil {
+			writeError(c.w, http.StatusConflict, "user_login_already_used", "User with the specified login already exists", nil)
+			return
+		}
+	} else if login == "" {
+		body["login"] = "AppUser_" + s.nextID() + "@boxdevedition.com"
+	}
+	u := s.newUser(body)
+	s.addAdminEvent("NEW_USER", c.userID, s.userMini(u.id()))
+	writeJSON(c.w, http.StatusCreated, u)
+}
+
+func (s *Server) updateUser(c *call) {
+	u, ok := s.users[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	var body map[string]interface{}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if login, ok := body["login"].(string); ok {
+		if other := s.findUserByLogin(login); other != nil && other.id() != u.id() {
+			writeError(c.w, http.StatusConflict, "user_login_already_used", "User with the specified login already exists", nil)
+			return
+		}
+	}
+	u.merge(body, "notify", "created_at", "space_used")
+	u["modified_at"] = formatTime(s.now())
+	s.addAdminEvent("EDIT_USER", c.userID, s.userMini(u.id()))
+	writeJSON(c.w, http.StatusOK, u)
+}
+
+func (s *Server) deleteUser(c *call) {
+	u, ok := s.users[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	if c.query.Get("force") != "true" {
+		for _, it := range s.items {
+			if it.ownerID == u.id() && it.id != "0" && !s.isTrashed(it) {
+				writeError(c.w, http.StatusBadRequest, "user_delete_items_exist", "User has items and force is not set", nil)
+				return
+			}
+		}
+	}
+	for _, id := range append([]string(nil), s.membershipOrd...) {
+		if m := s.memberships[id]; m != nil && miniID(m["user"]) == u.id() {
+			s.removeMembership(id)
+		}
+	}
+	delete(s.users, u.id())
+	s.userOrder = removeID(s.userOrder, u.id())
+	s.addAdminEvent("DELETE_USER", c.userID, map[string]interface{}{"type": "user", "id": u.id(), "name": u["name"], "login": u["login"]})
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listUsers(c *call) {
+	filter := strings.ToLower(c.query.Get("filter_term"))
+	userType := c.query.Get("user_type")
+	switch userType {
+	case "", "all", "managed", "external":
+	default:
+		badRequest(c, "Invalid value '"+userType+"' for 'user_type'")
+		return
+	}
+	externalAppUserID := c.query.Get("external_app_user_id")
+	var matched []interface{}
+	for _, id := range s.userOrder {
+		u := s.users[id]
+		if filter != "" && !strings.HasPrefix(strings.ToLower(u.str("login")), filter) && !strings.HasPrefix(strings.ToLower(u.str("name")), filter) {
+			continue
+		}
+		// every user of the emulator is a managed user
+		if userType == "external" {
+			continue
+		}
+		if externalAppUserID != "" && u.str("external_app_user_id") != externalAppUserID {
+			continue
+		}
+		matched = append(matched, u)
+	}
+	if c.query.Get("usemarker") == "true" {
+		from, to, next, ok := markerPage(c, len(matched))
+		if !ok {
+			return
+		}
+		writeJSON(c.w, http.StatusOK, map[string]interface{}{"entries": nonNil(matched[from:to]), "next_marker": next})
+		return
+	}
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	from, to := page(len(matched), offset, limit)
+	writeJSON(c.w, http.StatusOK, collection(matched[from:to], len(matched), offset, limit))
+}
+
+func (s *Server) getGroup(c *call) {
+	g, ok := s.groups[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, g)
+}
+
+func (s *Server) findGroupByName(name string) record {
+	for _, id := range s.groupOrder {
+		if g := s.groups[id]; strings.EqualFold(g.str("name"), name) {
+			return g
+		}
+	}
+	return nil
+}
+
+func (s *Server) createGroup(c *call) {
+	var body map[string]interface{}
+	if !c.decodeBody(&body) {
+		return
+	}
+	name, _ := body["name"].(string)
+	if name == "" {
+		badRequest(c, "'name' is required")
+		return
+	}
+	if s.findGroupByName(name) != nil {
+		writeError(c.w, http.StatusConflict, "conflict", "Group with the specified name already exists", nil)
+		return
+	}
+	now := formatTime(s.now())
+	g := record{
+		"type":                     "group",
+		"id":                       s.nextID(),
+		"created_at":               now,
+		"modified_at":              now,
+		"provenance":               "",
+		"external_sync_identifier": "",
+		"description":              "",
+		"invitability_level":       "admins_only",
+		"member_viewability_level": "admins_only",
+		"group_type":               "managed_group",
+	}
+	g.merge(body)
+	s.groups[g.id()] = g
+	s.groupOrder = append(s.groupOrder, g.id())
+	s.addAdminEvent("GROUP_CREATION", c.userID, s.groupMini(g.id()))
+	writeJSON(c.w, http.StatusCreated, g)
+}
+
+func (s *Server) updateGroup(c *call) {
+	g, ok := s.groups[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	var body map[string]interface{}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if name, ok := body["name"].(string); ok {
+		if other := s.findGroupByName(name); other != nil && other.id() != g.id() {
+			writeError(c.w, http.StatusConflict, "conflict", "Group with the specified name already exists", nil)
+			return
+		}
+	}
+	g.merge(body, "created_at", "group_type")
+	g["modified_at"] = formatTime(s.now())
+	s.addAdminEvent("GROUP_EDITED", c.userID, s.groupMini(g.id()))
+	writeJSON(c.w, http.StatusOK, g)
+}
+
+func (s *Server) deleteGroup(c *call) {
+	g, ok := s.groups[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	for _, id := range append([]string(nil), s.membershipOrd...) {
+		if m := s.memberships[id]; m != nil && miniID(m["group"]) == g.id() {
+			s.removeMembership(id)
+		}
+	}
+	for _, id := range append([]string(nil), s.collabOrder...) {
+		if collab := s.collaborations[id]; collab != nil && miniID(collab["accessible_by"]) == g.id() {
+			s.removeCollaboration(id)
+		}
+	}
+	s.addAdminEvent("GROUP_DELETION", c.userID, s.groupMini(g.id()))
+	delete(s.groups, g.id())
+	s.groupOrder = removeID(s.groupOrder, g.id())
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listGroups(c *call) {
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	filter := strings.ToLower(c.query.Get("name"))
+	if filter == "" {
+		filter = strings.ToLower(c.query.Get("filter_term"))
+	}
+	var matched []interface{}
+	for _, id := range s.groupOrder {
+		g := s.groups[id]
+		if filter != "" && !strings.HasPrefix(strings.ToLower(g.str("name")), filter) {
+			continue
+		}
+		matched = append(matched, g)
+	}
+	from, to := page(len(matched), offset, limit)
+	writeJSON(c.w, http.StatusOK, collection(matched[from:to], len(matched), offset, limit))
+}
+
+func (s *Server) getMembership(c *call) {
+	m, ok := s.memberships[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, m)
+}
+
+func (s *Server) createMembership(c *call) {
+	var body struct {
+		User                    *itemRef               `json:"user"`
+		Group                   *itemRef               `json:"group"`
+		Role                    string                 `json:"role"`
+		ConfigurablePermissions map[string]interface{} `json:"configurable_permissions"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.User == nil || body.Group == nil {
+		badRequest(c, "'user' and 'group' are required")
+		return
+	}
+	if _, ok := s.users[body.User.ID]; !ok {
+		notFound(c)
+		return
+	}
+	if _, ok := s.groups[body.Group.ID]; !ok {
+		notFound(c)
+		return
+	}
+	for _, id := range s.membershipOrd {
+		m := s.memberships[id]
+		if miniID(m["user"]) == body.User.ID && miniID(m["group"]) == body.Group.ID {
+			writeError(c.w, http.StatusConflict, "conflict", "User is already a member of the group", nil)
+			return
+		}
+	}
+	if body.Role == "" {
+		body.Role = "member"
+	}
+	now := formatTime(s.now())
+	m := record{
+		"type":        "group_membership",
+		"id":          s.nextID(),
+		"user":        s.userMini(body.User.ID),
+		"group":       s.groupMini(body.Group.ID),
+		"role":        body.Role,
+		"created_at":  now,
+		"modified_at": now,
+	}
+	if body.ConfigurablePermissions != nil {
+		m["configurable_permissions"] = body.ConfigurablePermissions
+	}
+	s.memberships[m.id()] = m
+	s.membershipOrd = append(s.membershipOrd, m.id())
+	s.addAdminEvent("GROUP_ADD_USER", c.userID, s.groupMini(body.Group.ID))
+	writeJSON(c.w, http.StatusCreated, m)
+}
+
+func (s *Server) updateMembership(c *call) {
+	m, ok := s.memberships[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	var body map[string]interface{}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if role, ok := body["role"]; ok {
+		m["role"] = role
+	}
+	if perm, ok := body["configurable_permissions"]; ok {
+		m["configurable_permissions"] = perm
+	}
+	m["modified_at"] = formatTime(s.now())
+	writeJSON(c.w, http.StatusOK, m)
+}
+
+func (s *Server) deleteMembership(c *call) {
+	m, ok := s.memberships[c.id]
+	if !ok {
+		notFound(c)
+		return
+	}
+	s.addAdminEvent("GROUP_REMOVE_USER", c.userID, s.groupMini(miniID(m["group"])))
+	s.removeMembership(m.id())
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeMembership(id string) {
+	delete(s.memberships, id)
+	s.membershipOrd = removeID(s.membershipOrd, id)
+}
+
+func (s *Server) groupMemberships(c *call) {
+	if _, ok := s.groups[c.id]; !ok {
+		notFound(c)
+		return
+	}
+	s.listMemberships(c, "group")
+}
+
+func (s *Server) userMemberships(c *call) {
+	if _, ok := s.users[c.id]; !ok {
+		notFound(c)
+		return
+	}
+	s.listMemberships(c, "user")
+}
+
+func (s *Server) listMemberships(c *call, key string) {
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	var matched []interface{}
+	for _, id := range s.membershipOrd {
+		if m := s.memberships[id]; miniID(m[key]) == c.id {
+			matched = append(matched, m)
+		}
+	}
+	from, to := page(len(matched), offset, limit)
+	writeJSON(c.w, http.StatusOK, collection(matched[from:to], len(matched), offset, limit))
+}
+
+// miniID returns the id of the mini representation (a map or a record).
+func miniID(v interface{}) string {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		id, _ := m["id"].(string)
+		return id
+	case record:
+		return m.id()
+	}
+	return ""
+}
+
+func removeID(ids []string, id string) []string {
+	r := ids[:0]
+	for _, v := range ids {
+		if v != id {
+			r = append(r, v)
+		}
+	}
+	return r
+}
@@ -0,0 +1,140 @@
+package boxtest
+
+import (
+	"net/http"
+	"time"
+)
+
+func (s *Server) versionJSON(it *item, v *version) map[string]interface{} {
+	return map[string]interface{}{
+		"type":                  "file_version",
+		"id":                    v.id,
+		"sha1":                  v.sha1,
+		"name":                  it.name,
+		"size":                  len(v.content),
+		"created_at":            formatTime(v.createdAt),
+		"modified_at":           formatTime(v.createdAt),
+		"modified_by":           s.userMini(v.modifiedByID),
+		"trashed_at":            formatTime(v.trashedAt),
+		"trashed_by":            s.userMini(v.trashedByID),
+		"restored_at":           formatTime(v.restoredAt),
+		"restored_by":           s.userMini(v.restoredByID),
+		"purged_at":             nil,
+		"uploader_display_name": s.users[v.modifiedByID].str("name"),
+	}
+}
+
+// version returns the version of the file, or writes 404.
+func (s *Server) version(c *call, it *item, versionID string) (*version, bool) {
+	for _, v := range it.versions {
+		if v.id == versionID {
+			return v, true
+		}
+	}
+	notFound(c)
+	return nil, false
+}
+
+func (s *Server) listVersions(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok {
+		return
+	}
+	offset, limit, ok := pagination(c)
+	if !ok {
+		return
+	}
+	// past versions, newest first
+	var entries []interface{}
+	for i := len(it.versions) - 2; i >= 0; i-- {
+		entries = append(entries, s.versionJSON(it, it.versions[i]))
+	}
+	from, to := page(len(entries), offset, limit)
+	writeJSON(c.w, http.StatusOK, collection(entries[from:to], len(entries), offset, limit))
+}
+
+func (s *Server) getVersion(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok {
+		return
+	}
+	v, ok := s.version(c, it, c.subID)
+	if !ok {
+		return
+	}
+	writeJSON(c.w, http.StatusOK, s.versionJSON(it, v))
+}
+
+func (s *Server) promoteVersion(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok {
+		return
+	}
+	var body struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Type != "file_version" {
+		badRequest(c, "'type' must be 'file_version'")
+		return
+	}
+	v, ok := s.version(c, it, body.ID)
+	if !ok {
+		return
+	}
+	if !v.trashedAt.IsZero() {
+		badRequest(c, "The version is trashed")
+		return
+	}
+	promoted := s.newVersion(v.content, c.userID)
+	it.versions = append(it.versions, promoted)
+	s.touch(it, c.userID)
+	writeJSON(c.w, http.StatusCreated, s.versionJSON(it, promoted))
+}
+
+func (s *Server) deleteVersion(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok || !checkIfMatch(c, it) {
+		return
+	}
+	v, ok := s.version(c, it, c.subID)
+	if !ok {
+		return
+	}
+	if v == it.current() {
+		badRequest(c, "The current version can not be deleted")
+		return
+	}
+	v.trashedAt = s.now()
+	v.trashedByID = c.userID
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) restoreVersion(c *call) {
+	it, ok := s.liveItem(c, "file", c.id)
+	if !ok {
+		return
+	}
+	var body map[string]interface{}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if trashedAt, ok := body["trashed_at"]; !ok || trashedAt != nil {
+		badRequest(c, "'trashed_at' must be null")
+		return
+	}
+	v, ok := s.version(c, it, c.subID)
+	if !ok {
+		return
+	}
+	if !v.trashedAt.IsZero() {
+		v.trashedAt = time.Time{}
+		v.trashedByID = ""
+		v.restoredAt = s.now()
+		v.restoredByID = c.userID
+	}
+	writeJSON(c.w, http.StatusOK, s.versionJSON(it, v))
+}
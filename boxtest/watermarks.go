@@ -0,0 +1,71 @@
+package boxtest
+
+import (
+	"net/http"
+)
+
+// isWatermarked reports whether the item or any of its ancestors is watermarked.
+func (s *Server) isWatermarked(it *item) bool {
+	for it != nil {
+		if it.watermark != nil {
+			return true
+		}
+		if it.id == "0" {
+			return false
+		}
+		it = s.items[it.parentID]
+	}
+	return false
+}
+
+func (s *Server) getWatermark(c *call) {
+	it, ok := s.liveItem(c, c.itemType(), c.id)
+	if !ok {
+		return
+	}
+	if it.watermark == nil {
+		notFound(c)
+		return
+	}
+	writeJSON(c.w, http.StatusOK, map[string]interface{}{"watermark": it.watermark})
+}
+
+func (s *Server) applyWatermark(c *call) {
+	it, ok := s.liveItem(c, c.itemType(), c.id)
+	if !ok {
+		return
+	}
+	var body struct {
+		Watermark *struct {
+			Imprint string `json:"imprint"`
+		} `json:"watermark"`
+	}
+	if !c.decodeBody(&body) {
+		return
+	}
+	if body.Watermark == nil || body.Watermark.Imprint != "default" {
+		badRequest(c, "'watermark.imprint' must be 'default'")
+		return
+	}
+	now := formatTime(s.now())
+	status := http.StatusOK
+	if it.watermark == nil {
+		it.watermark = map[string]interface{}{"created_at": now}
+		status = http.StatusCreated
+	}
+	it.watermark["modified_at"] = now
+	writeJSON(c.w, status, map[string]interface{}{"watermark": it.watermark})
+}
+
+func (s *Server) removeWatermark(c *call) {
+	it, ok := s.liveItem(c, c.itemType(), c.id)
+	if !ok {
+		return
+	}
+	if it.watermark == nil {
+		notFound(c)
+		return
+	}
+	it.watermark = nil
+	c.w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,335 @@
+package goboxer
+
+import (
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const httpHeaderIfNoneMatch = "If-None-Match"
+
+// CachedResponse is the response stored in CacheBackend.
+type CachedResponse struct {
+	ETag     string
+	Headers  http.Header
+	Body     []byte
+	StoredAt time.Time
+}
+
+// CacheBackend stores the responses cached by ResponseCache.
+//
+// Implementations must be safe for concurrent use.
+type CacheBackend interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+	// DeletePrefix deletes the entries whose key starts with prefix.
+	DeletePrefix(prefix string)
+}
+
+// ResponseCache caches the responses of Folder.GetInfo, File.GetFileInfo, User.GetUser and Group.GetGroup. (APIConn.Cache)
+//
+// Responses are stored by URL (including fields) and As-User.
+// A cached response with an etag is revalidated with "If-None-Match", and 304 is served from the cache.
+// A cached response younger than TTL is served without revalidation.
+// Entries are invalidated by InvalidateXxx, or by the events returned by Event.UserEvent.
+// The entries of a resource (and of its previous and new parent folders) are also invalidated
+// when it is mutated by a request of the connection.
+type ResponseCache struct {
+	Backend CacheBackend
+	// TTL is the duration a cached response is served without revalidation. If 0, responses are always revalidated.
+	// Responses without etag are only served within TTL.
+	TTL time.Duration
+
+	mu sync.Mutex
+	// parents is the parent folder of the cached folders and files. e.g. "/folders/123?" -> "0"
+	parents map[string]string
+}
+
+// NewResponseCache returns the ResponseCache storing responses in backend.
+func NewResponseCache(backend CacheBackend, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{Backend: backend, TTL: ttl}
+}
+
+// cacheKey returns the key of the request. e.g. "/folders/123?fields=name as-user=456"
+func (c *ResponseCache) cacheKey(req *Request) (string, bool) {
+	relativeUrl, err := req.relativeUrl()
+	if err != nil {
+		return "", false
+	}
+	if !strings.Contains(relativeUrl, "?") {
+		relativeUrl += "?"
+	}
+	return relativeUrl + " as-user=" + req.effectiveHeaders().Get(httpHeaderAsUser), true
+}
+
+func cachePrefix(collection string, id string) string {
+	return "/" + collection + "/" + id + "?"
+}
+
+// InvalidateFolder deletes the cached responses of the folder.
+func (c *ResponseCache) InvalidateFolder(folderId string) {
+	c.Backend.DeletePrefix(cachePrefix("folders", folderId))
+}
+
+// InvalidateFile deletes the cached responses of the file.
+func (c *ResponseCache) InvalidateFile(fileId string) {
+	c.Backend.DeletePrefix(cachePrefix("files", fileId))
+}
+
+// InvalidateUser deletes the cached responses of the user.
+func (c *ResponseCache) InvalidateUser(userId string) {
+	c.Backend.DeletePrefix(cachePrefix("users", userId))
+}
+
+// InvalidateGroup deletes the cached responses of the group.
+func (c *ResponseCache) InvalidateGroup(groupId string) {
+	c.Backend.DeletePrefix(cachePrefix("groups", groupId))
+}
+
+// InvalidateAll deletes all the cached responses.
+func (c *ResponseCache) InvalidateAll() {
+	c.Backend.DeletePrefix("")
+	c.mu.Lock()
+	c.parents = nil
+	c.mu.Unlock()
+}
+
+// InvalidateEvents deletes the cached responses of the sources of the events, and of their parent folders.
+func (c *ResponseCache) InvalidateEvents(events []*BoxEvent) {
+	for _, event := range events {
+		var parent *ItemMini
+		switch source := event.Source().(type) {
+		case *Folder:
+			c.invalidate("folders", source.ID)
+			parent = source.Parent
+		case *File:
+			c.invalidate("files", source.ID)
+			parent = source.Parent
+		case *User:
+			c.invalidate("users", source.ID)
+		case *Group:
+			c.invalidate("groups", source.ID)
+		}
+		if parent != nil {
+			c.invalidate("folders", parent.ID)
+		}
+	}
+}
+
+// resourcePrefix returns the prefix of the cache keys of the folder, file, user or group requested by req,
+// and whether req is of the resource itself, not of its sub resource (e.g. "/files/123/copy").
+func resourcePrefix(req *Request) (prefix string, itself bool) {
+	relativeUrl, err := req.relativeUrl()
+	if err != nil {
+		return "", false
+	}
+	if i := strings.Index(relativeUrl, "?"); i >= 0 {
+		relativeUrl = relativeUrl[:i]
+	}
+	// e.g. "/folders/123", "/files/123/copy", "/files/123/metadata/enterprise/contract"
+	segments := strings.Split(strings.TrimPrefix(relativeUrl, "/"), "/")
+	if len(segments) < 2 {
+		return "", false
+	}
+	switch segments[0] {
+	case "folders", "files", "users", "groups":
+		return cachePrefix(segments[0], segments[1]), len(segments) == 2
+	}
+	return "", false
+}
+
+// responseParent returns the id of the parent folder in the body of the response, or "".
+func responseParent(body []byte) string {
+	var resource struct {
+		Parent *ItemMini `json:"parent"`
+	}
+	if err := json.Unmarshal(body, &resource); err != nil || resource.Parent == nil || resource.Parent.ID == nil {
+		return ""
+	}
+	return *resource.Parent.ID
+}
+
+// setParent records the parent folder of the cached resource.
+func (c *ResponseCache) setParent(prefix string, parentId string) {
+	if prefix == "" || parentId == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.parents == nil {
+		c.parents = map[string]string{}
+	}
+	c.parents[prefix] = parentId
+}
+
+// parentOf returns the parent folder of the resource mutated by req, recorded from its cached responses, or "".
+// It must be called before sending req, since the responses of move and delete do not have the previous parent.
+func (c *ResponseCache) parentOf(req *Request) string {
+	if req.Method == GET {
+		return ""
+	}
+	prefix, _ := resourcePrefix(req)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.parents[prefix]
+}
+
+// invalidateMutated deletes the cached responses of the resource mutated by the request (e.g. Update, Delete, Copy,
+// Move, Restore or UpdateMetadata of the folder), of its previous parent folder (returned by parentOf before sending
+// the request), and of the parent folder of the resource in the response.
+func (c *ResponseCache) invalidateMutated(req *Request, resp *Response, previousParent string) {
+	if req.Method == GET || resp.ResponseCode < 200 || resp.ResponseCode >= 300 {
+		return
+	}
+	prefix, itself := resourcePrefix(req)
+	if prefix != "" {
+		c.Backend.DeletePrefix(prefix)
+	}
+	if previousParent != "" {
+		c.InvalidateFolder(previousParent)
+	}
+	parent := responseParent(resp.Body)
+	if parent != "" {
+		c.InvalidateFolder(parent)
+	}
+	if !itself {
+		return
+	}
+	if req.Method == DELETE {
+		c.mu.Lock()
+		delete(c.parents, prefix)
+		c.mu.Unlock()
+		return
+	}
+	c.setParent(prefix, parent)
+}
+
+func (c *ResponseCache) invalidate(collection string, id *string) {
+	if id != nil {
+		c.Backend.DeletePrefix(cachePrefix(collection, *id))
+	}
+}
+
+// sendCached sends the GET request through the cache of the connection, if any.
+func (req *Request) sendCached() (*Response, error) {
+	cache := req.apiConn.Cache
+	if cache == nil || req.Method != GET {
+		return req.Send()
+	}
+	key, ok := cache.cacheKey(req)
+	if !ok {
+		return req.Send()
+	}
+	cached, ok := cache.Backend.Get(key)
+	if ok {
+		if cache.TTL > 0 && time.Since(cached.StoredAt) < cache.TTL {
+			return cached.response(req), nil
+		}
+		if cached.ETag != "" {
+			req.headers.Set(httpHeaderIfNoneMatch, cached.ETag)
+		}
+	}
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.ResponseCode == http.StatusNotModified && ok:
+		cached = &CachedResponse{ETag: cached.ETag, Headers: cached.Headers, Body: cached.Body, StoredAt: time.Now()}
+		cache.Backend.Set(key, cached)
+		return cached.response(req), nil
+	case resp.ResponseCode == http.StatusOK:
+		etag := responseETag(resp)
+		if etag != "" || cache.TTL > 0 {
+			cache.Backend.Set(key, &CachedResponse{ETag: etag, Headers: resp.Headers, Body: resp.Body, StoredAt: time.Now()})
+			prefix, _ := resourcePrefix(req)
+			cache.setParent(prefix, responseParent(resp.Body))
+		}
+	}
+	return resp, nil
+}
+
+func (cr *CachedResponse) response(req *Request) *Response {
+	return &Response{
+		Request:      req,
+		ContentType:  ContentTypeApplicationJson,
+		Headers:      cr.Headers,
+		Body:         cr.Body,
+		ResponseCode: http.StatusOK,
+	}
+}
+
+// responseETag returns "ETag" header, or "etag" field of the json body.
+func responseETag(resp *Response) string {
+	if etag := resp.Headers.Get("ETag"); etag != "" {
+		return etag
+	}
+	var body struct {
+		ETag *string `json:"etag"`
+	}
+	if err := json.Unmarshal(resp.Body, &body); err != nil || body.ETag == nil {
+		return ""
+	}
+	return *body.ETag
+}
+
+// NewLRUCache returns the in-memory CacheBackend which keeps up to capacity responses, evicting the least recently used.
+func NewLRUCache(capacity int) CacheBackend {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &lruCache{capacity: capacity, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type lruEntry struct {
+	key  string
+	resp *CachedResponse
+}
+
+func (c *lruCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry).resp, true
+}
+
+func (c *lruCache) Set(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry).resp = resp
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, resp: resp})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.order.Remove(e)
+			delete(c.entries, key)
+		}
+	}
+}
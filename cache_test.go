@@ -0,0 +1,167 @@
+package goboxer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	folderJson, _ := ioutil.ReadFile("testdata/folders/getinfo_normal.json")
+	eventsJson, _ := ioutil.ReadFile("testdata/events/events_user_normal.json")
+
+	var mu sync.Mutex
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path+" "+r.Header.Get("If-None-Match"))
+		mu.Unlock()
+		w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+		switch {
+		case r.URL.Path == "/2.0/events":
+			_, _ = w.Write(eventsJson)
+		case r.URL.Path == "/2.0/batch":
+			_, _ = w.Write([]byte(`{"responses":[{"status":204,"response":null}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/2.0/folders/30000":
+			_, _ = w.Write([]byte(`{"type":"folder","id":"30000","parent":{"type":"folder","id":"20000"}}`))
+		case r.Header.Get("If-None-Match") == "1":
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			_, _ = w.Write(folderJson)
+		}
+	}))
+	defer ts.Close()
+	takeRequests := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		r := strings.Join(requests, ",")
+		requests = nil
+		return r
+	}
+
+	apiConn := commonInit(ts.URL)
+	apiConn.Cache = NewResponseCache(NewLRUCache(10), 0)
+
+	for _, id := range []string{"10000", "10000", "0", "11446498"} {
+		folder, err := NewFolder(apiConn).GetInfo(id, nil)
+		if err != nil {
+			t.Fatalf("GetInfo() error = %v", err)
+		}
+		if *folder.Name != "Pictures" || folder.apiInfo == nil {
+			t.Errorf("unexpected folder: %v", folder)
+		}
+	}
+	if got, want := takeRequests(), "/2.0/folders/10000 ,/2.0/folders/10000 1,/2.0/folders/0 ,/2.0/folders/11446498 "; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// fields and As-User are part of the key
+	_, _ = NewFolder(apiConn).GetInfo("10000", []string{"name"})
+	_, _ = NewFolder(apiConn.AsUser("5")).GetInfo("10000", nil)
+	if got, want := takeRequests(), "/2.0/folders/10000 ,/2.0/folders/10000 "; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// invalidated explicitly
+	apiConn.Cache.InvalidateFolder("10000")
+	_, _ = NewFolder(apiConn).GetInfo("10000", nil)
+	_, _ = NewFolder(apiConn).GetInfo("10000", []string{"name"})
+	if got, want := takeRequests(), "/2.0/folders/10000 ,/2.0/folders/10000 "; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// invalidated by the events (the folder 11446498 created in the folder 0)
+	if _, _, err := NewEvent(apiConn).UserEvent(All, "", 100); err != nil {
+		t.Fatalf("UserEvent() error = %v", err)
+	}
+	takeRequests()
+	for _, id := range []string{"10000", "0", "11446498"} {
+		_, _ = NewFolder(apiConn).GetInfo(id, nil)
+	}
+	if got, want := takeRequests(), "/2.0/folders/10000 1,/2.0/folders/0 ,/2.0/folders/11446498 "; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// served without revalidation within TTL
+	apiConn.Cache.TTL = time.Hour
+	folder, err := NewFolder(apiConn).GetInfo("10000", nil)
+	if err != nil || *folder.ID != "10000" {
+		t.Errorf("GetInfo() = %v, %v", folder, err)
+	}
+	if got := takeRequests(); got != "" {
+		t.Errorf("requests = %s, want none", got)
+	}
+
+	// invalidated by the mutation through the connection (the folder 10000 and its parent 0)
+	_, _ = NewFolder(apiConn).GetInfo("0", nil)
+	_, _ = NewFolder(apiConn).GetInfo("10000", []string{"name"})
+	if _, err := NewFolder(apiConn).SetName("Pictures").Update("10000", "", nil); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	takeRequests()
+	_, _ = NewFolder(apiConn).GetInfo("10000", nil)
+	_, _ = NewFolder(apiConn).GetInfo("10000", []string{"name"})
+	_, _ = NewFolder(apiConn).GetInfo("0", nil)
+	_, _ = NewFolder(apiConn).GetInfo("11446498", nil)
+	if got, want := takeRequests(), "/2.0/folders/10000 ,/2.0/folders/10000 ,/2.0/folders/0 "; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// invalidated by the move (the folder 30000, its previous parent 0 and its new parent 20000)
+	for _, id := range []string{"30000", "0", "20000"} {
+		_, _ = NewFolder(apiConn).GetInfo(id, nil)
+	}
+	move := NewRequest(apiConn, apiConn.BaseURL+"folders/30000", PUT, nil, strings.NewReader(`{"parent":{"id":"20000"}}`))
+	if _, err := move.Send(); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	takeRequests()
+	for _, id := range []string{"30000", "0", "20000"} {
+		_, _ = NewFolder(apiConn).GetInfo(id, nil)
+	}
+	if got, want := takeRequests(), "/2.0/folders/30000 ,/2.0/folders/0 ,/2.0/folders/20000 "; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// invalidated by the delete as the sub request of the batch request (the folder 10000 and its parent 0)
+	_, _ = NewFolder(apiConn).GetInfo("10000", nil)
+	_, _ = NewFolder(apiConn).GetInfo("0", nil)
+	if _, err := NewBatchRequest(apiConn).ExecuteBatch([]*Request{NewFolder(apiConn).DeleteReq("10000", false, "")}); err != nil {
+		t.Fatalf("ExecuteBatch() error = %v", err)
+	}
+	takeRequests()
+	_, _ = NewFolder(apiConn).GetInfo("10000", nil)
+	_, _ = NewFolder(apiConn).GetInfo("0", nil)
+	if got, want := takeRequests(), "/2.0/folders/10000 ,/2.0/folders/0 "; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("/folders/1?", &CachedResponse{ETag: "1"})
+	c.Set("/folders/1?fields=name", &CachedResponse{ETag: "2"})
+	if _, ok := c.Get("/folders/1?"); !ok {
+		t.Fatalf("entry not found")
+	}
+	// the least recently used entry is evicted
+	c.Set("/files/1?", &CachedResponse{ETag: "3"})
+	if _, ok := c.Get("/folders/1?fields=name"); ok {
+		t.Errorf("the least recently used entry is not evicted")
+	}
+	if _, ok := c.Get("/folders/1?"); !ok {
+		t.Errorf("the recently used entry is evicted")
+	}
+
+	c.DeletePrefix("/folders/1?")
+	if _, ok := c.Get("/folders/1?"); ok {
+		t.Errorf("entry is not deleted")
+	}
+	if r, ok := c.Get("/files/1?"); !ok || r.ETag != "3" {
+		t.Errorf("other entry is deleted")
+	}
+}
@@ -0,0 +1,347 @@
+package goboxer
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/xerrors"
+)
+
+// Cassette is a sequence of recorded HTTP interactions.
+//
+// Cassettes are sanitized when recorded: secrets (tokens, client secrets, passwords...) are replaced by RedactedValue,
+// and email addresses are replaced by stable placeholders, so they can be committed under testdata/.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a pair of the recorded request and response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a sanitized HTTP request.
+type RecordedRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	// BodyEncoding is "base64" if Body is not a text.
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// RecordedResponse is a sanitized HTTP response.
+type RecordedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	// BodyEncoding is "base64" if Body is not a text.
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// LoadCassette reads the cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, xerrors.Errorf("failed to parse cassette: %w", err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to the file. Missing directories are created.
+func (c *Cassette) Save(path string) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(c); err != nil {
+		return xerrors.Errorf("failed to serialize cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return xerrors.Errorf("failed to create directory: %w", err)
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return xerrors.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Recorder is a http.RoundTripper that records the traffic into a sanitized Cassette.
+//
+//	recorder := goboxer.NewRecorder(nil)
+//	apiConn.Transport = recorder
+//	// ... use apiConn
+//	err := recorder.Cassette().Save("testdata/folders/cassettes/getinfo.json")
+type Recorder struct {
+	// Transport sends the actual requests.
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder that sends the requests by transport.
+// If transport is nil, the shared default transport is used.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = client.Transport
+	}
+	return &Recorder{Transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read request body: %w", err)
+		}
+		reqBody = b
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+
+	resp, err := r.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Request:  sanitizeRequest(req, reqBody),
+		Response: RecordedResponse{StatusCode: resp.StatusCode, Headers: sanitizeHeader(resp.Header)},
+	}
+	interaction.Response.Body, interaction.Response.BodyEncoding = sanitizeBody(resp.Header.Get(httpHeaderContentType), respBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+// Cassette returns a copy of the recorded interactions.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := &Cassette{Interactions: make([]*Interaction, len(r.cassette.Interactions))}
+	copy(c.Interactions, r.cassette.Interactions)
+	return c
+}
+
+// Replayer is a http.RoundTripper that serves the recorded responses of a Cassette instead of sending requests.
+//
+// A request matches an interaction by method, path, query and body (sanitized the same way as recorded).
+// The host is not compared, so a cassette recorded against Box can be replayed with any endpoints.
+// Each interaction is served once, in the recorded order among the matching ones.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayer returns a Replayer serving the cassette.
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{cassette: cassette, used: make([]bool, len(cassette.Interactions))}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read request body: %w", err)
+		}
+		reqBody = b
+	}
+	recorded := sanitizeRequest(req, reqBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !matchRequest(&interaction.Request, &recorded) {
+			continue
+		}
+		r.used[i] = true
+
+		body := []byte(interaction.Response.Body)
+		if interaction.Response.BodyEncoding == "base64" {
+			b, err := base64.StdEncoding.DecodeString(interaction.Response.Body)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to decode recorded response body: %w", err)
+			}
+			body = b
+		}
+		header := http.Header{}
+		for k, v := range interaction.Response.Headers {
+			header.Set(k, v)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, xerrors.Errorf("no recorded interaction for %s %s", recorded.Method, recorded.URL)
+}
+
+// Unused returns the interactions that have not been served yet.
+func (r *Replayer) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []*Interaction
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func matchRequest(recorded *RecordedRequest, req *RecordedRequest) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	u1, err1 := url.Parse(recorded.URL)
+	u2, err2 := url.Parse(req.URL)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	if u1.Path != u2.Path || !reflect.DeepEqual(u1.Query(), u2.Query()) {
+		return false
+	}
+	if recorded.Body == req.Body {
+		return true
+	}
+	// json bodies are compared regardless of the order of the keys
+	var v1, v2 interface{}
+	if json.Unmarshal([]byte(recorded.Body), &v1) != nil || json.Unmarshal([]byte(req.Body), &v2) != nil {
+		return false
+	}
+	return reflect.DeepEqual(v1, v2)
+}
+
+// headers not worth recording (they change for every request)
+var volatileHeaders = map[string]bool{
+	"Date":            true,
+	"Content-Length":  true,
+	"Box-Request-Id":  true,
+	"Accept-Encoding": true,
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+
+// scrubEmails replaces email addresses with placeholders.
+// The same address is always replaced with the same placeholder, so recorded requests still match.
+func scrubEmails(s string) string {
+	return emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+		if strings.HasSuffix(strings.ToLower(email), "@example.com") {
+			return email
+		}
+		sum := sha1.Sum([]byte(strings.ToLower(email)))
+		return "user-" + hex.EncodeToString(sum[:4]) + "@example.com"
+	})
+}
+
+var redactedFieldsPattern = func() *regexp.Regexp {
+	var fields []string
+	for f := range redactedFields {
+		fields = append(fields, regexp.QuoteMeta(f))
+	}
+	sort.Strings(fields)
+	return regexp.MustCompile(`("(?:` + strings.Join(fields, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+}()
+
+func sanitizeHeader(header http.Header) map[string]string {
+	r := map[string]string{}
+	for k, v := range redactHeader(header) {
+		if !volatileHeaders[http.CanonicalHeaderKey(k)] {
+			r[http.CanonicalHeaderKey(k)] = scrubEmails(v)
+		}
+	}
+	return r
+}
+
+func sanitizeRequest(req *http.Request, body []byte) RecordedRequest {
+	r := RecordedRequest{
+		Method:  req.Method,
+		URL:     scrubEmails(redactURL(req.URL)),
+		Headers: sanitizeHeader(req.Header),
+	}
+	contentType := req.Header.Get(httpHeaderContentType)
+	if boundary := multipartBoundary(contentType); boundary != "" {
+		// boundaries are random, normalize them to match the recorded requests
+		r.Headers[httpHeaderContentType] = strings.Replace(contentType, boundary, "BOUNDARY", 1)
+		body = bytes.Replace(body, []byte(boundary), []byte("BOUNDARY"), -1)
+	}
+	r.Body, r.BodyEncoding = sanitizeBody(contentType, body)
+	return r
+}
+
+func multipartBoundary(contentType string) string {
+	if !strings.HasPrefix(mediaType(contentType), "multipart/") {
+		return ""
+	}
+	i := strings.Index(contentType, "boundary=")
+	if i < 0 {
+		return ""
+	}
+	boundary := strings.Trim(contentType[i+len("boundary="):], `"`)
+	if j := strings.Index(boundary, ";"); j >= 0 {
+		boundary = boundary[:j]
+	}
+	return boundary
+}
+
+// sanitizeBody returns the body with secrets and emails scrubbed.
+// JSON is not re-encoded, to keep the order of the fields as sent by Box.
+func sanitizeBody(contentType string, body []byte) (string, string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+	switch mediaType(contentType) {
+	case ContentTypeFormUrlEncoded:
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return scrubEmails(redactValues(values).Encode()), ""
+		}
+	case ContentTypeApplicationJson, ContentTypeApplicationJsonPatch:
+		body = redactedFieldsPattern.ReplaceAll(body, []byte(`$1"`+RedactedValue+`"`))
+	}
+	if !utf8.Valid(body) {
+		return base64.StdEncoding.EncodeToString(body), "base64"
+	}
+	return scrubEmails(string(body)), ""
+}
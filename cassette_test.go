@@ -0,0 +1,183 @@
+package goboxer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// emails are scrubbed from cassettes
+var scrubbedOpts = []cmp.Option{
+	cmpopts.IgnoreUnexported(Folder{}, File{}, SharedLink{}),
+	cmpopts.IgnoreFields(UserGroupMini{}, "Login"),
+	cmpopts.IgnoreFields(FolderUploadEmail{}, "Email"),
+}
+
+func TestReplayer_testdata(t *testing.T) {
+	cassette, err := LoadCassette("testdata/folders/cassettes/getinfo.json")
+	if err != nil {
+		t.Fatalf("failed to load cassette: %+v", err)
+	}
+	replayer := NewReplayer(cassette)
+
+	apiConn := NewAPIConnWithRefreshToken("CLIENT_ID", "CLIENT_SECRET", "ACCESS_TOKEN", "REFRESH_TOKEN")
+	apiConn.Transport = replayer
+
+	if err := apiConn.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %+v", err)
+	}
+	got, err := NewFolder(apiConn).GetInfo("10000", nil)
+	if err != nil {
+		t.Fatalf("GetInfo failed: %+v", err)
+	}
+	want := buildFolderOfGetInfoNormalJson()
+	if diff := cmp.Diff(got, want, scrubbedOpts...); diff != "" {
+		t.Errorf("replayed folder differs: (-got +want)\n%s", diff)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("unused interactions: %d", len(unused))
+	}
+
+	// every interaction is served once
+	_, err = NewFolder(apiConn).GetInfo("10000", nil)
+	if _, ok := err.(*ApiOtherError); !ok {
+		t.Errorf("expected ApiOtherError, got %v", err)
+	}
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/oauth2/token":
+			_, _ = w.Write([]byte(`{"access_token":"NEW_ACCESS_TOKEN","refresh_token":"NEW_REFRESH_TOKEN","expires_in":3600,"restricted_to":[],"token_type":"bearer"}`))
+		case r.URL.Path == "/api/2.0/files/content":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"total_count":1,"entries":[{"type":"file","id":"5000948880","name":"a.txt","created_by":{"type":"user","id":"1","login":"john.doe@corp.example.org"}}]}`))
+		default:
+			resp, _ := ioutil.ReadFile("testdata/folders/getinfo_normal.json")
+			_, _ = w.Write(resp)
+		}
+	}))
+	defer ts.Close()
+
+	apiConn := commonInit(ts.URL)
+	recorder := NewRecorder(nil)
+	apiConn.Transport = recorder
+
+	if err := apiConn.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %+v", err)
+	}
+	wantFolder, err := NewFolder(apiConn).GetInfo("10000", FolderAllFields)
+	if err != nil {
+		t.Fatalf("GetInfo failed: %+v", err)
+	}
+	wantFile, err := NewFile(apiConn).UploadFile("a.txt", strings.NewReader("hello"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %+v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "folders", "cassettes", "record.json")
+	if err := recorder.Cassette().Save(path); err != nil {
+		t.Fatalf("Save failed: %+v", err)
+	}
+
+	b, _ := ioutil.ReadFile(path)
+	for _, secret := range []string{"NEW_ACCESS_TOKEN", "NEW_REFRESH_TOKEN", "CLIENT_SECRET", "Bearer", "john.doe@corp.example.org", "sean@box.com"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %+v", err)
+	}
+	apiConn = commonInit("https://replay.example.com")
+	apiConn.Transport = NewReplayer(cassette)
+
+	if err := apiConn.Refresh(); err != nil {
+		t.Fatalf("replayed Refresh failed: %+v", err)
+	}
+	gotFolder, err := NewFolder(apiConn).GetInfo("10000", FolderAllFields)
+	if err != nil {
+		t.Fatalf("replayed GetInfo failed: %+v", err)
+	}
+	gotFile, err := NewFile(apiConn).UploadFile("a.txt", strings.NewReader("hello"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("replayed UploadFile failed: %+v", err)
+	}
+
+	if diff := cmp.Diff(gotFolder, wantFolder, scrubbedOpts...); diff != "" {
+		t.Errorf("replayed folder differs: (-got +want)\n%s", diff)
+	}
+	if diff := cmp.Diff(gotFile, wantFile, scrubbedOpts...); diff != "" {
+		t.Errorf("replayed file differs: (-got +want)\n%s", diff)
+	}
+	if !strings.HasSuffix(*gotFile.CreatedBy.Login, "@example.com") {
+		t.Errorf("email is not scrubbed: %s", *gotFile.CreatedBy.Login)
+	}
+}
+
+func Test_matchRequest(t *testing.T) {
+	recorded := &RecordedRequest{
+		Method: "PUT",
+		URL:    "https://api.box.com/2.0/folders/1?fields=name&limit=10",
+		Body:   `{"name":"a","description":"b"}`,
+	}
+	tests := []struct {
+		name string
+		req  *RecordedRequest
+		want bool
+	}{
+		{"same", &RecordedRequest{Method: "PUT", URL: "https://api.box.com/2.0/folders/1?fields=name&limit=10", Body: `{"name":"a","description":"b"}`}, true},
+		{"host/query order/json key order", &RecordedRequest{Method: "PUT", URL: "http://127.0.0.1:8080/2.0/folders/1?limit=10&fields=name", Body: `{"description":"b", "name":"a"}`}, true},
+		{"method", &RecordedRequest{Method: "POST", URL: "https://api.box.com/2.0/folders/1?fields=name&limit=10", Body: `{"name":"a","description":"b"}`}, false},
+		{"path", &RecordedRequest{Method: "PUT", URL: "https://api.box.com/2.0/folders/2?fields=name&limit=10", Body: `{"name":"a","description":"b"}`}, false},
+		{"query", &RecordedRequest{Method: "PUT", URL: "https://api.box.com/2.0/folders/1?fields=name&limit=20", Body: `{"name":"a","description":"b"}`}, false},
+		{"body", &RecordedRequest{Method: "PUT", URL: "https://api.box.com/2.0/folders/1?fields=name&limit=10", Body: `{"name":"b","description":"b"}`}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchRequest(recorded, tt.req); got != tt.want {
+				t.Errorf("matchRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sanitizeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"json", "application/json; charset=utf-8", `{"type":"user","login":"sean@box.com","access_token":"SECRET","name":"x"}`,
+			`{"type":"user","login":"` + scrubEmails("sean@box.com") + `","access_token":"[REDACTED]","name":"x"}`},
+		{"json patch", ContentTypeApplicationJsonPatch, `[{"op":"add","path":"/shared_link","value":{"password":"secret"}}]`,
+			`[{"op":"add","path":"/shared_link","value":{"password":"[REDACTED]"}}]`},
+		{"form", ContentTypeFormUrlEncoded, "grant_type=refresh_token&refresh_token=SECRET",
+			"grant_type=refresh_token&refresh_token=%5BREDACTED%5D"},
+		{"example.com is kept", "text/plain", "user@example.com", "user@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := sanitizeBody(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("sanitizeBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package goboxer
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit of CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests fast with *CircuitOpenError.
+	CircuitOpen
+	// CircuitHalfOpen lets a probe request through, and fails the others fast.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// CircuitOpenError is returned instead of sending a request while the circuit of the host is open.
+type CircuitOpenError struct {
+	Host string
+	// RetryAt is the time when a probe request is allowed.
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("goboxer: circuit breaker for %s is open until %s", e.Host, e.RetryAt.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// CircuitBreaker stops sending requests to a host (API or upload) during a sustained outage. (APIConn.CircuitBreaker)
+//
+// The circuit of a host opens after FailureThreshold consecutive failures (5xx or no response),
+// and requests fail fast with *CircuitOpenError while it is open.
+// After OpenTimeout, a probe request is let through (half-open). The circuit closes if it succeeds, or opens again.
+// State changes are logged and notified to Metrics.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of the consecutive failures to open the circuit.
+	FailureThreshold int
+	// OpenTimeout is the duration the circuit stays open before a probe request.
+	OpenTimeout time.Duration
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker returns the CircuitBreaker which opens after failureThreshold consecutive failures,
+// and probes after openTimeout.
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{FailureThreshold: failureThreshold, OpenTimeout: openTimeout}
+}
+
+// State returns the state of the circuit of the host.
+func (cb *CircuitBreaker) State(host string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if c, ok := cb.circuits[host]; ok {
+		return c.state
+	}
+	return CircuitClosed
+}
+
+func (cb *CircuitBreaker) circuit(host string) *circuit {
+	if cb.circuits == nil {
+		cb.circuits = map[string]*circuit{}
+	}
+	c, ok := cb.circuits[host]
+	if !ok {
+		c = &circuit{}
+		cb.circuits[host] = c
+	}
+	return c
+}
+
+// allow returns *CircuitOpenError if the request to the host must fail fast.
+func (cb *CircuitBreaker) allow(host string) error {
+	cb.mu.Lock()
+	c := cb.circuit(host)
+	var changed *stateChange
+	var err error
+	switch c.state {
+	case CircuitOpen:
+		retryAt := c.openedAt.Add(cb.OpenTimeout)
+		if time.Now().Before(retryAt) {
+			err = &CircuitOpenError{Host: host, RetryAt: retryAt}
+			break
+		}
+		changed = c.setState(host, CircuitHalfOpen)
+		c.probing = true
+	case CircuitHalfOpen:
+		if c.probing {
+			err = &CircuitOpenError{Host: host, RetryAt: time.Now().Add(cb.OpenTimeout)}
+			break
+		}
+		c.probing = true
+	}
+	cb.mu.Unlock()
+	changed.notify()
+	return err
+}
+
+// record records the result of the request to the host.
+func (cb *CircuitBreaker) record(host string, failed bool) {
+	cb.mu.Lock()
+	c := cb.circuit(host)
+	var changed *stateChange
+	c.probing = false
+	if !failed {
+		c.failures = 0
+		if c.state != CircuitClosed {
+			changed = c.setState(host, CircuitClosed)
+		}
+	} else {
+		c.failures++
+		if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= cb.FailureThreshold) {
+			c.openedAt = time.Now()
+			changed = c.setState(host, CircuitOpen)
+		}
+	}
+	cb.mu.Unlock()
+	changed.notify()
+}
+
+type stateChange struct {
+	host     string
+	from     CircuitState
+	to       CircuitState
+	failures int
+}
+
+func (c *circuit) setState(host string, to CircuitState) *stateChange {
+	from := c.state
+	c.state = to
+	return &stateChange{host: host, from: from, to: to, failures: c.failures}
+}
+
+// notify logs the state change and notifies it to Metrics (outside of the lock).
+func (sc *stateChange) notify() {
+	if sc == nil {
+		return
+	}
+	if Log != nil {
+		Log.Warnf("[goboxer] Circuit breaker for %s: %s -> %s\n", sc.host, sc.from, sc.to)
+	}
+	logWarn("goboxer circuit breaker state changed", "host", sc.host, "from", sc.from.String(), "to", sc.to.String(), "failures", sc.failures)
+	if Metrics != nil {
+		Metrics.CircuitStateChanged(sc.host, sc.from, sc.to)
+	}
+}
+
+// circuitBreakerTransport fails requests fast while the circuit of their host is open.
+type circuitBreakerTransport struct {
+	breaker *CircuitBreaker
+	base    http.RoundTripper
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if err := t.breaker.allow(host); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	t.breaker.record(host, err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	return resp, err
+}
@@ -0,0 +1,137 @@
+package goboxer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+type recordingMetrics struct {
+	mu          sync.Mutex
+	transitions []string
+	requests    int
+}
+
+func (m *recordingMetrics) RequestCompleted(method string, host string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests++
+}
+
+func (m *recordingMetrics) CircuitStateChanged(host string, from CircuitState, to CircuitState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transitions = append(m.transitions, fmt.Sprintf("%s->%s", from, to))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var down int32 = 1
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			w.Header().Set(HttpHeaderRetryAfter, "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+		_, _ = w.Write([]byte(`{"type":"user","id":"1"}`))
+	}))
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	metrics := &recordingMetrics{}
+	Metrics = metrics
+	defer func() { Metrics = nil }()
+
+	apiConn := commonInit(ts.URL)
+	apiConn.CircuitBreaker = NewCircuitBreaker(3, 100*time.Millisecond)
+
+	// opens after 3 failures, the remaining retries fail fast
+	_, err := NewUser(apiConn).GetCurrentUser(nil)
+	var openErr *CircuitOpenError
+	if !xerrors.Is(err, ErrCircuitOpen) || !xerrors.As(err, &openErr) || openErr.Host != host {
+		t.Fatalf("GetCurrentUser() error = %v, want CircuitOpenError", err)
+	}
+	if requests != 3 {
+		t.Errorf("%d requests sent, want 3", requests)
+	}
+	if state := apiConn.CircuitBreaker.State(host); state != CircuitOpen {
+		t.Errorf("State() = %s, want open", state)
+	}
+
+	// fails fast while open
+	if _, err := NewUser(apiConn).GetCurrentUser(nil); !xerrors.Is(err, ErrCircuitOpen) || requests != 3 {
+		t.Errorf("GetCurrentUser() error = %v, %d requests sent", err, requests)
+	}
+
+	// the probe fails and the circuit opens again
+	time.Sleep(120 * time.Millisecond)
+	if _, err := NewUser(apiConn).GetCurrentUser(nil); !xerrors.Is(err, ErrCircuitOpen) || requests != 4 {
+		t.Errorf("GetCurrentUser() error = %v, %d requests sent", err, requests)
+	}
+
+	// the probe succeeds and the circuit closes
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(120 * time.Millisecond)
+	if _, err := NewUser(apiConn).GetCurrentUser(nil); err != nil {
+		t.Fatalf("GetCurrentUser() error = %v", err)
+	}
+	if state := apiConn.CircuitBreaker.State(host); state != CircuitClosed {
+		t.Errorf("State() = %s, want closed", state)
+	}
+
+	want := "closed->open,open->half-open,half-open->open,open->half-open,half-open->closed"
+	if got := strings.Join(metrics.transitions, ","); got != want {
+		t.Errorf("transitions = %s, want %s", got, want)
+	}
+	// 5 requests sent and 3 failed fast
+	if metrics.requests != 8 {
+		t.Errorf("RequestCompleted is called %d times, want 8", metrics.requests)
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	cb := NewCircuitBreaker(1, 0)
+	cb.record("upload.box.com", true)
+	if cb.State("upload.box.com") != CircuitOpen || cb.State("api.box.com") != CircuitClosed {
+		t.Fatalf("circuits must be per host")
+	}
+	if err := cb.allow("upload.box.com"); err != nil {
+		t.Fatalf("the probe request is not allowed: %v", err)
+	}
+	// only one probe request at a time
+	if err := cb.allow("upload.box.com"); !xerrors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() = %v, want ErrCircuitOpen", err)
+	}
+	if err := cb.allow("api.box.com"); err != nil {
+		t.Errorf("allow() = %v for other host", err)
+	}
+	cb.record("upload.box.com", false)
+	if cb.State("upload.box.com") != CircuitClosed {
+		t.Errorf("State() = %s, want closed", cb.State("upload.box.com"))
+	}
+}
+
+func TestAPIConn_httpClient(t *testing.T) {
+	u, _ := url.Parse("https://api.box.com/2.0/users/me")
+	apiConn := &APIConn{}
+	if apiConn.httpClient() != client {
+		t.Errorf("the shared client must be used by default")
+	}
+	apiConn.RateLimiter = NewRateLimiter(1, 1)
+	apiConn.CircuitBreaker = NewCircuitBreaker(1, time.Minute)
+	apiConn.CircuitBreaker.record(u.Host, true)
+	_, err := apiConn.httpClient().Transport.RoundTrip(&http.Request{Method: http.MethodGet, URL: u})
+	if !xerrors.Is(err, ErrCircuitOpen) {
+		t.Errorf("RoundTrip() error = %v, want ErrCircuitOpen", err)
+	}
+}
@@ -23,12 +23,10 @@ package cmd
 import (
 	"bufio"
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"github.com/jparound30/goboxer"
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
-	"net/http"
 	"os"
 	"strings"
 )
@@ -65,10 +63,10 @@ var createFolderCmd = &cobra.Command{
 		defer file.Close()
 
 		type Task struct {
-			name string
-			id   string
-			path string
-			req  *goboxer.Request
+			name    string
+			id      string
+			path    string
+			created *goboxer.PendingFolder
 		}
 
 		folder := goboxer.NewFolder(apiConn)
@@ -85,7 +83,6 @@ var createFolderCmd = &cobra.Command{
 			}
 			f := &Task{
 				name: line,
-				req:  folder.CreateReq(parent, line, nil),
 			}
 			folderNames = append(folderNames, f)
 		}
@@ -95,48 +92,34 @@ var createFolderCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		batchReq := goboxer.NewBatchRequest(apiConn)
+		if len(folderNames) == 0 {
+			fmt.Println("no folder names in " + inf)
+			os.Exit(1)
+		}
+
+		batch := goboxer.NewBatchRequest(apiConn)
+		for _, t := range folderNames {
+			t.created = folder.CreateBatch(batch, parent, t.name, nil)
+		}
+		if _, err := batch.Execute(); err != nil {
+			fmt.Printf("%+v\n", xerrors.Errorf("failed to execute batch request\n%+v", err))
+			os.Exit(1)
+		}
 
-		var reqs []*goboxer.Request
-		for i, t := range folderNames {
-			if len(reqs) < 20 {
-				reqs = append(reqs, t.req)
+		for _, v := range folderNames {
+			f, err := v.created.Result()
+			if err != nil {
+				fmt.Printf("%+v\n", xerrors.Errorf("failed to create folder: %w", err))
+				v.id = "failed"
+				continue
 			}
-			if len(reqs) == 20 || len(folderNames)-1 == i {
-				response, err := batchReq.ExecuteBatch(reqs)
-				if err != nil {
-					fmt.Printf("%+v\n", xerrors.Errorf("failed to execute batch request\n%+v", err))
-					os.Exit(1)
-				}
-
-				for _, resp := range response.Responses {
-					for _, v := range folderNames {
-						if v.req == resp.Request {
-							if resp.ResponseCode == http.StatusCreated {
-								f := &goboxer.Folder{}
-								err = json.Unmarshal(resp.Body, f)
-								if err != nil {
-									fmt.Printf("%+v\n", xerrors.Errorf("failed to parse batch request: %w", err))
-									os.Exit(1)
-								}
-								v.id = *f.ID
-								builder := strings.Builder{}
-								for _, p := range f.PathCollection.Entries {
-									builder.WriteString(*p.Name + "/")
-								}
-								builder.WriteString(v.name)
-								v.path = builder.String()
-								break
-							} else {
-								err := goboxer.NewApiStatusError(resp.Body)
-								fmt.Printf("%+v\n", xerrors.Errorf("failed to parse batch request: %w", err))
-								v.id = "failed"
-							}
-						}
-					}
-				}
-				reqs = []*goboxer.Request{}
+			v.id = *f.ID
+			builder := strings.Builder{}
+			for _, p := range f.PathCollection.Entries {
+				builder.WriteString(*p.Name + "/")
 			}
+			builder.WriteString(v.name)
+			v.path = builder.String()
 		}
 
 		// output result
@@ -18,6 +18,8 @@ var clientSecret string
 var accessToken string
 var refreshToken string
 var verbose bool
+var endpoint string
+var endpoints goboxer.Endpoints
 
 var apiConn *goboxer.APIConn
 
@@ -63,6 +65,13 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&StateFilename, "state", "./apiconnstate.json", "goboxer state file(json file that include credentials)")
 
+	rootCmd.PersistentFlags().StringVar(&endpoint, "endpoint", "", "Root URL of Box API compatible server(emulator, reverse proxy...). default is Box")
+	rootCmd.PersistentFlags().StringVar(&endpoints.BaseURL, "base-url", "", "Base URL of API (overrides --endpoint)")
+	rootCmd.PersistentFlags().StringVar(&endpoints.BaseUploadURL, "upload-url", "", "Base URL of Upload API (overrides --endpoint)")
+	rootCmd.PersistentFlags().StringVar(&endpoints.TokenURL, "token-url", "", "Token URL (overrides --endpoint)")
+	rootCmd.PersistentFlags().StringVar(&endpoints.RevokeURL, "revoke-url", "", "Revoke URL (overrides --endpoint)")
+	rootCmd.PersistentFlags().StringVar(&endpoints.AuthorizationURL, "authorize-url", "", "Authorization URL of OAuth2.0 (overrides --endpoint)")
+
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose log output")
 
 	// Cobra also supports local flags, which will only run
@@ -101,6 +110,10 @@ var (
 
 func createGoboxerApiConn() error {
 	apiConn = goboxer.NewAPIConnWithRefreshToken(clientId, clientSecret, accessToken, refreshToken)
+	if endpoint != "" {
+		apiConn.SetEndpoints(goboxer.NewEndpoints(endpoint))
+	}
+	apiConn.SetEndpoints(endpoints)
 
 	_, err := os.Stat(StateFilename)
 	if err == nil {
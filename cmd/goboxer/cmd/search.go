@@ -0,0 +1,159 @@
+// Copyright © 2019 Nobuhiro Tabuki
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jparound30/goboxer"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	"os"
+	"time"
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "search for files and folders",
+	Long: `search for files and folders by a query and/or a metadata filter, and print them as CSV.
+
+	goboxer search -q "annual report" -t file -e pdf,docx -a 12345
+	goboxer search --mdfilter '{"scope":"enterprise","templateKey":"contract","filters":{"amount":{"gt":100}}}'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := createGoboxerApiConn()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		search, err := buildSearch(cmd)
+		if err != nil {
+			fmt.Printf("%+v\n", err)
+			os.Exit(1)
+		}
+		max, _ := cmd.Flags().GetInt("max")
+
+		// output result
+		fmt.Printf("%s,%s,%s\n", "type", "id", "name")
+		it := search.ExecuteIter([]string{"type", "id", "name"}, 200)
+		defer it.Close()
+		for count := 0; (max <= 0 || count < max) && it.Next(); count++ {
+			switch v := it.Item().(type) {
+			case *goboxer.File:
+				fmt.Printf("%s,%s,%s\n", "file", *v.ID, *v.Name)
+			case *goboxer.Folder:
+				fmt.Printf("%s,%s,%s\n", "folder", *v.ID, *v.Name)
+			}
+		}
+		if err := it.Err(); err != nil {
+			fmt.Printf("%+v\n", xerrors.Errorf("failed to search: %w", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// buildSearch returns the search with the conditions specified by flags.
+func buildSearch(cmd *cobra.Command) (*goboxer.Search, error) {
+	flags := cmd.Flags()
+	search := goboxer.NewSearch(apiConn)
+
+	query, _ := flags.GetString("query")
+	search.SetQuery(query)
+	if typ, _ := flags.GetString("type"); typ != "" {
+		search.SetType(goboxer.ItemType(typ))
+	}
+	if extensions, _ := flags.GetStringSlice("extensions"); len(extensions) != 0 {
+		search.SetFileExtensions(extensions...)
+	}
+	if ancestors, _ := flags.GetStringSlice("ancestor"); len(ancestors) != 0 {
+		search.SetAncestorFolderIds(ancestors...)
+	}
+	if owners, _ := flags.GetStringSlice("owner"); len(owners) != 0 {
+		search.SetOwnerUserIds(owners...)
+	}
+	if contentTypes, _ := flags.GetStringSlice("content-types"); len(contentTypes) != 0 {
+		var cts []goboxer.SearchContentType
+		for _, ct := range contentTypes {
+			cts = append(cts, goboxer.SearchContentType(ct))
+		}
+		search.SetContentTypes(cts...)
+	}
+
+	var times [4]time.Time
+	for i, name := range []string{"created-from", "created-to", "updated-from", "updated-to"} {
+		v, _ := flags.GetString(name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid --%s (RFC3339 is required): %w", name, err)
+		}
+		times[i] = t
+	}
+	search.SetCreatedAtRange(times[0], times[1])
+	search.SetUpdatedAtRange(times[2], times[3])
+	minSize, _ := flags.GetInt64("min-size")
+	maxSize, _ := flags.GetInt64("max-size")
+	search.SetSizeRange(minSize, maxSize)
+
+	if trash, _ := flags.GetString("trash"); trash != "" {
+		search.SetTrashContent(goboxer.SearchTrashContent(trash))
+	}
+	mdFilter, _ := flags.GetString("mdfilter")
+	if query == "" && mdFilter == "" {
+		return nil, xerrors.New("--query or --mdfilter is required")
+	}
+	if mdFilter != "" {
+		filter := &goboxer.MetadataSearchFilter{}
+		if err := json.Unmarshal([]byte(mdFilter), filter); err != nil {
+			return nil, xerrors.Errorf("invalid --mdfilter: %w", err)
+		}
+		search.AddMetadataFilter(filter)
+	}
+	if sort, _ := flags.GetString("sort"); sort != "" {
+		direction, _ := flags.GetString("direction")
+		search.SetSort(goboxer.SearchSort(sort), direction)
+	}
+	return search, nil
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().StringP("query", "q", "", "search query")
+	searchCmd.Flags().StringP("type", "t", "", "file or folder")
+	searchCmd.Flags().StringSliceP("extensions", "e", nil, "file extensions (e.g. pdf,docx)")
+	searchCmd.Flags().StringSliceP("ancestor", "a", nil, "ancestor folder ids")
+	searchCmd.Flags().StringSlice("owner", nil, "owner user ids")
+	searchCmd.Flags().StringSlice("content-types", nil, "name,description,file_content,comments,tags")
+	searchCmd.Flags().String("created-from", "", "created at or after (RFC3339)")
+	searchCmd.Flags().String("created-to", "", "created at or before (RFC3339)")
+	searchCmd.Flags().String("updated-from", "", "updated at or after (RFC3339)")
+	searchCmd.Flags().String("updated-to", "", "updated at or before (RFC3339)")
+	searchCmd.Flags().Int64("min-size", -1, "minimum size in bytes")
+	searchCmd.Flags().Int64("max-size", -1, "maximum size in bytes")
+	searchCmd.Flags().String("trash", "", "non_trashed_only, trashed_only or all_items")
+	searchCmd.Flags().String("mdfilter", "", `metadata filter in JSON (e.g. {"scope":"enterprise","templateKey":"contract","filters":{"vendor":"Acme"}})`)
+	searchCmd.Flags().String("sort", "", "relevance or modified_at")
+	searchCmd.Flags().String("direction", "DESC", "ASC or DESC (for --sort modified_at)")
+	searchCmd.Flags().IntP("max", "n", 0, "maximum number of results (0 means all)")
+}
@@ -0,0 +1,179 @@
+// Copyright © 2019 Nobuhiro Tabuki
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/jparound30/goboxer"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	"os"
+)
+
+// watermarkCmd represents the watermark command
+var watermarkCmd = &cobra.Command{
+	Use:   "watermark",
+	Short: "get, apply or remove watermarks on files and folders",
+	Long:  ``,
+}
+
+var watermarkGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "get the watermark of a folder (or a file with --file)",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := createGoboxerApiConn()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		id := cmd.Flag("id").Value.String()
+
+		var watermark *goboxer.Watermark
+		if cmd.Flag("file").Value.String() == "true" {
+			watermark, err = goboxer.NewFile(apiConn).GetWatermark(id)
+		} else {
+			watermark, err = goboxer.NewFolder(apiConn).GetWatermark(id)
+		}
+		if xerrors.Is(err, goboxer.ErrNotFound) {
+			fmt.Printf("%s is not watermarked\n", id)
+			return
+		}
+		if err != nil {
+			fmt.Printf("%+v\n", xerrors.Errorf("failed to get watermark: %w", err))
+			os.Exit(1)
+		}
+		fmt.Printf("%s is watermarked %s\n", id, watermark)
+	},
+}
+
+var watermarkApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "apply watermark to a folder (or a file with --file)",
+	Long: `apply watermark to a folder (or a file with --file).
+With --recursive, applies watermark to all the sub folders and files in the folder tree too.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runWatermark(cmd, func(typ string, id string) error {
+			var err error
+			if typ == "file" {
+				_, err = goboxer.NewFile(apiConn).ApplyWatermark(id)
+			} else {
+				_, err = goboxer.NewFolder(apiConn).ApplyWatermark(id)
+			}
+			return err
+		})
+	},
+}
+
+var watermarkRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "remove watermark from a folder (or a file with --file)",
+	Long: `remove watermark from a folder (or a file with --file).
+With --recursive, removes watermark from all the sub folders and files in the folder tree too.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runWatermark(cmd, func(typ string, id string) error {
+			var err error
+			if typ == "file" {
+				err = goboxer.NewFile(apiConn).RemoveWatermark(id)
+			} else {
+				err = goboxer.NewFolder(apiConn).RemoveWatermark(id)
+			}
+			// not watermarked
+			if xerrors.Is(err, goboxer.ErrNotFound) {
+				return nil
+			}
+			return err
+		})
+	},
+}
+
+// runWatermark calls fn for the item specified by flags, and for all the items in the folder tree if recursive.
+func runWatermark(cmd *cobra.Command, fn func(typ string, id string) error) {
+	err := createGoboxerApiConn()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	id := cmd.Flag("id").Value.String()
+	typ := "folder"
+	if cmd.Flag("file").Value.String() == "true" {
+		typ = "file"
+	}
+	recursive := typ == "folder" && cmd.Flag("recursive").Value.String() == "true"
+
+	// output result
+	failed := 0
+	fmt.Printf("%s,%s,%s\n", "type", "id", "result")
+	err = walkWatermarkTargets(typ, id, recursive, func(typ string, id string) {
+		result := "ok"
+		if err := fn(typ, id); err != nil {
+			result = fmt.Sprintf("failed: %v", err)
+			failed++
+		}
+		fmt.Printf("%s,%s,%s\n", typ, id, result)
+	})
+	if err != nil {
+		fmt.Printf("%+v\n", xerrors.Errorf("failed to list folder items: %w", err))
+		os.Exit(1)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// walkWatermarkTargets calls fn for the item, and for the files and folders in the folder tree if recursive.
+// Web links are skipped since they can not be watermarked.
+func walkWatermarkTargets(typ string, id string, recursive bool, fn func(typ string, id string)) error {
+	fn(typ, id)
+	if !recursive {
+		return nil
+	}
+	items, err := goboxer.NewFolder(apiConn).FolderItemAll(id, "id", "ASC", []string{"type", "id"})
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		switch v := item.(type) {
+		case *goboxer.Folder:
+			if err := walkWatermarkTargets("folder", *v.ID, true, fn); err != nil {
+				return err
+			}
+		case *goboxer.File:
+			fn("file", *v.ID)
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(watermarkCmd)
+	watermarkCmd.AddCommand(watermarkGetCmd)
+	watermarkCmd.AddCommand(watermarkApplyCmd)
+	watermarkCmd.AddCommand(watermarkRemoveCmd)
+
+	for _, c := range []*cobra.Command{watermarkGetCmd, watermarkApplyCmd, watermarkRemoveCmd} {
+		c.Flags().StringP("id", "i", "", "folder id (or file id with --file)")
+		c.MarkFlagRequired("id")
+		c.Flags().BoolP("file", "f", false, "the id is a file id")
+	}
+	watermarkApplyCmd.Flags().BoolP("recursive", "r", false, "apply to all the sub folders and files")
+	watermarkRemoveCmd.Flags().BoolP("recursive", "r", false, "remove from all the sub folders and files")
+}
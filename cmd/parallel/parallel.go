@@ -1,14 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/jparound30/goboxer"
+	"golang.org/x/xerrors"
 )
 
 var (
@@ -61,44 +61,34 @@ func main() {
 		//"69649310195","69649755031",
 	}
 
-	batchRequest := goboxer.NewBatchRequest(apiConn)
+	// up to 10 requests per second, sent through batch requests of 20 sub requests
+	apiConn.RateLimiter = goboxer.NewRateLimiter(10, 10)
+	executor := goboxer.NewExecutor(4)
+	executor.BatchSize = 20
+	executor.Progress = func(stats goboxer.ExecutorStats) {
+		fmt.Printf("\tprogress: %d/%d succeeded, %d failed\n", stats.Succeeded, len(targetFolderIds), stats.Failed)
+	}
 
-	var br []*goboxer.Request
+	var jobs []*goboxer.Job
 	for _, id := range targetFolderIds {
+		id := id
 		folder := goboxer.NewFolder(apiConn)
-		br = append(br, folder.CollaborationsReq(id, goboxer.CollaborationAllFields))
+		jobs = append(jobs, &goboxer.Job{
+			Name:    id,
+			Request: folder.CollaborationsReq(id, goboxer.CollaborationAllFields),
+			OnResponse: func(resp *goboxer.Response) error {
+				fmt.Printf("\t[%s]: status:%d\n", id, resp.ResponseCode)
+				return nil
+			},
+		})
 	}
 
-	for counter := 1; counter < 21; counter++ {
-		fmt.Printf("===START c=%d  ===================================\n", counter)
-		response, err := batchRequest.ExecuteBatch(br)
-		if err != nil {
-			return
-		}
-		if response.ResponseCode == http.StatusOK {
-			requests := response.Responses
-			raMax := 0
-			for i, v := range requests {
-				status := v.ResponseCode
-				fmt.Printf("\t[%d]: status:%d\n", i, status)
-				fmt.Printf("\t[%d]: header:%s\n", i, v.Headers)
-				if status == http.StatusTooManyRequests {
-					ra, _ := strconv.Atoi(v.Headers.Get(goboxer.HttpHeaderRetryAfter))
-					if raMax < ra {
-						raMax = ra
-					}
-				}
-			}
-			if raMax > 0 {
-				time.Sleep(time.Duration(raMax) * time.Second)
-			}
-		} else if response.ResponseCode == http.StatusTooManyRequests {
-			t, _ := strconv.Atoi(response.Headers.Get(goboxer.HttpHeaderRetryAfter))
-			time.Sleep(time.Duration(t) * time.Second)
-		} else {
-			fmt.Printf("ERRORRRRRRRRRRRaasdfsdfasdfasdfasd\t status:%d\n", response.ResponseCode)
+	err = executor.RunJobs(context.Background(), jobs)
+	var executorError *goboxer.ExecutorError
+	if xerrors.As(err, &executorError) {
+		for _, jobError := range executorError.Errors {
+			fmt.Printf("ERROR: %v\n", jobError)
 		}
-		fmt.Printf("===END  =======================================\n")
 	}
 	end := time.Now()
 	fmt.Printf("[END  ] %s\n", end)
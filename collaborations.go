@@ -52,8 +52,8 @@ func (c *Collaboration) ResourceType() BoxResourceType {
 	return CollaborationResource
 }
 
-func NewCollaboration(api *APIConn) *Collaboration {
-	return &Collaboration{apiInfo: &apiInfo{api: api}}
+func NewCollaboration(api Connection) *Collaboration {
+	return &Collaboration{apiInfo: api.connInfo()}
 }
 
 func (c *Collaboration) String() string {
@@ -84,7 +84,14 @@ func (c *Collaboration) GetInfoReq(collaborationId string, fields []string) *Req
 		query = fmt.Sprintf("?%s", fieldsParam)
 	}
 
-	return NewRequest(c.apiInfo.api, url+query, GET, nil, nil)
+	return c.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get Collaboration
+//
+// GetInfoBatch adds GetInfoReq to the batch request, and returns the handle of the collaboration.
+func (c *Collaboration) GetInfoBatch(batch *BatchRequest, collaborationId string, fields []string) *PendingCollaboration {
+	return batch.addCollaboration(c.GetInfoReq(collaborationId, fields))
 }
 
 // Get Collaboration
@@ -100,10 +107,10 @@ func (c *Collaboration) GetInfo(collaborationId string, fields []string) (*Colla
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &Collaboration{apiInfo: &apiInfo{api: c.apiInfo.api}}
+	r := &Collaboration{apiInfo: c.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -183,7 +190,14 @@ func (c *Collaboration) CreateReq(targetItem ItemMini, grantedTo UserGroupMini,
 		CanViewPath:  canViewPath,
 	}
 	bodyBytes, _ := json.Marshal(body)
-	return NewRequest(c.apiInfo.api, url+query, POST, nil, bytes.NewReader(bodyBytes))
+	return c.apiInfo.newRequest(url+query, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Create Collaboration
+//
+// CreateBatch adds CreateReq to the batch request, and returns the handle of the collaboration.
+func (c *Collaboration) CreateBatch(batch *BatchRequest, targetItem ItemMini, grantedTo UserGroupMini, role Role, canViewPath *bool, fields []string, notify bool) *PendingCollaboration {
+	return batch.addCollaboration(c.CreateReq(targetItem, grantedTo, role, canViewPath, fields, notify))
 }
 
 // Create Collaboration
@@ -198,10 +212,10 @@ func (c *Collaboration) Create(targetItem ItemMini, grantedTo UserGroupMini, rol
 	}
 
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &Collaboration{apiInfo: &apiInfo{api: c.apiInfo.api}}
+	r := &Collaboration{apiInfo: c.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -212,8 +226,9 @@ func (c *Collaboration) Create(targetItem ItemMini, grantedTo UserGroupMini, rol
 // Update Collaboration
 //
 // Update a collaboration.
+// The etag of the collaboration can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#edit-a-collaboration
-func (c *Collaboration) UpdateReq(collaborationId string, role Role, status *CollaborationStatus, canViewPath *bool, fields []string) *Request {
+func (c *Collaboration) UpdateReq(collaborationId string, role Role, status *CollaborationStatus, canViewPath *bool, ifMatch string, fields []string) *Request {
 	b := struct {
 		Role        Role                 `json:"role"`
 		Status      *CollaborationStatus `json:"status,omitempty"`
@@ -233,15 +248,23 @@ func (c *Collaboration) UpdateReq(collaborationId string, role Role, status *Col
 
 	}
 	bodyBytes, _ := json.Marshal(b)
-	return NewRequest(c.apiInfo.api, url+query, PUT, nil, bytes.NewReader(bodyBytes))
+	return c.apiInfo.newRequest(url+query, PUT, nil, bytes.NewReader(bodyBytes)).IfMatch(ifMatch)
+}
+
+// Update Collaboration
+//
+// UpdateBatch adds UpdateReq to the batch request, and returns the handle of the collaboration.
+func (c *Collaboration) UpdateBatch(batch *BatchRequest, collaborationId string, role Role, status *CollaborationStatus, canViewPath *bool, ifMatch string, fields []string) *PendingCollaboration {
+	return batch.addCollaboration(c.UpdateReq(collaborationId, role, status, canViewPath, ifMatch, fields))
 }
 
 // Update Collaboration
 //
 // Update a collaboration.
+// The etag of the collaboration can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#edit-a-collaboration
-func (c *Collaboration) Update(collaborationId string, role Role, status *CollaborationStatus, canViewPath *bool, fields []string) (*Collaboration, error) {
-	req := c.UpdateReq(collaborationId, role, status, canViewPath, fields)
+func (c *Collaboration) Update(collaborationId string, role Role, status *CollaborationStatus, canViewPath *bool, ifMatch string, fields []string) (*Collaboration, error) {
+	req := c.UpdateReq(collaborationId, role, status, canViewPath, ifMatch, fields)
 	resp, err := req.Send()
 	if err != nil {
 		return nil, err
@@ -249,7 +272,7 @@ func (c *Collaboration) Update(collaborationId string, role Role, status *Collab
 
 	switch resp.ResponseCode {
 	case http.StatusOK:
-		r := &Collaboration{apiInfo: &apiInfo{api: c.apiInfo.api}}
+		r := &Collaboration{apiInfo: c.apiInfo}
 		err = UnmarshalJSONWrapper(resp.Body, r)
 		if err != nil {
 			return nil, err
@@ -261,26 +284,35 @@ func (c *Collaboration) Update(collaborationId string, role Role, status *Collab
 		// A 204 response is returned in this case.
 		return nil, nil
 	default:
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 }
 
 // Delete Collaboration
 //
 // Delete a collaboration.
+// The etag of the collaboration can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#edit-a-collaboration
-func (c *Collaboration) DeleteReq(collaborationId string) *Request {
+func (c *Collaboration) DeleteReq(collaborationId string, ifMatch string) *Request {
 	var url string
 	url = fmt.Sprintf("%s%s%s", c.apiInfo.api.BaseURL, "collaborations/", collaborationId)
-	return NewRequest(c.apiInfo.api, url, DELETE, nil, nil)
+	return c.apiInfo.newRequest(url, DELETE, nil, nil).IfMatch(ifMatch)
+}
+
+// Delete Collaboration
+//
+// DeleteBatch adds DeleteReq to the batch request, and returns the handle of its error.
+func (c *Collaboration) DeleteBatch(batch *BatchRequest, collaborationId string, ifMatch string) *PendingNoContent {
+	return batch.addNoContent(c.DeleteReq(collaborationId, ifMatch))
 }
 
 // Delete Collaboration
 //
 // Delete a collaboration.
+// The etag of the collaboration can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#edit-a-collaboration
-func (c *Collaboration) Delete(collaborationId string) error {
-	req := c.DeleteReq(collaborationId)
+func (c *Collaboration) Delete(collaborationId string, ifMatch string) error {
+	req := c.DeleteReq(collaborationId, ifMatch)
 	resp, err := req.Send()
 	if err != nil {
 		return err
@@ -290,7 +322,7 @@ func (c *Collaboration) Delete(collaborationId string) error {
 	case http.StatusNoContent:
 		return nil
 	default:
-		err = newApiStatusError(resp.Body)
+		err = newApiStatusError(resp)
 		return err
 	}
 }
@@ -310,7 +342,14 @@ func (c *Collaboration) PendingCollaborationsReq(offset int, limit int, fields [
 		query = query + fmt.Sprintf("&%s", fieldsParams)
 	}
 
-	return NewRequest(c.apiInfo.api, url+query, GET, nil, nil)
+	return c.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Pending Collaborations
+//
+// PendingCollaborationsBatch adds PendingCollaborationsReq to the batch request, and returns the handle of the collaborations.
+func (c *Collaboration) PendingCollaborationsBatch(batch *BatchRequest, offset int, limit int, fields []string) *PendingCollaborations {
+	return batch.addCollaborations(c.PendingCollaborationsReq(offset, limit, fields))
 }
 
 // Get all pending collaboration invites for a user.
@@ -322,7 +361,7 @@ func (c *Collaboration) PendingCollaborations(offset int, limit int, fields []st
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, offset, limit, 0, newApiStatusError(resp.Body)
+		return nil, offset, limit, 0, newApiStatusError(resp)
 	}
 
 	r := struct {
@@ -341,3 +380,22 @@ func (c *Collaboration) PendingCollaborations(offset int, limit int, fields []st
 	}
 	return r.Entries, r.Offset, r.Limit, r.TotalCount, nil
 }
+
+// Get All Pending Collaborations
+//
+// PendingCollaborationsIter returns the iterator of all the pending collaborations of the user. If pageSize is 0, 100 is used.
+func (c *Collaboration) PendingCollaborationsIter(fields []string, pageSize int) *CollaborationIterator {
+	return &CollaborationIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := c.PendingCollaborations(offset, limit, fields)
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get All Pending Collaborations
+//
+// PendingCollaborationsAll returns all the pending collaborations of the user.
+func (c *Collaboration) PendingCollaborationsAll(fields []string) ([]*Collaboration, error) {
+	var r []*Collaboration
+	err := c.PendingCollaborationsIter(fields, 1000).allInto(&r)
+	return r, err
+}
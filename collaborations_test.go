@@ -1268,6 +1268,7 @@ func TestCollaboration_UpdateReq(t *testing.T) {
 		role            Role
 		status          *CollaborationStatus
 		canViewPath     *bool
+		ifMatch         string
 		fields          []string
 	}
 	tests := []struct {
@@ -1336,7 +1337,7 @@ func TestCollaboration_UpdateReq(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := tt.target
 
-			got := c.UpdateReq(tt.args.collaborationId, tt.args.role, tt.args.status, tt.args.canViewPath, tt.args.fields)
+			got := c.UpdateReq(tt.args.collaborationId, tt.args.role, tt.args.status, tt.args.canViewPath, tt.args.ifMatch, tt.args.fields)
 
 			opts := diffCompOptions(Collaboration{}, APIConn{})
 			opt := cmpopts.IgnoreUnexported(Request{})
@@ -1424,6 +1425,7 @@ func TestCollaboration_Update(t *testing.T) {
 		role            Role
 		status          *CollaborationStatus
 		canViewPath     *bool
+		ifMatch         string
 		fields          []string
 	}
 	tests := []struct {
@@ -1439,6 +1441,7 @@ func TestCollaboration_Update(t *testing.T) {
 				VIEWER_UPLOADER,
 				nil,
 				nil,
+				"",
 				[]string{"type", "id"},
 			},
 			normal,
@@ -1451,6 +1454,7 @@ func TestCollaboration_Update(t *testing.T) {
 				VIEWER_UPLOADER,
 				nil,
 				nil,
+				"",
 				[]string{"type", "id"},
 			},
 			nil,
@@ -1463,6 +1467,7 @@ func TestCollaboration_Update(t *testing.T) {
 				VIEWER_UPLOADER,
 				nil,
 				nil,
+				"",
 				[]string{"type", "id"},
 			},
 			nil,
@@ -1475,6 +1480,7 @@ func TestCollaboration_Update(t *testing.T) {
 				VIEWER_UPLOADER,
 				nil,
 				nil,
+				"",
 				[]string{"type", "id"},
 			},
 			normal,
@@ -1486,6 +1492,7 @@ func TestCollaboration_Update(t *testing.T) {
 				VIEWER_UPLOADER,
 				nil,
 				nil,
+				"",
 				[]string{"type", "id"},
 			},
 			nil,
@@ -1503,7 +1510,7 @@ func TestCollaboration_Update(t *testing.T) {
 
 			c := NewCollaboration(apiConn)
 
-			got, err := c.Update(tt.args.collaborationId, tt.args.role, tt.args.status, tt.args.canViewPath, tt.args.fields)
+			got, err := c.Update(tt.args.collaborationId, tt.args.role, tt.args.status, tt.args.canViewPath, tt.args.ifMatch, tt.args.fields)
 
 			// Error checks
 			if (err != nil) != tt.wantErr {
@@ -1564,25 +1571,30 @@ func TestCollaboration_DeleteReq(t *testing.T) {
 
 	type args struct {
 		collaborationId string
+		ifMatch         string
 	}
 	tests := []struct {
 		name string
 		args args
 		want *Request
 	}{
-		{"normal", args{"10001"}, w1},
+		{"normal", args{"10001", ""}, w1},
+		{"If-Match", args{"10001", "3"}, w1},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := NewCollaboration(apiConn)
 
-			got := c.DeleteReq(tt.args.collaborationId)
+			got := c.DeleteReq(tt.args.collaborationId, tt.args.ifMatch)
 			opts := diffCompOptions(Collaboration{}, APIConn{})
 			opt := cmpopts.IgnoreUnexported(Request{})
 			opts = append(opts, opt)
 			if diff := cmp.Diff(got, tt.want, opts...); diff != "" {
 				t.Errorf("differs: (-got +want)\n%s", diff)
 			}
+			if ifMatch := got.headers.Get("If-Match"); ifMatch != tt.args.ifMatch {
+				t.Errorf("If-Match = %q, want %q", ifMatch, tt.args.ifMatch)
+			}
 		})
 	}
 }
@@ -1641,6 +1653,7 @@ func TestCollaboration_Delete(t *testing.T) {
 
 	type args struct {
 		collaborationId string
+		ifMatch         string
 	}
 	tests := []struct {
 		name    string
@@ -1652,6 +1665,7 @@ func TestCollaboration_Delete(t *testing.T) {
 		{"normal",
 			args{
 				"10001",
+				"",
 			},
 			normal,
 			false,
@@ -1660,6 +1674,7 @@ func TestCollaboration_Delete(t *testing.T) {
 		{"http error/404",
 			args{
 				"404",
+				"",
 			},
 			nil,
 			true,
@@ -1668,6 +1683,7 @@ func TestCollaboration_Delete(t *testing.T) {
 		{"senderror",
 			args{
 				"999",
+				"",
 			},
 			nil,
 			true,
@@ -1685,7 +1701,7 @@ func TestCollaboration_Delete(t *testing.T) {
 			}
 
 			c := NewCollaboration(apiConn)
-			err := c.Delete(tt.args.collaborationId)
+			err := c.Delete(tt.args.collaborationId, tt.args.ifMatch)
 
 			// Error checks
 			if (err != nil) != tt.wantErr {
@@ -0,0 +1,326 @@
+package goboxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+type CommentItemType string
+
+func (t *CommentItemType) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+	return string(*t)
+}
+
+const (
+	CommentItemFile    CommentItemType = "file"
+	CommentItemComment CommentItemType = "comment"
+)
+
+// CommentItem is the file commented, or the comment replied to.
+type CommentItem struct {
+	Type *CommentItemType `json:"type,omitempty"`
+	ID   *string          `json:"id,omitempty"`
+}
+
+type Comment struct {
+	apiInfo        *apiInfo
+	Type           *string        `json:"type,omitempty"`
+	ID             *string        `json:"id,omitempty"`
+	IsReplyComment *bool          `json:"is_reply_comment,omitempty"`
+	Message        *string        `json:"message,omitempty"`
+	TaggedMessage  *string        `json:"tagged_message,omitempty"`
+	CreatedBy      *UserGroupMini `json:"created_by,omitempty"`
+	CreatedAt      *time.Time     `json:"created_at,omitempty"`
+	ModifiedAt     *time.Time     `json:"modified_at,omitempty"`
+	Item           *CommentItem   `json:"item,omitempty"`
+}
+
+func (c *Comment) ResourceType() BoxResourceType {
+	return CommentResource
+}
+
+func NewComment(api Connection) *Comment {
+	return &Comment{apiInfo: api.connInfo()}
+}
+
+var CommentAllFields = []string{
+	"type", "id", "is_reply_comment", "message", "tagged_message",
+	"created_by", "created_at", "modified_at", "item",
+}
+
+// Mention returns the @mention of the user to be embedded in the message of a comment.
+// e.g. "@[1234:John]"
+func Mention(userId string, name string) string {
+	return fmt.Sprintf("@[%s:%s]", userId, name)
+}
+
+var mentionPattern = regexp.MustCompile(`@\[\d+:[^\]]*\]`)
+
+// commentBody returns the body of the message, sent as tagged_message if it contains @mentions.
+func commentBody(message string) map[string]interface{} {
+	if mentionPattern.MatchString(message) {
+		return map[string]interface{}{"tagged_message": message}
+	}
+	return map[string]interface{}{"message": message}
+}
+
+// Get Comment Info
+//
+// Get information about a comment.
+// https://developer.box.com/reference#get-a-comment
+func (c *Comment) GetInfoReq(commentId string, fields []string) *Request {
+	var url string
+	var query string
+
+	url = fmt.Sprintf("%s%s%s", c.apiInfo.api.BaseURL, "comments/", commentId)
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+	return c.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get Comment Info
+//
+// GetInfoBatch adds GetInfoReq to the batch request, and returns the handle of the comment.
+func (c *Comment) GetInfoBatch(batch *BatchRequest, commentId string, fields []string) *PendingComment {
+	return batch.addComment(c.GetInfoReq(commentId, fields))
+}
+
+// Get Comment Info
+//
+// Get information about a comment.
+// https://developer.box.com/reference#get-a-comment
+func (c *Comment) GetInfo(commentId string, fields []string) (*Comment, error) {
+	req := c.GetInfoReq(commentId, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &Comment{apiInfo: c.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Create Comment
+//
+// Add a comment to a file (itemType CommentItemFile), or reply to a comment (itemType CommentItemComment).
+// The message may contain @mentions of users (see Mention), then it is sent as tagged_message.
+// https://developer.box.com/reference#create-a-comment
+func (c *Comment) CreateReq(itemType CommentItemType, itemId string, message string, fields []string) *Request {
+	var url string
+	var query string
+
+	url = fmt.Sprintf("%s%s", c.apiInfo.api.BaseURL, "comments")
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+
+	data := commentBody(message)
+	data["item"] = &CommentItem{Type: &itemType, ID: &itemId}
+	bodyBytes, _ := json.Marshal(data)
+
+	return c.apiInfo.newRequest(url+query, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Create Comment
+//
+// CreateBatch adds CreateReq to the batch request, and returns the handle of the comment.
+func (c *Comment) CreateBatch(batch *BatchRequest, itemType CommentItemType, itemId string, message string, fields []string) *PendingComment {
+	return batch.addComment(c.CreateReq(itemType, itemId, message, fields))
+}
+
+// Create Comment
+//
+// Add a comment to a file (itemType CommentItemFile), or reply to a comment (itemType CommentItemComment).
+// The message may contain @mentions of users (see Mention), then it is sent as tagged_message.
+// https://developer.box.com/reference#create-a-comment
+func (c *Comment) Create(itemType CommentItemType, itemId string, message string, fields []string) (*Comment, error) {
+	req := c.CreateReq(itemType, itemId, message, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusCreated {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &Comment{apiInfo: c.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reply to Comment
+//
+// Reply to a comment. Same as Create with CommentItemComment.
+// https://developer.box.com/reference#create-a-comment
+func (c *Comment) Reply(commentId string, message string, fields []string) (*Comment, error) {
+	return c.Create(CommentItemComment, commentId, message, fields)
+}
+
+// Update Comment
+//
+// Update the message of a comment.
+// https://developer.box.com/reference#change-a-comments-message
+func (c *Comment) UpdateReq(commentId string, message string, fields []string) *Request {
+	var url string
+	var query string
+
+	url = fmt.Sprintf("%s%s%s", c.apiInfo.api.BaseURL, "comments/", commentId)
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+
+	bodyBytes, _ := json.Marshal(map[string]string{"message": message})
+	return c.apiInfo.newRequest(url+query, PUT, nil, bytes.NewReader(bodyBytes))
+}
+
+// Update Comment
+//
+// UpdateBatch adds UpdateReq to the batch request, and returns the handle of the comment.
+func (c *Comment) UpdateBatch(batch *BatchRequest, commentId string, message string, fields []string) *PendingComment {
+	return batch.addComment(c.UpdateReq(commentId, message, fields))
+}
+
+// Update Comment
+//
+// Update the message of a comment.
+// https://developer.box.com/reference#change-a-comments-message
+func (c *Comment) Update(commentId string, message string, fields []string) (*Comment, error) {
+	req := c.UpdateReq(commentId, message, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &Comment{apiInfo: c.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Delete Comment
+//
+// Permanently delete a comment.
+// https://developer.box.com/reference#delete-a-comment
+func (c *Comment) DeleteReq(commentId string) *Request {
+	var url string
+	url = fmt.Sprintf("%s%s%s", c.apiInfo.api.BaseURL, "comments/", commentId)
+	return c.apiInfo.newRequest(url, DELETE, nil, nil)
+}
+
+// Delete Comment
+//
+// DeleteBatch adds DeleteReq to the batch request, and returns the handle of its error.
+func (c *Comment) DeleteBatch(batch *BatchRequest, commentId string) *PendingNoContent {
+	return batch.addNoContent(c.DeleteReq(commentId))
+}
+
+// Delete Comment
+//
+// Permanently delete a comment.
+// https://developer.box.com/reference#delete-a-comment
+func (c *Comment) Delete(commentId string) error {
+	req := c.DeleteReq(commentId)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
+
+// Get File Comments
+//
+// Retrieves the comments on a file.
+// https://developer.box.com/reference#get-comments-on-a-file
+func (f *File) CommentsReq(fileId string, offset int, limit int, fields []string) *Request {
+	var urlBase string
+	var query string
+	urlBase = fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "files/", fileId, "/comments")
+	if limit > 1000 {
+		limit = 1000
+	}
+	query = fmt.Sprintf("?offset=%d&limit=%d", offset, limit)
+	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
+		query += fmt.Sprintf("&%s", fieldsParams)
+	}
+	return f.apiInfo.newRequest(urlBase+query, GET, nil, nil)
+}
+
+// Get File Comments
+//
+// Retrieves the comments on a file.
+// https://developer.box.com/reference#get-comments-on-a-file
+func (f *File) Comments(fileId string, offset int, limit int, fields []string) (outComments []*Comment, outOffset int, outLimit int, outTotalCount int, err error) {
+	req := f.CommentsReq(fileId, offset, limit, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, 0, 0, 0, newApiStatusError(resp)
+	}
+
+	comments := struct {
+		TotalCount int        `json:"total_count"`
+		Entries    []*Comment `json:"entries"`
+		Offset     int        `json:"offset"`
+		Limit      int        `json:"limit"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &comments)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	for _, c := range comments.Entries {
+		c.apiInfo = f.apiInfo
+	}
+	return comments.Entries, comments.Offset, comments.Limit, comments.TotalCount, nil
+}
+
+// Get All File Comments
+//
+// CommentsIter returns the iterator of all the comments on the file. If pageSize is 0, 100 is used.
+func (f *File) CommentsIter(fileId string, fields []string, pageSize int) *CommentIterator {
+	return &CommentIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := f.Comments(fileId, offset, limit, fields)
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get All File Comments
+//
+// CommentsAll returns all the comments on the file.
+func (f *File) CommentsAll(fileId string, fields []string) ([]*Comment, error) {
+	var r []*Comment
+	err := f.CommentsIter(fileId, fields, 1000).allInto(&r)
+	return r, err
+}
@@ -0,0 +1,65 @@
+package goboxer
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestComment_ResourceType(t *testing.T) {
+	var c *Comment
+	if got := c.ResourceType(); got != CommentResource {
+		t.Errorf("Comment.ResourceType() = %v, want %v", got, CommentResource)
+	}
+}
+
+func TestComment_CreateReq(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	tests := []struct {
+		name     string
+		itemType CommentItemType
+		itemId   string
+		message  string
+		fields   []string
+		wantUrl  string
+		wantBody string
+	}{
+		{"file", CommentItemFile, "10001", "LGTM", nil,
+			url + "/2.0/comments", `{"item":{"type":"file","id":"10001"},"message":"LGTM"}`},
+		{"reply", CommentItemComment, "20001", "thanks", []string{"type", "id"},
+			url + "/2.0/comments?fields=type,id", `{"item":{"type":"comment","id":"20001"},"message":"thanks"}`},
+		{"mention", CommentItemFile, "10001", "please review " + Mention("1234", "John Doe"), nil,
+			url + "/2.0/comments", `{"item":{"type":"file","id":"10001"},"tagged_message":"please review @[1234:John Doe]"}`},
+		{"not a mention", CommentItemFile, "10001", "mail to @[john]", nil,
+			url + "/2.0/comments", `{"item":{"type":"file","id":"10001"},"message":"mail to @[john]"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewComment(apiConn).CreateReq(tt.itemType, tt.itemId, tt.message, tt.fields)
+			if got.Method != POST || got.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want POST %s", got.Method, got.Url, tt.wantUrl)
+			}
+			body, _ := ioutil.ReadAll(got.body)
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestParseResource_Comment(t *testing.T) {
+	r, err := ParseResource([]byte(`{"type":"comment","id":"20001","is_reply_comment":false,"message":"LGTM","item":{"type":"file","id":"10001"}}`))
+	if err != nil {
+		t.Fatalf("ParseResource() error = %v", err)
+	}
+	c, ok := r.(*Comment)
+	if !ok || *c.ID != "20001" || *c.Item.Type != CommentItemFile {
+		t.Fatalf("ParseResource() = %#v", r)
+	}
+	info := &apiInfo{api: commonInit("https://example.com")}
+	setApiInfo(c, info)
+	if c.apiInfo != info {
+		t.Errorf("apiInfo is not set")
+	}
+}
@@ -198,9 +198,9 @@ type Event struct {
 	apiInfo *apiInfo
 }
 
-func NewEvent(api *APIConn) *Event {
+func NewEvent(api Connection) *Event {
 	return &Event{
-		apiInfo: &apiInfo{api: api},
+		apiInfo: api.connInfo(),
 	}
 }
 
@@ -231,7 +231,7 @@ func (e *Event) UserEvent(streamType StreamType, streamPosition string, limit in
 
 	var url string
 	url = fmt.Sprintf("%s%s?%s", e.apiInfo.api.BaseURL, "events", query.String())
-	req := NewRequest(e.apiInfo.api, url, GET, nil, nil)
+	req := e.apiInfo.newRequest(url, GET, nil, nil)
 
 	resp, err := req.Send()
 	if err != nil {
@@ -239,7 +239,7 @@ func (e *Event) UserEvent(streamType StreamType, streamPosition string, limit in
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, "", newApiStatusError(resp.Body)
+		return nil, "", newApiStatusError(resp)
 	}
 	event := &struct {
 		ChunkSize          int         `json:"chunk_size"`
@@ -257,6 +257,9 @@ func (e *Event) UserEvent(streamType StreamType, streamPosition string, limit in
 			setApiInfo(r, e.apiInfo)
 		}
 	}
+	if cache := e.apiInfo.api.Cache; cache != nil {
+		cache.InvalidateEvents(event.Entries)
+	}
 
 	return event.Entries, strconv.FormatInt(event.NextStreamPosition, 10), nil
 }
@@ -288,7 +291,7 @@ func (e *Event) EnterpriseEvent(streamPosition string, eventTypes []EventType, c
 
 	var urlStr string
 	urlStr = fmt.Sprintf("%s%s?%s", e.apiInfo.api.BaseURL, "events", query.String())
-	req := NewRequest(e.apiInfo.api, urlStr, GET, nil, nil)
+	req := e.apiInfo.newRequest(urlStr, GET, nil, nil)
 
 	resp, err := req.Send()
 	if err != nil {
@@ -296,7 +299,7 @@ func (e *Event) EnterpriseEvent(streamPosition string, eventTypes []EventType, c
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, "", newApiStatusError(resp.Body)
+		return nil, "", newApiStatusError(resp)
 	}
 	event := &struct {
 		ChunkSize          int         `json:"chunk_size"`
@@ -0,0 +1,346 @@
+package goboxer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// Job is an operation run by the Executor.
+//
+// If Request is set, the request is sent (through a batch request if Executor.BatchSize is 2 or more)
+// and OnResponse is called with the response. A non-2xx response fails the job with *ApiStatusError.
+// Otherwise Do is called.
+type Job struct {
+	// Name identifies the job in JobError. (optional)
+	Name string
+	// Request is the request sent by the job.
+	Request *Request
+	// OnResponse is called with the 2xx response of Request. (optional)
+	OnResponse func(resp *Response) error
+	// Do is the operation of the job, if Request is nil.
+	Do func() error
+}
+
+// JobError is the error of a failed job.
+type JobError struct {
+	Job      *Job
+	Attempts int
+	Err      error
+}
+
+func (e *JobError) Error() string {
+	name := e.Job.Name
+	if name == "" && e.Job.Request != nil {
+		name = convertMethodStr(e.Job.Request.Method) + " " + e.Job.Request.Url
+	}
+	return fmt.Sprintf("job %q failed after %d attempts: %v", name, e.Attempts, e.Err)
+}
+
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// ExecutorError aggregates the errors of the failed jobs.
+type ExecutorError struct {
+	Errors []*JobError
+}
+
+func (e *ExecutorError) Error() string {
+	return fmt.Sprintf("%d jobs failed, first error: %v", len(e.Errors), e.Errors[0])
+}
+
+// ExecutorStats is the progress of the Executor.
+type ExecutorStats struct {
+	// Started is the number of the jobs taken from the queue.
+	Started int64
+	// Succeeded is the number of the succeeded jobs.
+	Succeeded int64
+	// Failed is the number of the failed jobs.
+	Failed int64
+	// Retried is the number of the retried attempts.
+	Retried int64
+	// Batched is the number of the jobs sent through batch requests.
+	Batched int64
+}
+
+// Executor runs jobs with bounded concurrency.
+//
+// Do of a job failed with 429, 5xx or a network error is retried (honoring "Retry-After") up to MaxAttempts.
+// Request jobs are not retried by the executor, their requests (and sub requests of batch requests)
+// which returned 429 or 5xx are retried by their connection.
+// The rate of the requests is limited by the RateLimiter of their connection (APIConn.RateLimiter).
+//
+//	e := goboxer.NewExecutor(8)
+//	e.BatchSize = 20
+//	err := e.RunJobs(ctx, jobs)
+//	var execErr *goboxer.ExecutorError
+//	if errors.As(err, &execErr) { ... }
+type Executor struct {
+	stats ExecutorStats
+
+	// Concurrency is the number of the jobs (or batch requests) run concurrently. If 0, 4 is used.
+	Concurrency int
+	// MaxAttempts is the maximum number of the attempts of Do of a job. If 0, 3 is used.
+	MaxAttempts int
+	// BatchSize is the maximum number of the request jobs sent in a batch request, up to 20.
+	// If 0 or 1, the requests are sent one by one.
+	// Queued request jobs are sent in a batch request only if they are sent to BaseURL.
+	BatchSize int
+	// Progress is called with the stats every time a job is finished. (optional)
+	// It is called from the worker goroutines concurrently.
+	Progress func(stats ExecutorStats)
+
+	mu     sync.Mutex
+	errors []*JobError
+}
+
+// NewExecutor returns the Executor which runs up to concurrency jobs concurrently.
+func NewExecutor(concurrency int) *Executor {
+	return &Executor{Concurrency: concurrency}
+}
+
+// Stats returns the progress of the Executor.
+func (e *Executor) Stats() ExecutorStats {
+	return ExecutorStats{
+		Started:   atomic.LoadInt64(&e.stats.Started),
+		Succeeded: atomic.LoadInt64(&e.stats.Succeeded),
+		Failed:    atomic.LoadInt64(&e.stats.Failed),
+		Retried:   atomic.LoadInt64(&e.stats.Retried),
+		Batched:   atomic.LoadInt64(&e.stats.Batched),
+	}
+}
+
+// RunJobs runs the jobs. See Run.
+func (e *Executor) RunJobs(ctx context.Context, jobs []*Job) error {
+	ch := make(chan *Job, len(jobs))
+	for _, job := range jobs {
+		ch <- job
+	}
+	close(ch)
+	return e.Run(ctx, ch)
+}
+
+// Run runs the jobs received from jobs until jobs is closed, and waits for them.
+// Request jobs already queued in jobs are sent through a batch request, so buffer jobs to batch them.
+//
+// When ctx is done, no more jobs are started, the requests in flight and waits for retries are aborted,
+// and the running jobs are waited. Do of the running jobs is not aborted.
+// Returns *ExecutorError if some jobs failed, ctx.Err() if ctx is done and no jobs failed, or nil.
+func (e *Executor) Run(ctx context.Context, jobs <-chan *Job) error {
+	concurrency := e.Concurrency
+	if concurrency < 1 {
+		concurrency = 4
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.work(ctx, jobs)
+		}()
+	}
+	wg.Wait()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.errors) != 0 {
+		errs := e.errors
+		e.errors = nil
+		return &ExecutorError{Errors: errs}
+	}
+	return ctx.Err()
+}
+
+func (e *Executor) work(ctx context.Context, jobs <-chan *Job) {
+	for {
+		var job *Job
+		var ok bool
+		select {
+		case job, ok = <-jobs:
+		case <-ctx.Done():
+			return
+		}
+		if !ok || ctx.Err() != nil {
+			return
+		}
+		group := []*Job{job}
+		closed := false
+		batchSize := e.BatchSize
+		if batchSize > maxBatchRequests {
+			batchSize = maxBatchRequests
+		}
+		if batchSize > 1 && job.Request.batchable() {
+		gather:
+			for len(group) < batchSize {
+				select {
+				case j, ok := <-jobs:
+					if !ok {
+						closed = true
+						break gather
+					}
+					group = append(group, j)
+				default:
+					break gather
+				}
+			}
+		}
+		e.runGroup(ctx, group)
+		if closed {
+			return
+		}
+	}
+}
+
+// runGroup runs the jobs, sending the batchable requests of the same connection through a batch request.
+func (e *Executor) runGroup(ctx context.Context, jobs []*Job) {
+	atomic.AddInt64(&e.stats.Started, int64(len(jobs)))
+	var batches [][]*Job
+	conns := map[*APIConn]int{}
+	for _, job := range jobs {
+		if !job.Request.batchable() {
+			e.run(ctx, job)
+			continue
+		}
+		i, ok := conns[job.Request.apiConn]
+		if !ok {
+			i = len(batches)
+			conns[job.Request.apiConn] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], job)
+	}
+	for _, batch := range batches {
+		if len(batch) == 1 {
+			e.run(ctx, batch[0])
+			continue
+		}
+		e.runBatch(ctx, batch)
+	}
+}
+
+// runBatch sends the requests of the jobs in a batch request.
+// If the batch request failed, only the jobs whose requests were never sent to Box are run one by one.
+// The jobs whose requests may have been executed by Box (e.g. the batch request returned 5xx or the connection was lost)
+// fail with the error of the batch request, so items are not created (or copied, ...) twice.
+func (e *Executor) runBatch(ctx context.Context, jobs []*Job) {
+	requests := make([]*Request, len(jobs))
+	for i, job := range jobs {
+		requests[i] = job.Request
+	}
+	resp, unsent, err := NewBatchRequest(jobs[0].Request.apiConn).executeBatch(ctx, requests)
+	if err != nil {
+		logWarn("goboxer executor batch request failed", "error", err)
+	}
+	for i, job := range jobs {
+		switch {
+		case resp.Responses[i] != nil:
+			atomic.AddInt64(&e.stats.Batched, 1)
+			e.finish(job, 1, job.handle(resp.Responses[i]))
+		case i >= unsent:
+			e.run(ctx, job)
+		default:
+			e.finish(job, 1, err)
+		}
+	}
+}
+
+// run runs the job, and retries Do of the job if needed.
+func (e *Executor) run(ctx context.Context, job *Job) {
+	maxAttempts := e.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 3
+	}
+	attempt := 1
+	err := job.attempt(ctx)
+	for err != nil && job.Request == nil && attempt < maxAttempts && isRetryableError(err) {
+		wait := time.Duration(retryAfterSeconds(retryAfterHeader(err), attempt)) * time.Second
+		logInfo("goboxer executor retry job", "error", err, "retry_after_sec", wait.Seconds())
+		if err := sleepContext(ctx, wait); err != nil {
+			e.finish(job, attempt, err)
+			return
+		}
+		atomic.AddInt64(&e.stats.Retried, 1)
+		attempt++
+		err = job.attempt(ctx)
+	}
+	e.finish(job, attempt, err)
+}
+
+func (e *Executor) finish(job *Job, attempts int, err error) {
+	if err != nil {
+		atomic.AddInt64(&e.stats.Failed, 1)
+		e.mu.Lock()
+		e.errors = append(e.errors, &JobError{Job: job, Attempts: attempts, Err: err})
+		e.mu.Unlock()
+	} else {
+		atomic.AddInt64(&e.stats.Succeeded, 1)
+	}
+	if e.Progress != nil {
+		e.Progress(e.Stats())
+	}
+}
+
+// attempt runs the job once.
+func (job *Job) attempt(ctx context.Context) error {
+	if job.Request == nil {
+		if job.Do == nil {
+			return xerrors.New("job has neither Request nor Do")
+		}
+		return job.Do()
+	}
+	resp, err := job.Request.SendContext(ctx)
+	if err != nil {
+		return err
+	}
+	return job.handle(resp)
+}
+
+// handle checks the response of the request, and calls OnResponse.
+func (job *Job) handle(resp *Response) error {
+	resp, err := (&pending{req: job.Request, resp: resp}).response()
+	if err != nil {
+		return err
+	}
+	if job.OnResponse != nil {
+		return job.OnResponse(resp)
+	}
+	return nil
+}
+
+// batchable reports whether the request can be sent as a sub request of a batch request.
+func (req *Request) batchable() bool {
+	if req == nil || req.apiConn == nil || req.apiConn.BaseURL == "" {
+		return false
+	}
+	if req.apiConn.BaseUploadURL != "" && strings.HasPrefix(req.Url, req.apiConn.BaseUploadURL) {
+		return false
+	}
+	return strings.HasPrefix(req.Url, req.apiConn.BaseURL)
+}
+
+// isRetryableError reports whether the error is 429 or 5xx of Box API, or a network error.
+func isRetryableError(err error) bool {
+	var apiErr *ApiStatusError
+	if xerrors.As(err, &apiErr) {
+		return isResponseRetryable(apiErr.Status)
+	}
+	var netErr net.Error
+	return xerrors.As(err, &netErr)
+}
+
+// retryAfterHeader returns the response headers of the error, which may have "Retry-After".
+func retryAfterHeader(err error) http.Header {
+	var apiErr *ApiStatusError
+	if xerrors.As(err, &apiErr) {
+		return apiErr.Headers
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package goboxer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+func TestExecutor_RunJobs_Batch(t *testing.T) {
+	var batches []int
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+			if r.URL.Path != "/2.0/batch" {
+				_, _ = w.Write([]byte(`{"type":"file","id":"single"}`))
+				return
+			}
+			var body struct {
+				Requests []struct {
+					RelativeURL string `json:"relative_url"`
+				} `json:"requests"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			batches = append(batches, len(body.Requests))
+			mu.Unlock()
+			var responses []map[string]interface{}
+			for _, sub := range body.Requests {
+				id := strings.TrimPrefix(sub.RelativeURL, "/files/")
+				if id == "404" {
+					responses = append(responses, map[string]interface{}{"status": 404, "response": map[string]interface{}{"type": "error", "status": 404, "code": "not_found"}})
+					continue
+				}
+				responses = append(responses, map[string]interface{}{"status": 200, "response": map[string]interface{}{"type": "file", "id": id}})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+		},
+	))
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	var got sync.Map
+	var jobs []*Job
+	for i := 0; i < 45; i++ {
+		jobs = append(jobs, &Job{
+			Request: NewFile(apiConn).GetFileInfoReq(strconv.Itoa(i), false, nil),
+			OnResponse: func(resp *Response) error {
+				file := &File{}
+				if err := UnmarshalJSONWrapper(resp.Body, file); err != nil {
+					return err
+				}
+				got.Store(*file.ID, true)
+				return nil
+			},
+		})
+	}
+	jobs = append(jobs, &Job{Name: "missing", Request: NewFile(apiConn).GetFileInfoReq("404", false, nil)})
+
+	var progress int64
+	e := NewExecutor(1)
+	e.BatchSize = 20
+	e.Progress = func(stats ExecutorStats) { atomic.AddInt64(&progress, 1) }
+
+	err := e.RunJobs(context.Background(), jobs)
+	var execErr *ExecutorError
+	if !xerrors.As(err, &execErr) || len(execErr.Errors) != 1 {
+		t.Fatalf("RunJobs() error = %v, want ExecutorError of 1 job", err)
+	}
+	if jobErr := execErr.Errors[0]; jobErr.Job.Name != "missing" || !xerrors.Is(jobErr, ErrNotFound) {
+		t.Errorf("unexpected job error: %v", jobErr)
+	}
+	for i := 0; i < 45; i++ {
+		if _, ok := got.Load(strconv.Itoa(i)); !ok {
+			t.Errorf("file %d is not handled", i)
+		}
+	}
+	if fmt.Sprint(batches) != "[20 20 6]" {
+		t.Errorf("batch sizes = %v, want [20 20 6]", batches)
+	}
+	want := ExecutorStats{Started: 46, Succeeded: 45, Failed: 1, Batched: 46}
+	if stats := e.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+	if progress != 46 {
+		t.Errorf("Progress is called %d times, want 46", progress)
+	}
+}
+
+func TestExecutor_Retry(t *testing.T) {
+	var rateLimited, unavailable, notFound int32
+	jobs := []*Job{
+		{Name: "ok", Do: func() error { return nil }},
+		{Name: "rateLimited", Do: func() error {
+			if atomic.AddInt32(&rateLimited, 1) < 3 {
+				return &ApiStatusError{Status: http.StatusTooManyRequests, Headers: http.Header{HttpHeaderRetryAfter: []string{"0"}}}
+			}
+			return nil
+		}},
+		{Name: "unavailable", Do: func() error {
+			atomic.AddInt32(&unavailable, 1)
+			return &ApiStatusError{Status: http.StatusServiceUnavailable, Headers: http.Header{HttpHeaderRetryAfter: []string{"0"}}}
+		}},
+		{Name: "notFound", Do: func() error {
+			atomic.AddInt32(&notFound, 1)
+			return &ApiStatusError{Status: http.StatusNotFound}
+		}},
+		{Name: "empty"},
+	}
+
+	e := NewExecutor(0)
+	e.MaxAttempts = 3
+	err := e.RunJobs(context.Background(), jobs)
+	var execErr *ExecutorError
+	if !xerrors.As(err, &execErr) || len(execErr.Errors) != 3 {
+		t.Fatalf("RunJobs() error = %v, want ExecutorError of 3 jobs", err)
+	}
+	if rateLimited != 3 || unavailable != 3 || notFound != 1 {
+		t.Errorf("called rateLimited %d, unavailable %d, notFound %d times", rateLimited, unavailable, notFound)
+	}
+	for _, jobErr := range execErr.Errors {
+		if jobErr.Job.Name == "unavailable" && jobErr.Attempts != 3 {
+			t.Errorf("unavailable failed after %d attempts, want 3", jobErr.Attempts)
+		}
+	}
+	want := ExecutorStats{Started: 5, Succeeded: 2, Failed: 3, Retried: 4}
+	if stats := e.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestExecutor_RunJobs_BatchFailure(t *testing.T) {
+	var batches []int
+	var singles []string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+			if r.URL.Path != "/2.0/batch" {
+				singles = append(singles, r.URL.Path)
+				_, _ = w.Write([]byte(`{"type":"file","id":"single"}`))
+				return
+			}
+			var body struct {
+				Requests []struct {
+					RelativeURL string `json:"relative_url"`
+				} `json:"requests"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			batches = append(batches, len(body.Requests))
+			if len(batches) != 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"type":"error","status":400,"code":"bad_request"}`))
+				return
+			}
+			var responses []map[string]interface{}
+			for _, sub := range body.Requests {
+				if sub.RelativeURL == "/files/5" {
+					responses = append(responses, map[string]interface{}{"status": 429, "headers": map[string]interface{}{"Retry-After": 0}})
+					continue
+				}
+				responses = append(responses, map[string]interface{}{"status": 200, "response": map[string]interface{}{"type": "file", "id": "1"}})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+		},
+	))
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+	apiConn.MaxRequestAttempts = 3
+
+	var jobs []*Job
+	for i := 0; i < 25; i++ {
+		jobs = append(jobs, &Job{Name: strconv.Itoa(i), Request: NewFile(apiConn).GetFileInfoReq(strconv.Itoa(i), false, nil)})
+	}
+	e := NewExecutor(1)
+	e.BatchSize = 50
+	err := e.RunJobs(context.Background(), jobs)
+
+	// 20 (capped): 5 is rate limited, and its retry fails / 5: the batch request fails
+	if fmt.Sprint(batches) != "[20 1 5]" {
+		t.Errorf("batch sizes = %v, want [20 1 5]", batches)
+	}
+	// the requests of the failed batch request may have been executed, so they are not sent again
+	if len(singles) != 0 {
+		t.Errorf("requests sent one by one = %v", singles)
+	}
+	var execErr *ExecutorError
+	if !xerrors.As(err, &execErr) || len(execErr.Errors) != 6 {
+		t.Fatalf("RunJobs() error = %v, want ExecutorError of 6 jobs", err)
+	}
+	for _, jobErr := range execErr.Errors {
+		var apiErr *ApiStatusError
+		switch {
+		case jobErr.Job.Name == "5":
+			if !xerrors.Is(jobErr, ErrRateLimited) {
+				t.Errorf("job 5 error = %v, want ErrRateLimited", jobErr)
+			}
+		case !xerrors.As(jobErr, &apiErr) || apiErr.Status != http.StatusBadRequest:
+			t.Errorf("job %s error = %v, want the error of the batch request", jobErr.Job.Name, jobErr)
+		}
+	}
+	want := ExecutorStats{Started: 25, Succeeded: 19, Failed: 6, Batched: 20}
+	if stats := e.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+
+	// the requests of the batch request never sent are sent one by one
+	apiConn.Transport = batchUnreachableTransport{}
+	batches, singles = nil, nil
+	jobs = nil
+	for i := 0; i < 5; i++ {
+		jobs = append(jobs, &Job{Name: strconv.Itoa(i), Request: NewFile(apiConn).GetFileInfoReq(strconv.Itoa(i), false, nil)})
+	}
+	e = NewExecutor(1)
+	e.BatchSize = 20
+	if err := e.RunJobs(context.Background(), jobs); err != nil {
+		t.Errorf("RunJobs() error = %v", err)
+	}
+	if len(batches) != 0 || fmt.Sprint(singles) != "[/2.0/files/0 /2.0/files/1 /2.0/files/2 /2.0/files/3 /2.0/files/4]" {
+		t.Errorf("batch sizes = %v, requests sent one by one = %v", batches, singles)
+	}
+	want = ExecutorStats{Started: 5, Succeeded: 5}
+	if stats := e.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+}
+
+// batchUnreachableTransport fails batch requests without sending them.
+type batchUnreachableTransport struct{}
+
+func (batchUnreachableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/2.0/batch" {
+		return nil, xerrors.New("connection refused")
+	}
+	return transport.RoundTrip(req)
+}
+
+func TestExecutor_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var started int32
+	var jobs []*Job
+	for i := 0; i < 10; i++ {
+		jobs = append(jobs, &Job{Do: func() error {
+			atomic.AddInt32(&started, 1)
+			cancel()
+			return nil
+		}})
+	}
+	err := NewExecutor(1).RunJobs(ctx, jobs)
+	if err != context.Canceled {
+		t.Errorf("RunJobs() error = %v, want context.Canceled", err)
+	}
+	if started != 1 {
+		t.Errorf("%d jobs started after the cancellation", started)
+	}
+
+	// the request in flight is aborted
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		},
+	))
+	defer ts.Close()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	job := &Job{Request: NewFile(commonInit(ts.URL)).GetFileInfoReq("1", false, nil)}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	begin := time.Now()
+	err = NewExecutor(1).RunJobs(ctx, []*Job{job})
+	var execErr *ExecutorError
+	if !xerrors.As(err, &execErr) || !xerrors.Is(execErr.Errors[0], context.Canceled) {
+		t.Errorf("RunJobs() error = %v, want context.Canceled", err)
+	}
+	if time.Since(begin) > 5*time.Second {
+		t.Errorf("the request in flight is not aborted")
+	}
+}
@@ -19,10 +19,12 @@ type Lock struct {
 }
 
 // Lock
+//
+// The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#lock-and-unlock
 //
 // TODO consider the receiver type
-func (f *File) LockFileReq(fileId string, expiresAt *time.Time, isDownloadPrevented *bool, fields []string) *Request {
+func (f *File) LockFileReq(fileId string, expiresAt *time.Time, isDownloadPrevented *bool, ifMatch string, fields []string) *Request {
 	var url string
 	var query string
 
@@ -44,25 +46,34 @@ func (f *File) LockFileReq(fileId string, expiresAt *time.Time, isDownloadPreven
 	}
 	bodyBytes, _ := json.Marshal(data)
 
-	return NewRequest(f.apiInfo.api, url+query, PUT, nil, bytes.NewReader(bodyBytes))
+	return f.apiInfo.newRequest(url+query, PUT, nil, bytes.NewReader(bodyBytes)).IfMatch(ifMatch)
+}
+
+// Lock
+//
+// LockFileBatch adds LockFileReq to the batch request, and returns the handle of the file.
+func (f *File) LockFileBatch(batch *BatchRequest, fileId string, expiresAt *time.Time, isDownloadPrevented *bool, ifMatch string, fields []string) *PendingFile {
+	return batch.addFile(f.LockFileReq(fileId, expiresAt, isDownloadPrevented, ifMatch, fields))
 }
 
 // Lock
+//
+// The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#lock-and-unlock
 //
 // TODO consider the receiver type
-func (f *File) LockFile(fileId string, expiresAt *time.Time, isDownloadPrevented *bool, fields []string) (file *File, err error) {
-	req := f.LockFileReq(fileId, expiresAt, isDownloadPrevented, fields)
+func (f *File) LockFile(fileId string, expiresAt *time.Time, isDownloadPrevented *bool, ifMatch string, fields []string) (file *File, err error) {
+	req := f.LockFileReq(fileId, expiresAt, isDownloadPrevented, ifMatch, fields)
 	resp, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	file = &File{apiInfo: &apiInfo{api: f.apiInfo.api}}
+	file = &File{apiInfo: f.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, file)
 	if err != nil {
 		return nil, err
@@ -71,10 +82,12 @@ func (f *File) LockFile(fileId string, expiresAt *time.Time, isDownloadPrevented
 }
 
 // Unlock
+//
+// The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#lock-and-unlock
 //
 // TODO consider the receiver type
-func (f *File) UnlockFileReq(fileId string, fields []string) *Request {
+func (f *File) UnlockFileReq(fileId string, ifMatch string, fields []string) *Request {
 	var url string
 	var query string
 
@@ -86,25 +99,34 @@ func (f *File) UnlockFileReq(fileId string, fields []string) *Request {
 	data := map[string]interface{}{"lock": nil}
 	bodyBytes, _ := json.Marshal(data)
 
-	return NewRequest(f.apiInfo.api, url+query, PUT, nil, bytes.NewReader(bodyBytes))
+	return f.apiInfo.newRequest(url+query, PUT, nil, bytes.NewReader(bodyBytes)).IfMatch(ifMatch)
+}
+
+// Unlock
+//
+// UnlockFileBatch adds UnlockFileReq to the batch request, and returns the handle of the file.
+func (f *File) UnlockFileBatch(batch *BatchRequest, fileId string, ifMatch string, fields []string) *PendingFile {
+	return batch.addFile(f.UnlockFileReq(fileId, ifMatch, fields))
 }
 
 // Unlock
+//
+// The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#lock-and-unlock
 //
 // TODO consider the receiver type
-func (f *File) UnlockFile(fileId string, fields []string) (file *File, err error) {
-	req := f.UnlockFileReq(fileId, fields)
+func (f *File) UnlockFile(fileId string, ifMatch string, fields []string) (file *File, err error) {
+	req := f.UnlockFileReq(fileId, ifMatch, fields)
 	resp, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	file = &File{apiInfo: &apiInfo{api: f.apiInfo.api}}
+	file = &File{apiInfo: f.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, file)
 	if err != nil {
 		return nil, err
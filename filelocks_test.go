@@ -130,7 +130,7 @@ func TestFile_LockFileReq(t *testing.T) {
 			t.Helper()
 
 			f := NewFile(apiConn)
-			got := f.LockFileReq(tt.args.fileId, tt.args.expiresAt, tt.args.isDownloadPrevented, tt.args.fields)
+			got := f.LockFileReq(tt.args.fileId, tt.args.expiresAt, tt.args.isDownloadPrevented, "", tt.args.fields)
 
 			opts := diffCompOptions(APIConn{})
 			opt := cmpopts.IgnoreUnexported(Request{})
@@ -296,7 +296,7 @@ func TestFile_LockFile(t *testing.T) {
 			}
 
 			f := NewFile(apiConn)
-			got, err := f.LockFile(tt.args.fileId, tt.args.expiresAt, tt.args.isDownloadPrevented, tt.args.fields)
+			got, err := f.LockFile(tt.args.fileId, tt.args.expiresAt, tt.args.isDownloadPrevented, "", tt.args.fields)
 
 			// Error checks
 			if (err != nil) != tt.wantErr {
@@ -389,7 +389,7 @@ func TestFile_UnlockFileReq(t *testing.T) {
 			t.Helper()
 
 			f := NewFile(apiConn)
-			got := f.UnlockFileReq(tt.args.fileId, tt.args.fields)
+			got := f.UnlockFileReq(tt.args.fileId, "", tt.args.fields)
 
 			opts := diffCompOptions(APIConn{})
 			opt := cmpopts.IgnoreUnexported(Request{})
@@ -545,7 +545,7 @@ func TestFile_UnlockFile(t *testing.T) {
 			}
 
 			f := NewFile(apiConn)
-			got, err := f.UnlockFile(tt.args.fileId, tt.args.fields)
+			got, err := f.UnlockFile(tt.args.fileId, "", tt.args.fields)
 
 			// Error checks
 			if (err != nil) != tt.wantErr {
@@ -15,37 +15,37 @@ import (
 type File struct {
 	ItemMini
 	apiInfo            *apiInfo
-	FileVersion        *FileVersion    `json:"file_version,omitempty"`
-	Sha1               *string         `json:"sha1,omitempty"`
-	Description        *string         `json:"description,omitempty"`
-	Size               float64         `json:"size,omitempty"`
-	PathCollection     *PathCollection `json:"path_collection,omitempty"`
-	CreatedAt          *time.Time      `json:"created_at,omitempty"`
-	ModifiedAt         *time.Time      `json:"modified_at,omitempty"`
-	TrashedAt          *time.Time      `json:"trashed_at,omitempty"`
-	PurgedAt           *time.Time      `json:"purged_at,omitempty"`
-	ContentCreatedAt   *time.Time      `json:"content_created_at,omitempty"`
-	ContentModifiedAt  *time.Time      `json:"content_modified_at,omitempty"`
-	ExpiresAt          *time.Time      `json:"expires_at,omitempty"`
-	CreatedBy          *UserGroupMini  `json:"created_by,omitempty"`
-	ModifiedBy         *UserGroupMini  `json:"modified_by,omitempty"`
-	OwnedBy            *UserGroupMini  `json:"owned_by,omitempty"`
-	SharedLink         *SharedLink     `json:"shared_link,omitempty"`
-	Parent             *ItemMini       `json:"parent,omitempty"`
-	ItemStatus         *string         `json:"item_status,omitempty"`
-	VersionNumber      *string         `json:"version_number,omitempty"`
-	CommentCount       *int            `json:"comment_count,omitempty"`
-	Permissions        *Permissions    `json:"permissions,omitempty"`
-	Tags               []string        `json:"tags,omitempty"`
-	Lock               *Lock           `json:"lock,omitempty"`
-	Extension          *string         `json:"extension,omitempty"`
-	IsPackage          *bool           `json:"is_package,omitempty"`
-	ExpiringEmbedLink  *string         `json:"expiring_embed_link,omitempty"`
-	WatermarkInfo      *WatermarkInfo  `json:"watermark_info,omitempty"`
-	AllowedInviteeRole []string        `json:"allowed_invitee_roles,omitempty"`
-	IsExternallyOwned  *bool           `json:"is_externally_owned,omitempty"`
-	HasCollaborations  *bool           `json:"has_collaborations,omitempty"`
-	Metadata           *Metadata       `json:"metadata,omitempty"`
+	FileVersion        *FileVersion       `json:"file_version,omitempty"`
+	Sha1               *string            `json:"sha1,omitempty"`
+	Description        *string            `json:"description,omitempty"`
+	Size               float64            `json:"size,omitempty"`
+	PathCollection     *PathCollection    `json:"path_collection,omitempty"`
+	CreatedAt          *time.Time         `json:"created_at,omitempty"`
+	ModifiedAt         *time.Time         `json:"modified_at,omitempty"`
+	TrashedAt          *time.Time         `json:"trashed_at,omitempty"`
+	PurgedAt           *time.Time         `json:"purged_at,omitempty"`
+	ContentCreatedAt   *time.Time         `json:"content_created_at,omitempty"`
+	ContentModifiedAt  *time.Time         `json:"content_modified_at,omitempty"`
+	ExpiresAt          *time.Time         `json:"expires_at,omitempty"`
+	CreatedBy          *UserGroupMini     `json:"created_by,omitempty"`
+	ModifiedBy         *UserGroupMini     `json:"modified_by,omitempty"`
+	OwnedBy            *UserGroupMini     `json:"owned_by,omitempty"`
+	SharedLink         *SharedLink        `json:"shared_link,omitempty"`
+	Parent             *ItemMini          `json:"parent,omitempty"`
+	ItemStatus         *string            `json:"item_status,omitempty"`
+	VersionNumber      *string            `json:"version_number,omitempty"`
+	CommentCount       *int               `json:"comment_count,omitempty"`
+	Permissions        *Permissions       `json:"permissions,omitempty"`
+	Tags               []string           `json:"tags,omitempty"`
+	Lock               *Lock              `json:"lock,omitempty"`
+	Extension          *string            `json:"extension,omitempty"`
+	IsPackage          *bool              `json:"is_package,omitempty"`
+	ExpiringEmbedLink  *string            `json:"expiring_embed_link,omitempty"`
+	WatermarkInfo      *WatermarkInfo     `json:"watermark_info,omitempty"`
+	AllowedInviteeRole []string           `json:"allowed_invitee_roles,omitempty"`
+	IsExternallyOwned  *bool              `json:"is_externally_owned,omitempty"`
+	HasCollaborations  *bool              `json:"has_collaborations,omitempty"`
+	Metadata           *MetadataInstances `json:"metadata,omitempty"`
 
 	changedFlag uint64
 }
@@ -161,9 +161,9 @@ func (f *File) SetParent(parentFolderId string) *File {
 	return f
 }
 
-func NewFile(api *APIConn) *File {
+func NewFile(api Connection) *File {
 	return &File{
-		apiInfo: &apiInfo{api: api},
+		apiInfo: api.connInfo(),
 	}
 }
 
@@ -192,21 +192,35 @@ func (f *File) GetFileInfoReq(fileId string, needExpiringEmbedLink bool, fields
 	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
 		query = fmt.Sprintf("?%s", fieldsParams)
 	}
-	return NewRequest(f.apiInfo.api, url+query, GET, nil, nil)
+	return f.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get File Info
+//
+// GetFileInfoBatch adds GetFileInfoReq to the batch request, and returns the handle of the file.
+func (f *File) GetFileInfoBatch(batch *BatchRequest, fileId string, needExpiringEmbedLink bool, fields []string) *PendingFile {
+	return batch.addFile(f.GetFileInfoReq(fileId, needExpiringEmbedLink, fields))
 }
 func (f *File) GetFileInfo(fileId string, needExpiringEmbedLink bool, fields []string) (*File, error) {
 
 	req := f.GetFileInfoReq(fileId, needExpiringEmbedLink, fields)
-	resp, err := req.Send()
+	var resp *Response
+	var err error
+	if needExpiringEmbedLink {
+		// expiring embed links are not cached
+		resp, err = req.Send()
+	} else {
+		resp, err = req.sendCached()
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &File{apiInfo: &apiInfo{api: f.apiInfo.api}}
+	r := &File{apiInfo: f.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -216,15 +230,15 @@ func (f *File) GetFileInfo(fileId string, needExpiringEmbedLink bool, fields []s
 
 // Download File
 // Retrieves the actual data of the file. An optional version parameter can be set to download a previous version of the file.
+// (e.g. a version returned by FileVersion.GetVersions, nil for the current version)
 // TODO add support for byte-range operation.
 // TODO receive io.Writer ?
-// TODO AS-USER support.
-func (f *File) DownloadFile(fileId string, fileVersion string, boxApiHeader string) (*Response, error) {
+func (f *File) DownloadFile(fileId string, fileVersion *FileVersion, boxApiHeader string) (*Response, error) {
 	var url string
 
 	url = fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "files/", fileId, "/content")
-	if fileVersion != "" {
-		url += fmt.Sprintf("?version=%s", fileVersion)
+	if fileVersion != nil && fileVersion.ID != "" {
+		url += fmt.Sprintf("?version=%s", fileVersion.ID)
 	}
 	var headers http.Header
 	if boxApiHeader != "" {
@@ -232,7 +246,7 @@ func (f *File) DownloadFile(fileId string, fileVersion string, boxApiHeader stri
 		headers.Set("BoxApi", boxApiHeader)
 	}
 
-	req := NewRequest(f.apiInfo.api, url, GET, headers, nil)
+	req := f.apiInfo.newRequest(url, GET, headers, nil)
 
 	resp, err := req.Send()
 	if err != nil {
@@ -246,77 +260,48 @@ func (f *File) DownloadFile(fileId string, fileVersion string, boxApiHeader stri
 		return resp, nil
 
 	default:
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 }
 
-// Upload File
-// Use the Upload API to allow users to add a new file. The user can then upload a file by specifying the destination folder for the file.
-// If the user provides a file name that already exists in the destination folder, the user will receive an error.
-//
-// TODO AS-USER support.
-// TODO Refactoring
-func (f *File) UploadFile(filename string, reader io.Reader, parentFolderId string, contentCreatedAt *time.Time, contentModifiedAt *time.Time, contentMD5 *string) (*File, error) {
-	var url string
-
-	url = fmt.Sprintf("%s%s", f.apiInfo.api.BaseUploadURL, "files/content")
-	attr := map[string]interface{}{}
-	attr["name"] = filename
-	attr["parent"] = map[string]string{"id": parentFolderId}
-	if contentCreatedAt != nil {
-		attr["content_created_at"] = contentCreatedAt.Format(time.RFC3339)
-	}
-	if contentModifiedAt != nil {
-		attr["content_modified_at"] = contentModifiedAt.Format(time.RFC3339)
-	}
-
-	headers := http.Header{}
-	if contentMD5 != nil {
-		headers.Set("Content-MD5", *contentMD5)
-	}
-
-	body := &bytes.Buffer{}
+func buildUploadBody(attr map[string]interface{}, reader io.Reader) (body *bytes.Buffer, contentType string, err error) {
+	body = &bytes.Buffer{}
 	mw := multipart.NewWriter(body)
 	mhAttr, err := mw.CreateFormField("attributes")
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to create attributes part.")
+		return nil, "", newApiOtherError(err, "failed to create attributes part.")
 	}
 	attrJsonBytes, err := json.Marshal(&attr)
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to marshal attributes part.")
+		return nil, "", newApiOtherError(err, "failed to marshal attributes part.")
 	}
 	_, err = mhAttr.Write(attrJsonBytes)
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to write attributes part.")
+		return nil, "", newApiOtherError(err, "failed to write attributes part.")
 	}
 	createFormFile, err := mw.CreateFormFile("file", "file")
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to create file part.")
+		return nil, "", newApiOtherError(err, "failed to create file part.")
 	}
 	all, err := ioutil.ReadAll(reader)
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to read file.")
+		return nil, "", newApiOtherError(err, "failed to read file.")
 	}
 	_, err = createFormFile.Write(all)
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to write file part.")
+		return nil, "", newApiOtherError(err, "failed to write file part.")
 	}
-	contentType := mw.FormDataContentType()
+	contentType = mw.FormDataContentType()
 	err = mw.Close()
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to close multipart/form part.")
-	}
-
-	headers.Add("Content-Type", contentType)
-	req := NewRequest(f.apiInfo.api, url, POST, headers, body)
-
-	resp, err := req.Send()
-	if err != nil {
-		return nil, err
+		return nil, "", newApiOtherError(err, "failed to close multipart/form part.")
 	}
+	return body, contentType, nil
+}
 
+func (f *File) parseUploadResponse(resp *Response) (*File, error) {
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
 	files := struct {
@@ -326,7 +311,7 @@ func (f *File) UploadFile(filename string, reader io.Reader, parentFolderId stri
 		Limit      int     `json:"limit"`
 	}{}
 
-	err = UnmarshalJSONWrapper(resp.Body, &files)
+	err := UnmarshalJSONWrapper(resp.Body, &files)
 	if err != nil {
 		return nil, err
 	}
@@ -336,20 +321,17 @@ func (f *File) UploadFile(filename string, reader io.Reader, parentFolderId stri
 	return r, nil
 }
 
-// Upload File Version
-//
-// Uploading a new file version is performed in the same way as uploading a file.
-// This method is used to upload a new version of an existing file in a user’s account.
-// https://developer.box.com/reference#upload-a-new-version-of-a-file-1
-// TODO AS-USER support.
-// TODO Refactoring (memory inefficiency, and more)
-func (f *File) UploadFileVersion(fileId string, reader io.Reader, filename *string, contentModifiedAt *time.Time, ifMatch *string, contentMD5 *string) (*File, error) {
+// UploadFileReq builds the multipart request of UploadFile.
+// The content is read from reader into the request body when this is called.
+func (f *File) UploadFileReq(filename string, reader io.Reader, parentFolderId string, contentCreatedAt *time.Time, contentModifiedAt *time.Time, contentMD5 *string) (*Request, error) {
 	var url string
 
-	url = fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseUploadURL, "files/", fileId, "/content")
+	url = fmt.Sprintf("%s%s", f.apiInfo.api.BaseUploadURL, "files/content")
 	attr := map[string]interface{}{}
-	if filename != nil {
-		attr["name"] = *filename
+	attr["name"] = filename
+	attr["parent"] = map[string]string{"id": parentFolderId}
+	if contentCreatedAt != nil {
+		attr["content_created_at"] = contentCreatedAt.Format(time.RFC3339)
 	}
 	if contentModifiedAt != nil {
 		attr["content_modified_at"] = contentModifiedAt.Format(time.RFC3339)
@@ -359,69 +341,82 @@ func (f *File) UploadFileVersion(fileId string, reader io.Reader, filename *stri
 	if contentMD5 != nil {
 		headers.Set("Content-MD5", *contentMD5)
 	}
-	if ifMatch != nil {
-		headers.Set("If-Match", *ifMatch)
-	}
 
-	body := &bytes.Buffer{}
-	mw := multipart.NewWriter(body)
-	mhAttr, err := mw.CreateFormField("attributes")
+	body, contentType, err := buildUploadBody(attr, reader)
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to create attributes part.")
+		return nil, err
 	}
-	attrJsonBytes, err := json.Marshal(&attr)
+
+	headers.Add("Content-Type", contentType)
+	return f.apiInfo.newRequest(url, POST, headers, body), nil
+}
+
+// Upload File
+// Use the Upload API to allow users to add a new file. The user can then upload a file by specifying the destination folder for the file.
+// If the user provides a file name that already exists in the destination folder, the user will receive an error.
+//
+// TODO Refactoring
+func (f *File) UploadFile(filename string, reader io.Reader, parentFolderId string, contentCreatedAt *time.Time, contentModifiedAt *time.Time, contentMD5 *string) (*File, error) {
+	req, err := f.UploadFileReq(filename, reader, parentFolderId, contentCreatedAt, contentModifiedAt, contentMD5)
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to marshal attributes part.")
+		return nil, err
 	}
-	_, err = mhAttr.Write(attrJsonBytes)
+
+	resp, err := req.Send()
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to write attributes part.")
+		return nil, err
 	}
-	createFormFile, err := mw.CreateFormFile("file", "file")
-	if err != nil {
-		return nil, newApiOtherError(err, "failed to create file part.")
+	return f.parseUploadResponse(resp)
+}
+
+// UploadFileVersionReq builds the multipart request of UploadFileVersion.
+// The content is read from reader into the request body when this is called.
+func (f *File) UploadFileVersionReq(fileId string, reader io.Reader, filename *string, contentModifiedAt *time.Time, ifMatch *string, contentMD5 *string) (*Request, error) {
+	var url string
+
+	url = fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseUploadURL, "files/", fileId, "/content")
+	attr := map[string]interface{}{}
+	if filename != nil {
+		attr["name"] = *filename
 	}
-	all, err := ioutil.ReadAll(reader)
-	if err != nil {
-		return nil, newApiOtherError(err, "failed to read file.")
+	if contentModifiedAt != nil {
+		attr["content_modified_at"] = contentModifiedAt.Format(time.RFC3339)
 	}
-	_, err = createFormFile.Write(all)
-	if err != nil {
-		return nil, newApiOtherError(err, "failed to write file part.")
+
+	headers := http.Header{}
+	if contentMD5 != nil {
+		headers.Set("Content-MD5", *contentMD5)
 	}
-	contentType := mw.FormDataContentType()
-	err = mw.Close()
+	if ifMatch != nil {
+		headers.Set("If-Match", *ifMatch)
+	}
+
+	body, contentType, err := buildUploadBody(attr, reader)
 	if err != nil {
-		return nil, newApiOtherError(err, "failed to close multipart/form part.")
+		return nil, err
 	}
 
 	headers.Add("Content-Type", contentType)
-	req := NewRequest(f.apiInfo.api, url, POST, headers, body)
+	return f.apiInfo.newRequest(url, POST, headers, body), nil
+}
 
-	resp, err := req.Send()
+// Upload File Version
+//
+// Uploading a new file version is performed in the same way as uploading a file.
+// This method is used to upload a new version of an existing file in a user’s account.
+// https://developer.box.com/reference#upload-a-new-version-of-a-file-1
+// TODO Refactoring (memory inefficiency, and more)
+func (f *File) UploadFileVersion(fileId string, reader io.Reader, filename *string, contentModifiedAt *time.Time, ifMatch *string, contentMD5 *string) (*File, error) {
+	req, err := f.UploadFileVersionReq(fileId, reader, filename, contentModifiedAt, ifMatch, contentMD5)
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
-	}
-
-	files := struct {
-		TotalCount int     `json:"total_count"`
-		Entries    []*File `json:"entries"`
-		Offset     int     `json:"offset"`
-		Limit      int     `json:"limit"`
-	}{}
-
-	err = UnmarshalJSONWrapper(resp.Body, &files)
+	resp, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
-	r := files.Entries[0]
-	r.apiInfo = f.apiInfo
-
-	return r, nil
+	return f.parseUploadResponse(resp)
 }
 
 // Update File Info
@@ -473,9 +468,16 @@ func (f *File) UpdateReq(fileId string, ifMatch string, fields []string) *Reques
 		headers.Set("If-Match", ifMatch)
 	}
 
-	req := NewRequest(f.apiInfo.api, url+query, PUT, headers, bytes.NewReader(bodyBytes))
+	req := f.apiInfo.newRequest(url+query, PUT, headers, bytes.NewReader(bodyBytes))
 	return req
 }
+
+// Update File Info
+//
+// UpdateBatch adds UpdateReq to the batch request, and returns the handle of the file.
+func (f *File) UpdateBatch(batch *BatchRequest, fileId string, ifMatch string, fields []string) *PendingFile {
+	return batch.addFile(f.UpdateReq(fileId, ifMatch, fields))
+}
 func (f *File) Update(fileId string, ifMatch string, fields []string) (*File, error) {
 
 	req := f.UpdateReq(fileId, ifMatch, fields)
@@ -485,7 +487,7 @@ func (f *File) Update(fileId string, ifMatch string, fields []string) (*File, er
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 	file := &File{apiInfo: f.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, &file)
@@ -509,14 +511,14 @@ func (f *File) PreflightCheck(name string, parentFolderId string, size *int) (ok
 	}
 	bodyBytes, _ := json.Marshal(data)
 
-	req := NewRequest(f.apiInfo.api, url, OPTION, nil, bytes.NewReader(bodyBytes))
+	req := f.apiInfo.newRequest(url, OPTION, nil, bytes.NewReader(bodyBytes))
 	resp, err := req.Send()
 	if err != nil {
 		return false, err
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return false, newApiStatusError(resp.Body)
+		return false, newApiStatusError(resp)
 	}
 	return true, nil
 }
@@ -524,6 +526,7 @@ func (f *File) PreflightCheck(name string, parentFolderId string, size *int) (ok
 // Delete File
 //
 // Discards a file to the trash. The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
+// The file can be restored by Restore.
 // https://developer.box.com/reference#delete-a-file
 func (f *File) DeleteReq(fileId string, ifMatch string) *Request {
 
@@ -535,13 +538,21 @@ func (f *File) DeleteReq(fileId string, ifMatch string) *Request {
 		headers.Set("If-Match", ifMatch)
 	}
 
-	req := NewRequest(f.apiInfo.api, url, DELETE, headers, nil)
+	req := f.apiInfo.newRequest(url, DELETE, headers, nil)
 	return req
 }
 
+// Delete File
+//
+// DeleteBatch adds DeleteReq to the batch request, and returns the handle of its error.
+func (f *File) DeleteBatch(batch *BatchRequest, fileId string, ifMatch string) *PendingNoContent {
+	return batch.addNoContent(f.DeleteReq(fileId, ifMatch))
+}
+
 // Delete File
 //
 // Discards a file to the trash. The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
+// The file can be restored by Restore.
 // https://developer.box.com/reference#delete-a-file
 func (f *File) Delete(fileId string, ifMatch string) error {
 
@@ -552,7 +563,7 @@ func (f *File) Delete(fileId string, ifMatch string) error {
 	}
 
 	if resp.ResponseCode != http.StatusNoContent {
-		return newApiStatusError(resp.Body)
+		return newApiStatusError(resp)
 	}
 
 	return nil
@@ -561,8 +572,9 @@ func (f *File) Delete(fileId string, ifMatch string) error {
 // Copy File
 //
 // Used to create a copy of a file in another folder. The original version of the file will not be altered.
+// The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#copy-a-file
-func (f *File) CopyReq(fileId string, parentFolderId string, name string, version string, fields []string) *Request {
+func (f *File) CopyReq(fileId string, parentFolderId string, name string, version string, ifMatch string, fields []string) *Request {
 	var url string
 	var query string
 	url = fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "files/", fileId, "/copy")
@@ -581,25 +593,33 @@ func (f *File) CopyReq(fileId string, parentFolderId string, name string, versio
 	}
 	bodyBytes, _ := json.Marshal(data)
 
-	return NewRequest(f.apiInfo.api, url+query, POST, nil, bytes.NewReader(bodyBytes))
+	return f.apiInfo.newRequest(url+query, POST, nil, bytes.NewReader(bodyBytes)).IfMatch(ifMatch)
+}
+
+// Copy File
+//
+// CopyBatch adds CopyReq to the batch request, and returns the handle of the file.
+func (f *File) CopyBatch(batch *BatchRequest, fileId string, parentFolderId string, name string, version string, ifMatch string, fields []string) *PendingFile {
+	return batch.addFile(f.CopyReq(fileId, parentFolderId, name, version, ifMatch, fields))
 }
 
 // Copy File
 //
 // Used to create a copy of a file in another folder. The original version of the file will not be altered.
+// The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#copy-a-file
-func (f *File) Copy(fileId string, parentFolderId string, name string, version string, fields []string) (file *File, err error) {
-	req := f.CopyReq(fileId, parentFolderId, name, version, fields)
+func (f *File) Copy(fileId string, parentFolderId string, name string, version string, ifMatch string, fields []string) (file *File, err error) {
+	req := f.CopyReq(fileId, parentFolderId, name, version, ifMatch, fields)
 	resp, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	file = &File{apiInfo: &apiInfo{api: f.apiInfo.api}}
+	file = &File{apiInfo: f.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, file)
 	if err != nil {
 		return nil, err
@@ -626,7 +646,14 @@ func (f *File) CollaborationsReq(fileId string, marker string, limit int, fields
 
 	var url string
 	url = fmt.Sprintf("%s%s%s%s?%s", f.apiInfo.api.BaseURL, "files/", fileId, "/collaborations", query.String())
-	return NewRequest(f.apiInfo.api, url, GET, nil, nil)
+	return f.apiInfo.newRequest(url, GET, nil, nil)
+}
+
+// Get File Collaborations
+//
+// CollaborationsBatch adds CollaborationsReq to the batch request, and returns the handle of the collaborations.
+func (f *File) CollaborationsBatch(batch *BatchRequest, fileId string, marker string, limit int, fields []string) *PendingCollaborations {
+	return batch.addCollaborations(f.CollaborationsReq(fileId, marker, limit, fields))
 }
 
 // Get File Collaborations
@@ -642,7 +669,7 @@ func (f *File) Collaborations(fileId string, marker string, limit int, fields []
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, "", newApiStatusError(resp.Body)
+		return nil, "", newApiStatusError(resp)
 	}
 	items := struct {
 		NextMarker string           `json:"next_marker,omitempty"`
@@ -659,3 +686,22 @@ func (f *File) Collaborations(fileId string, marker string, limit int, fields []
 
 	return items.Entries, items.NextMarker, nil
 }
+
+// Get All File Collaborations
+//
+// CollaborationsIter returns the iterator of all the collaborations on the file. If pageSize is 0, 100 is used.
+func (f *File) CollaborationsIter(fileId string, fields []string, pageSize int) *CollaborationIterator {
+	return &CollaborationIterator{newMarkerIterator(pageSize, func(marker string, limit int) ([]interface{}, string, error) {
+		page, nextMarker, err := f.Collaborations(fileId, marker, limit, fields)
+		return pageItems(page), nextMarker, err
+	})}
+}
+
+// Get All File Collaborations
+//
+// CollaborationsAll returns all the collaborations on the file.
+func (f *File) CollaborationsAll(fileId string, fields []string) ([]*Collaboration, error) {
+	var r []*Collaboration
+	err := f.CollaborationsIter(fileId, fields, 1000).allInto(&r)
+	return r, err
+}
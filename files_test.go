@@ -395,7 +395,7 @@ func TestFile_DownloadFile(t *testing.T) {
 
 	type args struct {
 		fileId       string
-		fileVersion  string
+		fileVersion  *FileVersion
 		boxApiHeader string
 	}
 	tests := []struct {
@@ -405,13 +405,13 @@ func TestFile_DownloadFile(t *testing.T) {
 		wantErr bool
 		errType interface{}
 	}{
-		{"normal/302", args{"302", "", ""}, strings.NewReader("DOWNLOAD SUCCESS"), false, nil},
-		{"normal/202", args{"202", "", ""}, strings.NewReader("DOWNLOAD SUCCESS"), false, nil},
-		{"normal version specified", args{"10001", "2", ""}, strings.NewReader("DOWNLOAD SUCCESS"), false, nil},
-		{"normal BoxApi specified", args{"10002", "", "shared_link=SHARED_LINK_URL&shared_link_password=PASSWORD"}, strings.NewReader("DOWNLOAD SUCCESS"), false, nil},
-		{"http error/404", args{"404", "", ""}, nil, true, &ApiStatusError{Status: 404}},
-		{"returned invalid json/999", args{"999", "", ""}, nil, true, &ApiOtherError{}},
-		{"senderror", args{"999", "", ""}, nil, true, &ApiOtherError{}},
+		{"normal/302", args{"302", nil, ""}, strings.NewReader("DOWNLOAD SUCCESS"), false, nil},
+		{"normal/202", args{"202", nil, ""}, strings.NewReader("DOWNLOAD SUCCESS"), false, nil},
+		{"normal version specified", args{"10001", &FileVersion{ID: "2"}, ""}, strings.NewReader("DOWNLOAD SUCCESS"), false, nil},
+		{"normal BoxApi specified", args{"10002", nil, "shared_link=SHARED_LINK_URL&shared_link_password=PASSWORD"}, strings.NewReader("DOWNLOAD SUCCESS"), false, nil},
+		{"http error/404", args{"404", nil, ""}, nil, true, &ApiStatusError{Status: 404}},
+		{"returned invalid json/999", args{"999", nil, ""}, nil, true, &ApiStatusError{Status: 400}},
+		{"senderror", args{"999", nil, ""}, nil, true, &ApiOtherError{}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1781,7 +1781,7 @@ func TestFile_CopyReq(t *testing.T) {
 
 			f := NewFile(apiConn)
 
-			got := f.CopyReq(tt.args.fileId, tt.args.parentFolderId, tt.args.name, tt.args.version, tt.args.fields)
+			got := f.CopyReq(tt.args.fileId, tt.args.parentFolderId, tt.args.name, tt.args.version, "", tt.args.fields)
 			// If normal response
 			opts := diffCompOptions(*got, Request{})
 			opts = append(opts, cmpopts.IgnoreInterfaces(struct{ io.Reader }{}))
@@ -1911,7 +1911,7 @@ func TestFile_Copy(t *testing.T) {
 				apiConn.Expires = 6000
 			}
 			f := NewFile(apiConn)
-			got, err := f.Copy(tt.args.fileId, tt.args.parentFolderId, tt.args.name, tt.args.version, tt.args.fields)
+			got, err := f.Copy(tt.args.fileId, tt.args.parentFolderId, tt.args.name, tt.args.version, "", tt.args.fields)
 
 			// Error checks
 			if (err != nil) != tt.wantErr {
@@ -2189,3 +2189,20 @@ func TestFile_Collaborations(t *testing.T) {
 		})
 	}
 }
+
+func TestFile_UploadFileReq_SuppressNotifications(t *testing.T) {
+	apiConn := commonInit("https://example.com")
+	f := NewFile(apiConn)
+
+	req, err := f.UploadFileReq("a.txt", strings.NewReader("content"), "0", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UploadFileReq() error = %v", err)
+	}
+	req.SuppressNotifications()
+	if got := req.headers.Get(httpHeaderNotifications); got != "off" {
+		t.Errorf("Box-Notifications = %q, want off", got)
+	}
+	if !strings.HasPrefix(req.headers.Get(httpHeaderContentType), "multipart/form-data") {
+		t.Errorf("Content-Type = %q", req.headers.Get(httpHeaderContentType))
+	}
+}
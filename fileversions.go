@@ -1,12 +1,223 @@
 package goboxer
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
 type FileVersion struct {
-	apiInfo *apiInfo
-	Type    string `json:"type,omitempty"`
-	ID      string `json:"id,omitempty"`
-	Sha1    string `json:"sha1,omitempty"`
+	apiInfo             *apiInfo
+	Type                string         `json:"type,omitempty"`
+	ID                  string         `json:"id,omitempty"`
+	Sha1                string         `json:"sha1,omitempty"`
+	Name                *string        `json:"name,omitempty"`
+	Size                *int64         `json:"size,omitempty"`
+	CreatedAt           *time.Time     `json:"created_at,omitempty"`
+	ModifiedAt          *time.Time     `json:"modified_at,omitempty"`
+	ModifiedBy          *UserGroupMini `json:"modified_by,omitempty"`
+	TrashedAt           *time.Time     `json:"trashed_at,omitempty"`
+	TrashedBy           *UserGroupMini `json:"trashed_by,omitempty"`
+	RestoredAt          *time.Time     `json:"restored_at,omitempty"`
+	RestoredBy          *UserGroupMini `json:"restored_by,omitempty"`
+	PurgedAt            *time.Time     `json:"purged_at,omitempty"`
+	UploaderDisplayName *string        `json:"uploader_display_name,omitempty"`
 }
 
 func (fv *FileVersion) ResourceType() BoxResourceType {
 	return FileVersionResource
 }
+
+func NewFileVersion(api Connection) *FileVersion {
+	return &FileVersion{
+		apiInfo: api.connInfo(),
+	}
+}
+
+// IsTrashed reports whether the version is in the trash.
+func (fv *FileVersion) IsTrashed() bool {
+	return fv != nil && fv.TrashedAt != nil
+}
+
+var FileVersionAllFields = []string{
+	"type", "id", "sha1", "name", "size", "created_at", "modified_at", "modified_by",
+	"trashed_at", "trashed_by", "restored_at", "restored_by", "purged_at", "uploader_display_name",
+}
+
+// Get Versions
+//
+// Retrieve a list of the past versions for a file. The current version is not included.
+// https://developer.box.com/reference#view-versions-of-a-file
+func (fv *FileVersion) GetVersionsReq(fileId string, offset int32, limit int32, fields []string) *Request {
+	var urlBase string
+	var query string
+	urlBase = fmt.Sprintf("%s%s%s%s", fv.apiInfo.api.BaseURL, "files/", fileId, "/versions")
+	if limit > 1000 {
+		limit = 1000
+	}
+	query = fmt.Sprintf("?offset=%d&limit=%d", offset, limit)
+	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
+		query += fmt.Sprintf("&%s", fieldsParams)
+	}
+	return fv.apiInfo.newRequest(urlBase+query, GET, nil, nil)
+}
+
+// Get Versions
+//
+// Retrieve a list of the past versions for a file. The current version is not included.
+// https://developer.box.com/reference#view-versions-of-a-file
+func (fv *FileVersion) GetVersions(fileId string, offset int32, limit int32, fields []string) (outVersions []*FileVersion, outOffset int, outLimit int, outTotalCount int, err error) {
+
+	req := fv.GetVersionsReq(fileId, offset, limit, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, 0, 0, 0, newApiStatusError(resp)
+	}
+
+	versions := struct {
+		TotalCount int            `json:"total_count"`
+		Entries    []*FileVersion `json:"entries"`
+		Offset     int            `json:"offset"`
+		Limit      int            `json:"limit"`
+	}{}
+
+	err = UnmarshalJSONWrapper(resp.Body, &versions)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	for _, v := range versions.Entries {
+		v.apiInfo = fv.apiInfo
+	}
+	return versions.Entries, versions.Offset, versions.Limit, versions.TotalCount, nil
+}
+
+// Get File Version Info
+//
+// Retrieve a specific version of a file.
+// https://developer.box.com/reference#get-file-version
+func (fv *FileVersion) GetVersionInfoReq(fileId string, versionId string, fields []string) *Request {
+	var urlBase string
+	var query string
+	urlBase = fmt.Sprintf("%s%s%s%s%s", fv.apiInfo.api.BaseURL, "files/", fileId, "/versions/", versionId)
+	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
+		query = fmt.Sprintf("?%s", fieldsParams)
+	}
+	return fv.apiInfo.newRequest(urlBase+query, GET, nil, nil)
+}
+
+// Get File Version Info
+//
+// Retrieve a specific version of a file.
+// https://developer.box.com/reference#get-file-version
+func (fv *FileVersion) GetVersionInfo(fileId string, versionId string, fields []string) (*FileVersion, error) {
+	req := fv.GetVersionInfoReq(fileId, versionId, fields)
+	return fv.sendForVersion(req, http.StatusOK)
+}
+
+// Promote Version
+//
+// Copy a previous file version and make it the current version of the file.
+// This creates a copy of the old file version and puts it on the top of the versions stack.
+// https://developer.box.com/reference#promote-version
+func (fv *FileVersion) PromoteReq(fileId string, versionId string, fields []string) *Request {
+	var urlBase string
+	var query string
+	urlBase = fmt.Sprintf("%s%s%s%s", fv.apiInfo.api.BaseURL, "files/", fileId, "/versions/current")
+	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
+		query = fmt.Sprintf("?%s", fieldsParams)
+	}
+	data := map[string]string{
+		"type": "file_version",
+		"id":   versionId,
+	}
+	bodyBytes, _ := json.Marshal(data)
+	return fv.apiInfo.newRequest(urlBase+query, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Promote Version
+//
+// Copy a previous file version and make it the current version of the file.
+// This creates a copy of the old file version and puts it on the top of the versions stack.
+// The new current version is returned.
+// https://developer.box.com/reference#promote-version
+func (fv *FileVersion) Promote(fileId string, versionId string, fields []string) (*FileVersion, error) {
+	req := fv.PromoteReq(fileId, versionId, fields)
+	return fv.sendForVersion(req, http.StatusCreated)
+}
+
+// Delete Old Version
+//
+// Discards a file version to the trash.
+// The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
+// https://developer.box.com/reference#delete-version
+func (fv *FileVersion) DeleteReq(fileId string, versionId string, ifMatch string) *Request {
+	var urlBase string
+	urlBase = fmt.Sprintf("%s%s%s%s%s", fv.apiInfo.api.BaseURL, "files/", fileId, "/versions/", versionId)
+	return fv.apiInfo.newRequest(urlBase, DELETE, nil, nil).IfMatch(ifMatch)
+}
+
+// Delete Old Version
+//
+// Discards a file version to the trash.
+// The etag of the file can be included as an ‘If-Match’ header to prevent race conditions.
+// https://developer.box.com/reference#delete-version
+func (fv *FileVersion) Delete(fileId string, versionId string, ifMatch string) error {
+	req := fv.DeleteReq(fileId, versionId, ifMatch)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
+
+// Restore File Version
+//
+// Restores a file version from the trash.
+// https://developer.box.com/reference#restore-file-version
+func (fv *FileVersion) RestoreReq(fileId string, versionId string, fields []string) *Request {
+	var urlBase string
+	var query string
+	urlBase = fmt.Sprintf("%s%s%s%s%s", fv.apiInfo.api.BaseURL, "files/", fileId, "/versions/", versionId)
+	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
+		query = fmt.Sprintf("?%s", fieldsParams)
+	}
+	bodyBytes := []byte(`{"trashed_at":null}`)
+	return fv.apiInfo.newRequest(urlBase+query, PUT, nil, bytes.NewReader(bodyBytes))
+}
+
+// Restore File Version
+//
+// Restores a file version from the trash.
+// https://developer.box.com/reference#restore-file-version
+func (fv *FileVersion) Restore(fileId string, versionId string, fields []string) (*FileVersion, error) {
+	req := fv.RestoreReq(fileId, versionId, fields)
+	return fv.sendForVersion(req, http.StatusOK)
+}
+
+func (fv *FileVersion) sendForVersion(req *Request, expectedStatus int) (*FileVersion, error) {
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != expectedStatus {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &FileVersion{apiInfo: fv.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
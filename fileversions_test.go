@@ -1,6 +1,9 @@
 package goboxer
 
-import "testing"
+import (
+	"io/ioutil"
+	"testing"
+)
 
 func TestFileVersion_ResourceType(t *testing.T) {
 	tests := []struct {
@@ -20,3 +23,40 @@ func TestFileVersion_ResourceType(t *testing.T) {
 		})
 	}
 }
+
+func TestFileVersion_Reqs(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+	fv := NewFileVersion(apiConn)
+
+	tests := []struct {
+		name       string
+		req        *Request
+		wantMethod Method
+		wantUrl    string
+		wantBody   string
+	}{
+		{"GetVersions", fv.GetVersionsReq("10001", 10, 2000, []string{"type", "id"}), GET, url + "/2.0/files/10001/versions?offset=10&limit=1000&fields=type,id", ""},
+		{"GetVersionInfo", fv.GetVersionInfoReq("10001", "20001", nil), GET, url + "/2.0/files/10001/versions/20001", ""},
+		{"Promote", fv.PromoteReq("10001", "20001", nil), POST, url + "/2.0/files/10001/versions/current", `{"id":"20001","type":"file_version"}`},
+		{"Delete", fv.DeleteReq("10001", "20001", "3"), DELETE, url + "/2.0/files/10001/versions/20001", ""},
+		{"Restore", fv.RestoreReq("10001", "20001", []string{"trashed_at"}), PUT, url + "/2.0/files/10001/versions/20001?fields=trashed_at", `{"trashed_at":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Method != tt.wantMethod || tt.req.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want %v %s", tt.req.Method, tt.req.Url, tt.wantMethod, tt.wantUrl)
+			}
+			var body []byte
+			if tt.req.body != nil {
+				body, _ = ioutil.ReadAll(tt.req.body)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+	if ifMatch := fv.DeleteReq("10001", "20001", "3").headers.Get("If-Match"); ifMatch != "3" {
+		t.Errorf("If-Match = %q, want 3", ifMatch)
+	}
+}
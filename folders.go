@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
 	"time"
@@ -226,15 +227,31 @@ func (wi *WatermarkInfo) String() string {
 	return fmt.Sprintf("{ %s:%t }", "IsWatermarked", wi.IsWatermarked)
 }
 
-type Metadata struct {
-	// TODO
+// MetadataInstances is the metadata instances requested by the fields parameter (see MetadataFields),
+// keyed by scope (e.g. "global", "enterprise_12345") and template key.
+type MetadataInstances map[string]map[string]MetadataInstance
+
+// Instance returns the metadata instance of the template, or nil.
+// The enterprise scope matches the scope with the enterprise id. (e.g. "enterprise_12345")
+func (m *MetadataInstances) Instance(scope string, templateKey string) MetadataInstance {
+	if m == nil {
+		return nil
+	}
+	for s, instances := range *m {
+		if s == scope || (scope == MetadataScopeEnterprise && strings.HasPrefix(s, MetadataScopeEnterprise+"_")) {
+			if instance, ok := instances[templateKey]; ok {
+				return instance
+			}
+		}
+	}
+	return nil
 }
 
-func (m *Metadata) String() string {
+func (m *MetadataInstances) String() string {
 	if m == nil {
 		return "<nil>"
 	}
-	return "{ Not Implemented }"
+	return fmt.Sprintf("%v", map[string]map[string]MetadataInstance(*m))
 }
 
 type Folder struct {
@@ -268,7 +285,7 @@ type Folder struct {
 	AllowedSharedLinkAccessLevels         []string           `json:"allowed_shared_link_access_levels,omitempty"`
 	AllowedInviteeRole                    []string           `json:"allowed_invitee_roles,omitempty"`
 	WatermarkInfo                         *WatermarkInfo     `json:"watermark_info,omitempty"`
-	Metadata                              *Metadata          `json:"metadata,omitempty"`
+	Metadata                              *MetadataInstances `json:"metadata,omitempty"`
 
 	changedFlag uint64
 }
@@ -338,9 +355,9 @@ func (f *Folder) ResourceType() BoxResourceType {
 	return FolderResource
 }
 
-func NewFolder(api *APIConn) *Folder {
+func NewFolder(api Connection) *Folder {
 	return &Folder{
-		apiInfo: &apiInfo{api: api},
+		apiInfo: api.connInfo(),
 	}
 }
 
@@ -358,7 +375,14 @@ func (f *Folder) GetInfoReq(folderId string, fields []string) *Request {
 		query = fmt.Sprintf("?%s", fieldsParam)
 	}
 
-	return NewRequest(f.apiInfo.api, url+query, GET, nil, nil)
+	return f.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get Folder Info.
+//
+// GetInfoBatch adds GetInfoReq to the batch request, and returns the handle of the folder.
+func (f *Folder) GetInfoBatch(batch *BatchRequest, folderId string, fields []string) *PendingFolder {
+	return batch.addFolder(f.GetInfoReq(folderId, fields))
 }
 
 // Get Folder Info.
@@ -367,13 +391,13 @@ func (f *Folder) GetInfoReq(folderId string, fields []string) *Request {
 // https://developer.box.com/reference#get-folder-info
 func (f *Folder) GetInfo(folderId string, fields []string) (*Folder, error) {
 	req := f.GetInfoReq(folderId, fields)
-	resp, err := req.Send()
+	resp, err := req.sendCached()
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 	folder := &Folder{}
 	err = UnmarshalJSONBoxResourceWrapper(resp.Body, folder)
@@ -405,7 +429,7 @@ func (f *Folder) FolderItemReq(folderId string, offset int, limit int, sort stri
 		query = query + fmt.Sprintf("&%s", fieldsParam)
 	}
 
-	return NewRequest(f.apiInfo.api, url+query, GET, nil, nil)
+	return f.apiInfo.newRequest(url+query, GET, nil, nil)
 }
 
 // Get Folder Items.
@@ -424,7 +448,7 @@ func (f *Folder) FolderItem(folderId string, offset int, limit int, sort string,
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, 0, 0, 0, newApiStatusError(resp.Body)
+		return nil, 0, 0, 0, newApiStatusError(resp)
 	}
 	items := struct {
 		TotalCount int               `json:"total_count"`
@@ -450,6 +474,115 @@ func (f *Folder) FolderItem(folderId string, offset int, limit int, sort string,
 	return entries, items.Offset, items.Limit, items.TotalCount, nil
 }
 
+// Get All Folder Items
+//
+// FolderItemIter returns the iterator of all the items in the folder. If pageSize is 0, 100 is used.
+func (f *Folder) FolderItemIter(folderId string, sort string, sortDir string, fields []string, pageSize int) *ItemIterator {
+	return &ItemIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := f.FolderItem(folderId, offset, limit, sort, sortDir, fields)
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get All Folder Items
+//
+// FolderItemAll returns all the items in the folder.
+func (f *Folder) FolderItemAll(folderId string, sort string, sortDir string, fields []string) ([]BoxResource, error) {
+	var r []BoxResource
+	err := f.FolderItemIter(folderId, sort, sortDir, fields, 1000).allInto(&r)
+	return r, err
+}
+
+// Get Folder Items (marker-based paging)
+//
+// Gets the files, folders, or web links contained within a folder, paginated by marker.
+// Marker-based paging is recommended for large folders.
+// https://developer.box.com/reference#get-a-folders-items
+//
+// The items are returned in the default order, Box does not support sorting with marker-based paging.
+//
+//  marker: "" for the first page, the returned nextMarker for the following pages
+func (f *Folder) FolderItemMarkerReq(folderId string, marker string, limit int, fields []string) *Request {
+	var urlBase string
+	var query string
+
+	urlBase = fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "folders/", folderId, "/items")
+	if limit > 1000 {
+		limit = 1000
+	}
+	query = fmt.Sprintf("?usemarker=true&limit=%d", limit)
+	if marker != "" {
+		query += fmt.Sprintf("&marker=%s", url.QueryEscape(marker))
+	}
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = query + fmt.Sprintf("&%s", fieldsParam)
+	}
+
+	return f.apiInfo.newRequest(urlBase+query, GET, nil, nil)
+}
+
+// Get Folder Items (marker-based paging)
+//
+// Gets the files, folders, or web links contained within a folder, paginated by marker.
+// nextMarker is empty if there are no more items.
+// https://developer.box.com/reference#get-a-folders-items
+//
+// The items are returned in the default order, Box does not support sorting with marker-based paging.
+//
+//  marker: "" for the first page, the returned nextMarker for the following pages
+func (f *Folder) FolderItemMarker(folderId string, marker string, limit int, fields []string) (outResources []BoxResource, nextMarker string, err error) {
+
+	req := f.FolderItemMarkerReq(folderId, marker, limit, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, "", newApiStatusError(resp)
+	}
+	items := struct {
+		NextMarker string            `json:"next_marker,omitempty"`
+		Entries    []json.RawMessage `json:"entries"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &items)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var entries []BoxResource
+
+	for _, entity := range items.Entries {
+		boxResource, err := ParseResource(entity)
+		if err != nil {
+			return nil, "", err
+		}
+		setApiInfo(boxResource, f.apiInfo)
+		entries = append(entries, boxResource)
+	}
+	return entries, items.NextMarker, nil
+}
+
+// Get All Folder Items (marker-based paging)
+//
+// FolderItemMarkerIter returns the iterator of all the items in the folder, paginated by marker.
+// If pageSize is 0, 100 is used.
+func (f *Folder) FolderItemMarkerIter(folderId string, fields []string, pageSize int) *ItemIterator {
+	return &ItemIterator{newMarkerIterator(pageSize, func(marker string, limit int) ([]interface{}, string, error) {
+		page, nextMarker, err := f.FolderItemMarker(folderId, marker, limit, fields)
+		return pageItems(page), nextMarker, err
+	})}
+}
+
+// Get All Folder Items (marker-based paging)
+//
+// FolderItemMarkerAll returns all the items in the folder, paginated by marker.
+func (f *Folder) FolderItemMarkerAll(folderId string, fields []string) ([]BoxResource, error) {
+	var r []BoxResource
+	err := f.FolderItemMarkerIter(folderId, fields, 1000).allInto(&r)
+	return r, err
+}
+
 // Create Folder
 //
 // Create a new folder.
@@ -472,7 +605,14 @@ func (f *Folder) CreateReq(parentFolderId string, name string, fields []string)
 	}
 	bodyBytes, _ := json.Marshal(bodyMap)
 
-	return NewRequest(f.apiInfo.api, url+query, POST, nil, bytes.NewReader(bodyBytes))
+	return f.apiInfo.newRequest(url+query, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Create Folder
+//
+// CreateBatch adds CreateReq to the batch request, and returns the handle of the folder.
+func (f *Folder) CreateBatch(batch *BatchRequest, parentFolderId string, name string, fields []string) *PendingFolder {
+	return batch.addFolder(f.CreateReq(parentFolderId, name, fields))
 }
 
 // Create Folder
@@ -488,7 +628,7 @@ func (f *Folder) Create(parentFolderId string, name string, fields []string) (*F
 	}
 
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 	folder := Folder{}
 	err = UnmarshalJSONBoxResourceWrapper(resp.Body, &folder)
@@ -674,8 +814,9 @@ func (f *Folder) SetFolderUploadEmailAccess(fuea FolderUploadEmailAccess) *Folde
 // Update Folder
 //
 // Update a folder.
+// The etag of the folder can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#update-information-about-a-folder
-func (f *Folder) UpdateReq(folderId string, fields []string) *Request {
+func (f *Folder) UpdateReq(folderId string, ifMatch string, fields []string) *Request {
 	var url string
 	var query string
 
@@ -732,22 +873,30 @@ func (f *Folder) UpdateReq(folderId string, fields []string) *Request {
 
 	bodyBytes, _ := json.Marshal(data)
 
-	return NewRequest(f.apiInfo.api, url+query, PUT, nil, bytes.NewReader(bodyBytes))
+	return f.apiInfo.newRequest(url+query, PUT, nil, bytes.NewReader(bodyBytes)).IfMatch(ifMatch)
+}
+
+// Update Folder
+//
+// UpdateBatch adds UpdateReq to the batch request, and returns the handle of the folder.
+func (f *Folder) UpdateBatch(batch *BatchRequest, folderId string, ifMatch string, fields []string) *PendingFolder {
+	return batch.addFolder(f.UpdateReq(folderId, ifMatch, fields))
 }
 
 // Update Folder
 //
 // Update a folder.
+// The etag of the folder can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#update-information-about-a-folder
-func (f *Folder) Update(folderId string, fields []string) (*Folder, error) {
-	req := f.UpdateReq(folderId, fields)
+func (f *Folder) Update(folderId string, ifMatch string, fields []string) (*Folder, error) {
+	req := f.UpdateReq(folderId, ifMatch, fields)
 	resp, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 	folder := &Folder{}
 	err = UnmarshalJSONBoxResourceWrapper(resp.Body, folder)
@@ -765,6 +914,7 @@ func (f *Folder) Update(folderId string, fields []string) (*Folder, error) {
 //
 // Move a folder to the trash.
 // The recursive parameter must be included in order to delete folders that aren't empty.
+// The folder can be restored with the items in it by Restore.
 // https://developer.box.com/reference#delete-a-folder
 func (f *Folder) DeleteReq(folderId string, recursive bool, ifMatch string) *Request {
 	var url string
@@ -782,13 +932,21 @@ func (f *Folder) DeleteReq(folderId string, recursive bool, ifMatch string) *Req
 	}
 	url = fmt.Sprintf("%s%s%s?%s", f.apiInfo.api.BaseURL, "folders/", folderId, param)
 
-	return NewRequest(f.apiInfo.api, url, DELETE, h, nil)
+	return f.apiInfo.newRequest(url, DELETE, h, nil)
+}
+
+// Delete Folder
+//
+// DeleteBatch adds DeleteReq to the batch request, and returns the handle of its error.
+func (f *Folder) DeleteBatch(batch *BatchRequest, folderId string, recursive bool, ifMatch string) *PendingNoContent {
+	return batch.addNoContent(f.DeleteReq(folderId, recursive, ifMatch))
 }
 
 // Delete Folder
 //
 // Move a folder to the trash.
 // The recursive parameter must be included in order to delete folders that aren't empty.
+// The folder can be restored with the items in it by Restore.
 // https://developer.box.com/reference#delete-a-folder
 func (f *Folder) Delete(folderId string, recursive bool, ifMatch string) error {
 	req := f.DeleteReq(folderId, recursive, ifMatch)
@@ -798,7 +956,7 @@ func (f *Folder) Delete(folderId string, recursive bool, ifMatch string) error {
 	}
 
 	if resp.ResponseCode != http.StatusNoContent {
-		return newApiStatusError(resp.Body)
+		return newApiStatusError(resp)
 	}
 	return nil
 }
@@ -807,8 +965,9 @@ func (f *Folder) Delete(folderId string, recursive bool, ifMatch string) error {
 //
 // Used to create a copy of a folder in another folder.
 // The original version of the folder will not be altered.
+// The etag of the folder can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#copy-a-folder
-func (f *Folder) CopyReq(folderId string, parentFolderId string, newName string, fields []string) *Request {
+func (f *Folder) CopyReq(folderId string, parentFolderId string, newName string, ifMatch string, fields []string) *Request {
 	var url string
 	var query string
 
@@ -828,23 +987,31 @@ func (f *Folder) CopyReq(folderId string, parentFolderId string, newName string,
 	}
 	bodyBytes, _ := json.Marshal(bodyMap)
 
-	return NewRequest(f.apiInfo.api, url+query, POST, nil, bytes.NewReader(bodyBytes))
+	return f.apiInfo.newRequest(url+query, POST, nil, bytes.NewReader(bodyBytes)).IfMatch(ifMatch)
+}
+
+// Copy Folder
+//
+// CopyBatch adds CopyReq to the batch request, and returns the handle of the folder.
+func (f *Folder) CopyBatch(batch *BatchRequest, folderId string, parentFolderId string, newName string, ifMatch string, fields []string) *PendingFolder {
+	return batch.addFolder(f.CopyReq(folderId, parentFolderId, newName, ifMatch, fields))
 }
 
 // Copy Folder
 //
 // Used to create a copy of a folder in another folder.
 // The original version of the folder will not be altered.
+// The etag of the folder can be included as an ‘If-Match’ header to prevent race conditions.
 // https://developer.box.com/reference#copy-a-folder
-func (f *Folder) Copy(folderId string, parentFolderId string, newName string, fields []string) (*Folder, error) {
-	req := f.CopyReq(folderId, parentFolderId, newName, fields)
+func (f *Folder) Copy(folderId string, parentFolderId string, newName string, ifMatch string, fields []string) (*Folder, error) {
+	req := f.CopyReq(folderId, parentFolderId, newName, ifMatch, fields)
 	resp, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 	folder := &Folder{}
 	err = UnmarshalJSONBoxResourceWrapper(resp.Body, folder)
@@ -870,7 +1037,14 @@ func (f *Folder) CollaborationsReq(folderId string, fields []string) *Request {
 	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
 		query = query + fmt.Sprintf("?%s", fieldsParam)
 	}
-	return NewRequest(f.apiInfo.api, url+query, GET, nil, nil)
+	return f.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get Folder Collaborations
+//
+// CollaborationsBatch adds CollaborationsReq to the batch request, and returns the handle of the collaborations.
+func (f *Folder) CollaborationsBatch(batch *BatchRequest, folderId string, fields []string) *PendingCollaborations {
+	return batch.addCollaborations(f.CollaborationsReq(folderId, fields))
 }
 
 // Get Folder Collaborations
@@ -886,7 +1060,7 @@ func (f *Folder) Collaborations(folderId string, fields []string) ([]*Collaborat
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 	collabs := struct {
 		TotalCount int              `json:"total_count"`
@@ -897,7 +1071,7 @@ func (f *Folder) Collaborations(folderId string, fields []string) ([]*Collaborat
 		return nil, err
 	}
 	for _, collab := range collabs.Entries {
-		collab.apiInfo = &apiInfo{api: f.apiInfo.api}
+		collab.apiInfo = f.apiInfo
 	}
 	return collabs.Entries, nil
 }
@@ -1415,7 +1415,7 @@ func TestFolder_UpdateReq(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.folder.UpdateReq(tt.args.folderId, tt.args.fields)
+			got := tt.folder.UpdateReq(tt.args.folderId, "", tt.args.fields)
 
 			opts := diffCompOptions(Folder{}, APIConn{})
 			opt := cmpopts.IgnoreUnexported(Request{})
@@ -1566,7 +1566,7 @@ func TestFolder_Update(t *testing.T) {
 			f.SetCanNonOwnersInvite(true)
 			f.SetIsCollaborationRestrictedToEnterprise(true)
 			f.SetFolderUploadEmailAccess(FolderUploadEmailAccessCollaborators)
-			got, err := f.Update(tt.args.folderId, tt.args.fields)
+			got, err := f.Update(tt.args.folderId, "", tt.args.fields)
 
 			// Error checks
 			if (err != nil) != tt.wantErr {
@@ -1842,7 +1842,7 @@ func TestFolder_CopyReq(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			f := NewFolder(apiConn)
 
-			got := f.CopyReq(tt.args.folderId, tt.args.parentFolderId, tt.args.name, tt.args.fields)
+			got := f.CopyReq(tt.args.folderId, tt.args.parentFolderId, tt.args.name, "", tt.args.fields)
 			// If normal response
 			opts := diffCompOptions(*got, Request{})
 			opts = append(opts, cmpopts.IgnoreInterfaces(struct{ io.Reader }{}))
@@ -1968,7 +1968,7 @@ func TestFolder_Copy(t *testing.T) {
 				apiConn.Expires = 0
 			}
 			f := NewFolder(apiConn)
-			got, err := f.Copy(tt.args.folderId, tt.args.parentFolderId, tt.args.name, tt.args.fields)
+			got, err := f.Copy(tt.args.folderId, tt.args.parentFolderId, tt.args.name, "", tt.args.fields)
 
 			// Error checks
 			if (err != nil) != tt.wantErr {
@@ -2265,3 +2265,42 @@ func TestFolder_String(t *testing.T) {
 		})
 	}
 }
+
+func TestFolder_FolderItemMarkerReq(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	type args struct {
+		folderId string
+		marker   string
+		limit    int
+		fields   []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"first page", args{"123", "", 1001, nil},
+			url + "/2.0/folders/123/items?usemarker=true&limit=1000"},
+		{"next page/fields", args{"123", "a+b/c", 100, []string{"type", "id"}},
+			url + "/2.0/folders/123/items?usemarker=true&limit=100&marker=a%2Bb%2Fc&fields=type,id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewFolder(apiConn).FolderItemMarkerReq(tt.args.folderId, tt.args.marker, tt.args.limit, tt.args.fields)
+			want := &Request{
+				apiConn:            apiConn,
+				Url:                tt.want,
+				Method:             GET,
+				headers:            http.Header{},
+				body:               nil,
+				shouldAuthenticate: true,
+				numRedirects:       defaultNumRedirects,
+			}
+			if diff := cmp.Diff(got, want, diffCompOptions(*got)...); diff != "" {
+				t.Errorf("Folder.FolderItemMarkerReq() diff:  (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
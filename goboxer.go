@@ -1,15 +1,48 @@
 package goboxer //import "github.com/jparound30/goboxer"
 
+import (
+	"io"
+	"net/http"
+)
+
 const (
 	VERSION = "0.0.1"
 )
 
 var (
 	Log Logger = nil
+
+	// StructuredLog receives key/value log records. It can be used together with Log or instead of it.
+	StructuredLog StructuredLogger = nil
+
+	// Metrics receives the metrics of requests and circuit breakers. (optional)
+	Metrics MetricsHook = nil
 )
 
+// Connection is the connection used by the resources (File, Folder, User, ...).
+//
+// It is implemented by *APIConn and by the user scoped connection returned from APIConn.AsUser.
+type Connection interface {
+	connInfo() *apiInfo
+}
+
 type apiInfo struct {
 	api *APIConn
+	// user id set as "As-User" header of every request, empty if not scoped
+	asUser string
+}
+
+func (i *apiInfo) connInfo() *apiInfo {
+	return i
+}
+
+// newRequest is NewRequest with the scope of this apiInfo applied.
+func (i *apiInfo) newRequest(url string, method Method, headers http.Header, body io.Reader) *Request {
+	req := NewRequest(i.api, url, method, headers, body)
+	if i.asUser != "" {
+		req.AsUser(i.asUser)
+	}
+	return req
 }
 
 type ItemType string
@@ -48,6 +81,7 @@ const (
 	TaskResource
 	EventResource
 	CollectionResource
+	TaskAssignmentResource
 )
 
 type BoxResource interface {
@@ -3,6 +3,8 @@ package goboxer
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
 
 	"golang.org/x/xerrors"
 )
@@ -41,6 +43,31 @@ func newApiOtherError(err error, msg string) error {
 	return &ApiOtherError{err: err, msg: msg, frame: xerrors.Caller(1)}
 }
 
+// Sentinel errors for well-known classes of Box API errors.
+//
+// Use errors.Is (or xerrors.Is) against an error returned from this library.
+//
+//	if errors.Is(err, goboxer.ErrNotFound) { ... }
+var (
+	ErrNotFound           = xerrors.New("goboxer: not found")
+	ErrConflict           = xerrors.New("goboxer: conflict")
+	ErrItemNameInUse      = xerrors.New("goboxer: item name in use")
+	ErrPreconditionFailed = xerrors.New("goboxer: precondition failed")
+	ErrRateLimited        = xerrors.New("goboxer: rate limited")
+	ErrUnauthorized       = xerrors.New("goboxer: unauthorized")
+	ErrForbidden          = xerrors.New("goboxer: forbidden")
+	// ErrNotReady is returned when a thumbnail or a representation is not generated within APIConn.MaxPollingAttempts.
+	ErrNotReady = xerrors.New("goboxer: not generated yet")
+	// ErrRepresentationFailed is returned when the generation of a representation failed. (RepresentationStateError)
+	ErrRepresentationFailed = xerrors.New("goboxer: representation generation failed")
+	// ErrCircuitOpen is returned without sending the request while APIConn.CircuitBreaker is open. (*CircuitOpenError)
+	ErrCircuitOpen = xerrors.New("goboxer: circuit breaker is open")
+)
+
+const (
+	errorCodeItemNameInUse = "item_name_in_use"
+)
+
 // Refer https://developer.box.com/reference#errors
 type ApiStatusError struct {
 	Type        string                 `json:"type"`
@@ -50,7 +77,17 @@ type ApiStatusError struct {
 	HelpUrl     string                 `json:"help_url,omitempty"`
 	Message     string                 `json:"message,omitempty"`
 	RequestId   string                 `json:"request_id,omitempty"`
-	frame       xerrors.Frame
+
+	// Conflicts is the items in context_info.conflicts (e.g. for 409 item_name_in_use).
+	Conflicts []*ItemMini `json:"-"`
+	// Method and URL of the failed request.
+	Method string `json:"-"`
+	URL    string `json:"-"`
+	// RetryAfter is the value of Retry-After header in seconds. (0 if not specified)
+	RetryAfter int `json:"-"`
+	// Headers is the response headers.
+	Headers http.Header `json:"-"`
+	frame   xerrors.Frame
 }
 
 func (e *ApiStatusError) Format(f fmt.State, c rune) { // implements fmt.Formatter
@@ -58,7 +95,11 @@ func (e *ApiStatusError) Format(f fmt.State, c rune) { // implements fmt.Formatt
 }
 
 func (e *ApiStatusError) errorMsg() string {
-	return fmt.Sprintf("HTTP status code: [%d], Message: [%s], RequestId: [%s], ContextInfo: [%s]", e.Status, e.Message, e.RequestId, e.ContextInfo)
+	msg := fmt.Sprintf("HTTP status code: [%d], Message: [%s], RequestId: [%s], ContextInfo: [%s]", e.Status, e.Message, e.RequestId, e.ContextInfo)
+	if e.URL != "" {
+		msg += fmt.Sprintf(", Request: [%s %s]", e.Method, e.URL)
+	}
+	return msg
 }
 func (e *ApiStatusError) FormatError(p xerrors.Printer) error { // implements xerrors.Formatter
 	p.Print(e.errorMsg())
@@ -67,32 +108,114 @@ func (e *ApiStatusError) FormatError(p xerrors.Printer) error { // implements xe
 	}
 	return nil
 }
+
+// Unwrap returns the sentinel error matching this error's class, or nil.
 func (e *ApiStatusError) Unwrap() error {
-	return nil
+	if e.Code == errorCodeItemNameInUse {
+		return ErrItemNameInUse
+	}
+	switch e.Status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	default:
+		return nil
+	}
 }
+
+// Is reports whether this error belongs to the class of target.
+// ErrItemNameInUse is also ErrConflict.
+func (e *ApiStatusError) Is(target error) bool {
+	switch target {
+	case ErrConflict:
+		return e.Status == http.StatusConflict || e.Code == errorCodeItemNameInUse
+	default:
+		return false
+	}
+}
+
 func (e *ApiStatusError) Error() string {
 	return e.errorMsg()
 }
 
-// for internal use
-func newApiStatusError(errBody []byte) error {
-	e := &ApiStatusError{frame: xerrors.Caller(1)}
-	err := json.Unmarshal(errBody, e)
+func (e *ApiStatusError) decodeConflicts() {
+	conflicts, ok := e.ContextInfo["conflicts"]
+	if !ok || conflicts == nil {
+		return
+	}
+	b, err := json.Marshal(conflicts)
 	if err != nil {
-		body := string(errBody)
-		err = xerrors.Errorf("response json marshaling error: %w", err)
-		return newApiOtherError(err, body)
+		return
+	}
+	// "conflicts" is an array for files, but a single object for folders.
+	var items []*ItemMini
+	if err = json.Unmarshal(b, &items); err == nil {
+		e.Conflicts = items
+		return
+	}
+	var item ItemMini
+	if err = json.Unmarshal(b, &item); err == nil {
+		e.Conflicts = []*ItemMini{&item}
 	}
-	return e
 }
 
-func NewApiStatusError(errBody []byte) error {
-	e := &ApiStatusError{frame: xerrors.Caller(0)}
-	err := json.Unmarshal(errBody, e)
-	if err != nil {
-		body := string(errBody)
-		err = xerrors.Errorf("response json marshaling error: %w", err)
-		return newApiOtherError(err, body)
+func (e *ApiStatusError) setResponse(resp *Response) {
+	if resp == nil {
+		return
+	}
+	if e.Status == 0 {
+		e.Status = resp.ResponseCode
+	}
+	if resp.Request != nil {
+		if resp.Request.Method != 0 {
+			e.Method = convertMethodStr(resp.Request.Method)
+		}
+		e.URL = resp.Request.Url
+	}
+	e.Headers = resp.Headers
+	if resp.Headers != nil {
+		e.RetryAfter, _ = strconv.Atoi(resp.Headers.Get(HttpHeaderRetryAfter))
+	}
+}
+
+func buildApiStatusError(resp *Response, frame xerrors.Frame) error {
+	e := &ApiStatusError{frame: frame}
+	if len(resp.Body) == 0 {
+		// e.g. 429 or 5xx without body.
+		e.setResponse(resp)
+		return e
 	}
+	if err := json.Unmarshal(resp.Body, e); err != nil {
+		// e.g. HTML error page returned from a proxy. The status is kept, so errors.Is still works.
+		e = &ApiStatusError{Message: string(resp.Body), frame: frame}
+		e.setResponse(resp)
+		return e
+	}
+	e.decodeConflicts()
+	e.setResponse(resp)
 	return e
 }
+
+// for internal use
+func newApiStatusError(resp *Response) error {
+	return buildApiStatusError(resp, xerrors.Caller(1))
+}
+
+func NewApiStatusError(errBody []byte) error {
+	return buildApiStatusError(&Response{Body: errBody}, xerrors.Caller(0))
+}
+
+// NewApiStatusErrorFromResponse returns ApiStatusError built from the response, including the request method/URL,
+// Retry-After and the response headers.
+func NewApiStatusErrorFromResponse(resp *Response) error {
+	return buildApiStatusError(resp, xerrors.Caller(0))
+}
@@ -0,0 +1,118 @@
+package goboxer
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApiStatusError_Is(t *testing.T) {
+	notFound, _ := ioutil.ReadFile("testdata/genericerror/404.json")
+	nameInUse, _ := ioutil.ReadFile("testdata/genericerror/409.json")
+
+	sentinels := []error{ErrNotFound, ErrConflict, ErrItemNameInUse, ErrPreconditionFailed, ErrRateLimited, ErrUnauthorized, ErrForbidden}
+
+	tests := []struct {
+		name string
+		resp *Response
+		want []error
+	}{
+		{"404", &Response{ResponseCode: 404, Body: notFound}, []error{ErrNotFound}},
+		{"409/item_name_in_use", &Response{ResponseCode: 409, Body: nameInUse}, []error{ErrConflict, ErrItemNameInUse}},
+		{"409/other", &Response{ResponseCode: 409, Body: []byte(`{"type":"error","status":409,"code":"conflict"}`)}, []error{ErrConflict}},
+		{"412", &Response{ResponseCode: 412, Body: []byte(`{"type":"error","status":412,"code":"precondition_failed"}`)}, []error{ErrPreconditionFailed}},
+		{"429/no body", &Response{ResponseCode: 429, Headers: http.Header{}}, []error{ErrRateLimited}},
+		{"401", &Response{ResponseCode: 401, Body: []byte(`{"type":"error","status":401}`)}, []error{ErrUnauthorized}},
+		{"403", &Response{ResponseCode: 403, Body: []byte(`{"type":"error","status":403,"code":"access_denied_insufficient_permissions"}`)}, []error{ErrForbidden}},
+		{"400", &Response{ResponseCode: 400, Body: []byte(`{"type":"error","status":400,"code":"bad_request"}`)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewApiStatusErrorFromResponse(tt.resp)
+			for _, s := range sentinels {
+				want := false
+				for _, w := range tt.want {
+					if w == s {
+						want = true
+					}
+				}
+				if got := errors.Is(err, s); got != want {
+					t.Errorf("errors.Is(err, %v) = %t, want %t", s, got, want)
+				}
+			}
+			var statusErr *ApiStatusError
+			if !errors.As(err, &statusErr) {
+				t.Errorf("errors.As(err, *ApiStatusError) failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewApiStatusErrorFromResponse(t *testing.T) {
+	nameInUse, _ := ioutil.ReadFile("testdata/genericerror/409.json")
+	header := http.Header{}
+	header.Set(HttpHeaderRetryAfter, "12")
+
+	req := &Request{Url: "https://example.com/2.0/folders", Method: POST}
+	err := NewApiStatusErrorFromResponse(&Response{Request: req, ResponseCode: 409, Headers: header, Body: nameInUse})
+
+	var got *ApiStatusError
+	if !errors.As(err, &got) {
+		t.Fatalf("unexpected error type: %v", err)
+	}
+	if got.Method != http.MethodPost || got.URL != req.Url {
+		t.Errorf("method/url = %s %s", got.Method, got.URL)
+	}
+	if got.RetryAfter != 12 {
+		t.Errorf("RetryAfter = %d, want 12", got.RetryAfter)
+	}
+	if got.Headers.Get(HttpHeaderRetryAfter) != "12" {
+		t.Errorf("Headers = %v", got.Headers)
+	}
+	wantConflicts := []*ItemMini{{
+		Type:       setItemTypePtr(TYPE_FOLDER),
+		ID:         setStringPtr("98765"),
+		SequenceId: setStringPtr("0"),
+		ETag:       setStringPtr("0"),
+		Name:       setStringPtr("ct"),
+	}}
+	if diff := cmp.Diff(got.Conflicts, wantConflicts); diff != "" {
+		t.Errorf("Conflicts diff: (-got +want)\n%s", diff)
+	}
+}
+
+func TestApiStatusError_ConflictsObject(t *testing.T) {
+	body := []byte(`{"type":"error","status":409,"code":"item_name_in_use","context_info":{"conflicts":{"type":"file","id":"123","name":"a.txt"}}}`)
+	err := NewApiStatusError(body)
+
+	var got *ApiStatusError
+	if !errors.As(err, &got) {
+		t.Fatalf("unexpected error type: %v", err)
+	}
+	if len(got.Conflicts) != 1 || *got.Conflicts[0].ID != "123" {
+		t.Errorf("Conflicts = %v", got.Conflicts)
+	}
+}
+
+func TestApiStatusError_NonJSONBody(t *testing.T) {
+	req := &Request{Url: "https://example.com/2.0/files/1", Method: GET}
+	body := []byte("<html><body>502 Bad Gateway</body></html>")
+	err := NewApiStatusErrorFromResponse(&Response{Request: req, ResponseCode: 502, Body: body})
+
+	got, ok := err.(*ApiStatusError)
+	if !ok {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	if got.Status != 502 || got.Method != http.MethodGet || got.URL != req.Url || got.Message != string(body) {
+		t.Errorf("got %+v", got)
+	}
+	if err := NewApiStatusErrorFromResponse(&Response{ResponseCode: 404, Body: []byte("Not Found")}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is(err, ErrNotFound) = false: %v", err)
+	}
+	if _, ok := NewApiStatusErrorFromResponse(&Response{ResponseCode: 503}).(*ApiStatusError); !ok {
+		t.Errorf("empty body error is not *ApiStatusError")
+	}
+}
@@ -66,7 +66,7 @@ type Group struct {
 	InvitabilityLevel      *InvitabilityLevel      `json:"invitability_level,omitempty"`
 	MemberViewabilityLevel *MemberViewabilityLevel `json:"member_viewability_level,omitempty"`
 
-	changeFlag uint64
+	changedFlag uint64
 }
 
 const (
@@ -82,9 +82,9 @@ func (g *Group) ResourceType() BoxResourceType {
 	return GroupResource
 }
 
-func NewGroup(api *APIConn) *Group {
+func NewGroup(api Connection) *Group {
 	return &Group{
-		apiInfo: &apiInfo{api: api},
+		apiInfo: api.connInfo(),
 	}
 }
 
@@ -105,7 +105,14 @@ func (g *Group) GetGroupReq(groupId string, fields []string) *Request {
 	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
 		query = fmt.Sprintf("?%s", fieldsParams)
 	}
-	return NewRequest(g.apiInfo.api, baseUrl+query, GET, nil, nil)
+	return g.apiInfo.newRequest(baseUrl+query, GET, nil, nil)
+}
+
+// Get Group
+//
+// GetGroupBatch adds GetGroupReq to the batch request, and returns the handle of the group.
+func (g *Group) GetGroupBatch(batch *BatchRequest, groupId string, fields []string) *PendingGroup {
+	return batch.addGroup(g.GetGroupReq(groupId, fields))
 }
 
 // Get Group
@@ -115,16 +122,16 @@ func (g *Group) GetGroupReq(groupId string, fields []string) *Request {
 func (g *Group) GetGroup(groupId string, fields []string) (*Group, error) {
 
 	req := g.GetGroupReq(groupId, fields)
-	resp, err := req.Send()
+	resp, err := req.sendCached()
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &Group{apiInfo: &apiInfo{api: g.apiInfo.api}}
+	r := &Group{apiInfo: g.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -146,27 +153,34 @@ func (g *Group) CreateGroupReq(fields []string) *Request {
 	}
 
 	data := &Group{}
-	if g.changeFlag&cGroupName == cGroupName {
+	if g.changedFlag&cGroupName == cGroupName {
 		data.Name = g.Name
 	}
-	if g.changeFlag&cGroupProvenance == cGroupProvenance {
+	if g.changedFlag&cGroupProvenance == cGroupProvenance {
 		data.Provenance = g.Provenance
 	}
-	if g.changeFlag&cGroupExternalSyncIdentifier == cGroupExternalSyncIdentifier {
+	if g.changedFlag&cGroupExternalSyncIdentifier == cGroupExternalSyncIdentifier {
 		data.ExternalSyncIdentifier = g.ExternalSyncIdentifier
 	}
-	if g.changeFlag&cGroupDescription == cGroupDescription {
+	if g.changedFlag&cGroupDescription == cGroupDescription {
 		data.Description = g.Description
 	}
-	if g.changeFlag&cGroupInvitabilityLevel == cGroupInvitabilityLevel {
+	if g.changedFlag&cGroupInvitabilityLevel == cGroupInvitabilityLevel {
 		data.InvitabilityLevel = g.InvitabilityLevel
 	}
-	if g.changeFlag&cGroupMemberViewabilityLevel == cGroupMemberViewabilityLevel {
+	if g.changedFlag&cGroupMemberViewabilityLevel == cGroupMemberViewabilityLevel {
 		data.MemberViewabilityLevel = g.MemberViewabilityLevel
 	}
 
 	b, _ := json.Marshal(data)
-	return NewRequest(g.apiInfo.api, baseUrl+query, POST, nil, bytes.NewReader(b))
+	return g.apiInfo.newRequest(baseUrl+query, POST, nil, bytes.NewReader(b))
+}
+
+// Create Group
+//
+// CreateGroupBatch adds CreateGroupReq to the batch request, and returns the handle of the group.
+func (g *Group) CreateGroupBatch(batch *BatchRequest, fields []string) *PendingGroup {
+	return batch.addGroup(g.CreateGroupReq(fields))
 }
 
 // Create Group
@@ -182,10 +196,10 @@ func (g *Group) CreateGroup(fields []string) (*Group, error) {
 	}
 
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &Group{apiInfo: &apiInfo{api: g.apiInfo.api}}
+	r := &Group{apiInfo: g.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -195,32 +209,32 @@ func (g *Group) CreateGroup(fields []string) (*Group, error) {
 
 func (g *Group) SetName(name string) *Group {
 	g.Name = &name
-	g.changeFlag |= cGroupName
+	g.changedFlag |= cGroupName
 	return g
 }
 func (g *Group) SetProvenance(provenance string) *Group {
 	g.Provenance = &provenance
-	g.changeFlag |= cGroupProvenance
+	g.changedFlag |= cGroupProvenance
 	return g
 }
 func (g *Group) SetExternalSyncIdentifier(externalSyncIdentifier string) *Group {
 	g.ExternalSyncIdentifier = &externalSyncIdentifier
-	g.changeFlag |= cGroupExternalSyncIdentifier
+	g.changedFlag |= cGroupExternalSyncIdentifier
 	return g
 }
 func (g *Group) SetDescription(description string) *Group {
 	g.Description = &description
-	g.changeFlag |= cGroupDescription
+	g.changedFlag |= cGroupDescription
 	return g
 }
 func (g *Group) SetInvitabilityLevel(invitabilityLevel InvitabilityLevel) *Group {
 	g.InvitabilityLevel = &invitabilityLevel
-	g.changeFlag |= cGroupInvitabilityLevel
+	g.changedFlag |= cGroupInvitabilityLevel
 	return g
 }
 func (g *Group) SetMemberViewabiityLevel(memberViewabilityLevel MemberViewabilityLevel) *Group {
 	g.MemberViewabilityLevel = &memberViewabilityLevel
-	g.changeFlag |= cGroupMemberViewabilityLevel
+	g.changedFlag |= cGroupMemberViewabilityLevel
 	return g
 }
 
@@ -238,27 +252,34 @@ func (g *Group) UpdateGroupReq(groupId string, fields []string) *Request {
 	}
 
 	data := &Group{}
-	if g.changeFlag&cGroupName == cGroupName {
+	if g.changedFlag&cGroupName == cGroupName {
 		data.Name = g.Name
 	}
-	if g.changeFlag&cGroupProvenance == cGroupProvenance {
+	if g.changedFlag&cGroupProvenance == cGroupProvenance {
 		data.Provenance = g.Provenance
 	}
-	if g.changeFlag&cGroupExternalSyncIdentifier == cGroupExternalSyncIdentifier {
+	if g.changedFlag&cGroupExternalSyncIdentifier == cGroupExternalSyncIdentifier {
 		data.ExternalSyncIdentifier = g.ExternalSyncIdentifier
 	}
-	if g.changeFlag&cGroupDescription == cGroupDescription {
+	if g.changedFlag&cGroupDescription == cGroupDescription {
 		data.Description = g.Description
 	}
-	if g.changeFlag&cGroupInvitabilityLevel == cGroupInvitabilityLevel {
+	if g.changedFlag&cGroupInvitabilityLevel == cGroupInvitabilityLevel {
 		data.InvitabilityLevel = g.InvitabilityLevel
 	}
-	if g.changeFlag&cGroupMemberViewabilityLevel == cGroupMemberViewabilityLevel {
+	if g.changedFlag&cGroupMemberViewabilityLevel == cGroupMemberViewabilityLevel {
 		data.MemberViewabilityLevel = g.MemberViewabilityLevel
 	}
 
 	b, _ := json.Marshal(data)
-	return NewRequest(g.apiInfo.api, baseUrl+query, PUT, nil, bytes.NewReader(b))
+	return g.apiInfo.newRequest(baseUrl+query, PUT, nil, bytes.NewReader(b))
+}
+
+// Update Group
+//
+// UpdateGroupBatch adds UpdateGroupReq to the batch request, and returns the handle of the group.
+func (g *Group) UpdateGroupBatch(batch *BatchRequest, groupId string, fields []string) *PendingGroup {
+	return batch.addGroup(g.UpdateGroupReq(groupId, fields))
 }
 
 // Update Group
@@ -274,10 +295,10 @@ func (g *Group) UpdateGroup(groupId string, fields []string) (*Group, error) {
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &Group{apiInfo: &apiInfo{api: g.apiInfo.api}}
+	r := &Group{apiInfo: g.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -293,7 +314,14 @@ func (g *Group) DeleteGroupReq(groupId string) *Request {
 	var baseUrl string
 	baseUrl = fmt.Sprintf("%s%s%s", g.apiInfo.api.BaseURL, "groups/", groupId)
 
-	return NewRequest(g.apiInfo.api, baseUrl, DELETE, nil, nil)
+	return g.apiInfo.newRequest(baseUrl, DELETE, nil, nil)
+}
+
+// Delete Group
+//
+// DeleteGroupBatch adds DeleteGroupReq to the batch request, and returns the handle of its error.
+func (g *Group) DeleteGroupBatch(batch *BatchRequest, groupId string) *PendingNoContent {
+	return batch.addNoContent(g.DeleteGroupReq(groupId))
 }
 
 // Delete Group
@@ -309,7 +337,7 @@ func (g *Group) DeleteGroup(groupId string) error {
 	}
 
 	if resp.ResponseCode != http.StatusNoContent {
-		return newApiStatusError(resp.Body)
+		return newApiStatusError(resp)
 	}
 	return nil
 }
@@ -332,7 +360,7 @@ func (g *Group) GetEnterpriseGroupsReq(name string, offset int32, limit int32, f
 	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
 		query += fmt.Sprintf("&%s", fieldsParams)
 	}
-	return NewRequest(g.apiInfo.api, urlBase+query, GET, nil, nil)
+	return g.apiInfo.newRequest(urlBase+query, GET, nil, nil)
 }
 
 // Get Enterprise Groups
@@ -348,7 +376,7 @@ func (g *Group) GetEnterpriseGroups(name string, offset int32, limit int32, fiel
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, 0, 0, 0, newApiStatusError(resp.Body)
+		return nil, 0, 0, 0, newApiStatusError(resp)
 	}
 
 	groups := struct {
@@ -364,3 +392,22 @@ func (g *Group) GetEnterpriseGroups(name string, offset int32, limit int32, fiel
 	}
 	return groups.Entries, groups.Offset, groups.Limit, groups.TotalCount, nil
 }
+
+// Get All Enterprise Groups
+//
+// GetEnterpriseGroupsIter returns the iterator of all the groups in the enterprise. If pageSize is 0, 100 is used.
+func (g *Group) GetEnterpriseGroupsIter(name string, fields []string, pageSize int) *GroupIterator {
+	return &GroupIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := g.GetEnterpriseGroups(name, int32(offset), int32(limit), fields)
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get All Enterprise Groups
+//
+// GetEnterpriseGroupsAll returns all the groups in the enterprise.
+func (g *Group) GetEnterpriseGroupsAll(name string, fields []string) ([]*Group, error) {
+	var r []*Group
+	err := g.GetEnterpriseGroupsIter(name, fields, 1000).allInto(&r)
+	return r, err
+}
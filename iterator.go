@@ -0,0 +1,278 @@
+package goboxer
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// defaultPageSize is the page size used by iterators if not specified.
+const defaultPageSize = 100
+
+// Iterator walks all pages of a list API lazily.
+// While the items of a page are consumed, the next page is fetched concurrently.
+//
+//	it := goboxer.NewUser(apiConn).GetEnterpriseUsersIter("", nil, 0)
+//	defer it.Close()
+//	for it.Next() {
+//		user := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// An Iterator is not safe for concurrent use.
+type Iterator struct {
+	fetch    pageFetcher
+	cursor   string
+	started  bool
+	finished bool
+
+	page  []interface{}
+	index int
+	item  interface{}
+	err   error
+
+	prefetch chan pageResult
+	closed   bool
+}
+
+// pageFetcher fetches the page at the cursor, and returns the cursor of the next page.
+// more is false if the page is the last one.
+type pageFetcher func(cursor string) (items []interface{}, next string, more bool, err error)
+
+type pageResult struct {
+	items []interface{}
+	next  string
+	more  bool
+	err   error
+}
+
+func newIterator(initialCursor string, fetch pageFetcher) *Iterator {
+	return &Iterator{fetch: fetch, cursor: initialCursor}
+}
+
+// newOffsetIterator returns the iterator for the API paginated by offset and limit.
+func newOffsetIterator(pageSize int, fetch func(offset int, limit int) (items []interface{}, totalCount int, err error)) *Iterator {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return newIterator("0", func(cursor string) ([]interface{}, string, bool, error) {
+		offset, _ := strconv.Atoi(cursor)
+		items, totalCount, err := fetch(offset, pageSize)
+		if err != nil {
+			return nil, "", false, err
+		}
+		next := offset + len(items)
+		return items, strconv.Itoa(next), len(items) != 0 && next < totalCount, nil
+	})
+}
+
+// newMarkerIterator returns the iterator for the API paginated by marker and limit.
+func newMarkerIterator(pageSize int, fetch func(marker string, limit int) (items []interface{}, nextMarker string, err error)) *Iterator {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return newIterator("", func(cursor string) ([]interface{}, string, bool, error) {
+		items, nextMarker, err := fetch(cursor, pageSize)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return items, nextMarker, nextMarker != "", nil
+	})
+}
+
+func (it *Iterator) startFetch(cursor string) {
+	ch := make(chan pageResult, 1)
+	go func() {
+		items, next, more, err := it.fetch(cursor)
+		ch <- pageResult{items: items, next: next, more: more, err: err}
+	}()
+	it.prefetch = ch
+}
+
+// Next advances the iterator to the next item. It returns false when all items are consumed or an error occurred.
+func (it *Iterator) Next() bool {
+	it.item = nil
+	for {
+		if it.closed || it.err != nil {
+			return false
+		}
+		if it.index < len(it.page) {
+			it.item = it.page[it.index]
+			it.index++
+			return true
+		}
+		if it.finished {
+			return false
+		}
+		if !it.started {
+			it.started = true
+			it.startFetch(it.cursor)
+		}
+		r := <-it.prefetch
+		it.prefetch = nil
+		if r.err != nil {
+			it.err = r.err
+			return false
+		}
+		it.page, it.index = r.items, 0
+		if r.more {
+			it.cursor = r.next
+			it.startFetch(r.next)
+		} else {
+			it.finished = true
+		}
+	}
+}
+
+// Item returns the current item.
+func (it *Iterator) Item() interface{} {
+	return it.item
+}
+
+// Err returns the error occurred while fetching pages.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close stops the iteration. A page being prefetched is discarded.
+func (it *Iterator) Close() {
+	it.closed = true
+	it.item = nil
+}
+
+// all returns all the remaining items.
+func (it *Iterator) all() ([]interface{}, error) {
+	defer it.Close()
+	var items []interface{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// ItemIterator iterates files, folders and web links.
+type ItemIterator struct {
+	*Iterator
+}
+
+// Item returns the current item.
+func (it *ItemIterator) Item() BoxResource {
+	if r, ok := it.Iterator.Item().(BoxResource); ok {
+		return r
+	}
+	return nil
+}
+
+// UserIterator iterates users.
+type UserIterator struct {
+	*Iterator
+}
+
+// Item returns the current user.
+func (it *UserIterator) Item() *User {
+	if r, ok := it.Iterator.Item().(*User); ok {
+		return r
+	}
+	return nil
+}
+
+// GroupIterator iterates groups.
+type GroupIterator struct {
+	*Iterator
+}
+
+// Item returns the current group.
+func (it *GroupIterator) Item() *Group {
+	if r, ok := it.Iterator.Item().(*Group); ok {
+		return r
+	}
+	return nil
+}
+
+// MembershipIterator iterates group memberships.
+type MembershipIterator struct {
+	*Iterator
+}
+
+// Item returns the current membership.
+func (it *MembershipIterator) Item() *Membership {
+	if r, ok := it.Iterator.Item().(*Membership); ok {
+		return r
+	}
+	return nil
+}
+
+// CollaborationIterator iterates collaborations.
+type CollaborationIterator struct {
+	*Iterator
+}
+
+// Item returns the current collaboration.
+func (it *CollaborationIterator) Item() *Collaboration {
+	if r, ok := it.Iterator.Item().(*Collaboration); ok {
+		return r
+	}
+	return nil
+}
+
+// CommentIterator iterates comments.
+type CommentIterator struct {
+	*Iterator
+}
+
+// Item returns the current comment.
+func (it *CommentIterator) Item() *Comment {
+	if r, ok := it.Iterator.Item().(*Comment); ok {
+		return r
+	}
+	return nil
+}
+
+// MetadataTemplateIterator iterates metadata templates.
+type MetadataTemplateIterator struct {
+	*Iterator
+}
+
+// Item returns the current metadata template.
+func (it *MetadataTemplateIterator) Item() *MetadataTemplate {
+	if r, ok := it.Iterator.Item().(*MetadataTemplate); ok {
+		return r
+	}
+	return nil
+}
+
+// MetadataCascadePolicyIterator iterates metadata cascade policies.
+type MetadataCascadePolicyIterator struct {
+	*Iterator
+}
+
+// Item returns the current metadata cascade policy.
+func (it *MetadataCascadePolicyIterator) Item() *MetadataCascadePolicy {
+	if r, ok := it.Iterator.Item().(*MetadataCascadePolicy); ok {
+		return r
+	}
+	return nil
+}
+
+// pageItems converts the page of resources (e.g. []*User) to the items of the iterator.
+func pageItems(page interface{}) []interface{} {
+	v := reflect.ValueOf(page)
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items
+}
+
+// allInto stores all the remaining items into the slice pointed by dst (e.g. *[]*User).
+func (it *Iterator) allInto(dst interface{}) error {
+	items, err := it.all()
+	v := reflect.ValueOf(dst).Elem()
+	r := reflect.MakeSlice(v.Type(), len(items), len(items))
+	for i, item := range items {
+		r.Index(i).Set(reflect.ValueOf(item))
+	}
+	v.Set(r)
+	return err
+}
@@ -0,0 +1,168 @@
+package goboxer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// pagingServer serves n users (/2.0/users, offset) and n file collaborations (/2.0/files/1/collaborations, marker).
+// The page at the offset/marker "fail" returns 404.
+type pagingServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string
+}
+
+func newPagingServer(n int, fail string) *pagingServer {
+	s := &pagingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		s.mu.Lock()
+		s.requests = append(s.requests, r.URL.Path+"?"+q.Get("offset")+q.Get("marker"))
+		s.mu.Unlock()
+
+		w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		start, _ := strconv.Atoi(q.Get("offset") + q.Get("marker"))
+		if fail != "" && q.Get("offset")+q.Get("marker") == fail {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"error","status":404,"code":"not_found"}`))
+			return
+		}
+		end := start + limit
+		if end > n {
+			end = n
+		}
+		var entries []map[string]interface{}
+		for i := start; i < end; i++ {
+			if r.URL.Path == "/2.0/users" {
+				entries = append(entries, map[string]interface{}{"type": "user", "id": strconv.Itoa(i)})
+			} else {
+				entries = append(entries, map[string]interface{}{"type": "collaboration", "id": strconv.Itoa(i)})
+			}
+		}
+		body := map[string]interface{}{"entries": entries}
+		if r.URL.Path == "/2.0/users" {
+			body["total_count"] = n
+			body["offset"] = start
+			body["limit"] = limit
+		} else if end < n {
+			body["next_marker"] = strconv.Itoa(end)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	return s
+}
+
+func (s *pagingServer) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func TestUser_GetEnterpriseUsersIter(t *testing.T) {
+	ts := newPagingServer(250, "")
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	it := NewUser(apiConn).GetEnterpriseUsersIter("", nil, 100)
+	defer it.Close()
+
+	if len(ts.Requests()) != 0 {
+		t.Errorf("pages must be fetched lazily")
+	}
+	var ids []string
+	for it.Next() {
+		ids = append(ids, *it.Item().ID)
+		if len(ids) == 1 {
+			// the next page is prefetched while the first page is consumed
+			deadline := time.Now().Add(time.Second)
+			for len(ts.Requests()) < 2 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if got := ts.Requests(); len(got) != 2 || got[1] != "/2.0/users?100" {
+				t.Errorf("the next page is not prefetched: %v", got)
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if len(ids) != 250 || ids[0] != "0" || ids[249] != "249" {
+		t.Errorf("unexpected users: %d items", len(ids))
+	}
+	if got := strings.Join(ts.Requests(), ","); got != "/2.0/users?0,/2.0/users?100,/2.0/users?200" {
+		t.Errorf("requests = %s", got)
+	}
+}
+
+func TestFile_CollaborationsAll(t *testing.T) {
+	ts := newPagingServer(2500, "")
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	collabs, err := NewFile(apiConn).CollaborationsAll("1", nil)
+	if err != nil {
+		t.Fatalf("CollaborationsAll() error = %v", err)
+	}
+	if len(collabs) != 2500 || *collabs[2499].ID != "2499" || collabs[0].apiInfo == nil {
+		t.Errorf("unexpected collaborations: %d items", len(collabs))
+	}
+	if got := strings.Join(ts.Requests(), ","); got != "/2.0/files/1/collaborations?,/2.0/files/1/collaborations?1000,/2.0/files/1/collaborations?2000" {
+		t.Errorf("requests = %s", got)
+	}
+}
+
+func TestIterator_Err(t *testing.T) {
+	ts := newPagingServer(250, "100")
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	it := NewUser(apiConn).GetEnterpriseUsersIter("", nil, 100)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 100 {
+		t.Errorf("%d items before the error, want 100", n)
+	}
+	if !xerrors.Is(it.Err(), ErrNotFound) {
+		t.Errorf("Err() = %v, want ErrNotFound", it.Err())
+	}
+	if it.Next() {
+		t.Errorf("Next() after the error must return false")
+	}
+
+	failed := newPagingServer(250, "0")
+	defer failed.Close()
+	users, err := NewUser(commonInit(failed.URL)).GetEnterpriseUsersAll("", nil)
+	if !xerrors.Is(err, ErrNotFound) || len(users) != 0 {
+		t.Errorf("GetEnterpriseUsersAll() = %d users, %v, want ErrNotFound", len(users), err)
+	}
+}
+
+func TestIterator_Close(t *testing.T) {
+	ts := newPagingServer(250, "")
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	it := NewFile(apiConn).CollaborationsIter("1", nil, 0)
+	if !it.Next() || *it.Item().ID != "0" {
+		t.Fatalf("Next() must return the first item")
+	}
+	it.Close()
+	if it.Next() || it.Item() != nil {
+		t.Errorf("Next() after Close() must return false")
+	}
+	if it.Err() != nil {
+		t.Errorf("Err() = %v", it.Err())
+	}
+}
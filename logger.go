@@ -1,5 +1,12 @@
 package goboxer
 
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
 type Logger interface {
 	RequestDumpf(format string, args ...interface{})
 	ResponseDumpf(format string, args ...interface{})
@@ -11,3 +18,172 @@ type Logger interface {
 	EnabledLoggingResponseBody() bool
 	EnabledLoggingRequestBody() bool
 }
+
+// StructuredLogger is the key/value alternative to Logger.
+//
+// Its method set matches *slog.Logger, so a *slog.Logger can be assigned to StructuredLog as is.
+// Request and response bodies are only logged when the logger also implements BodyLoggingPolicy.
+type StructuredLogger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// BodyLoggingPolicy is implemented by a StructuredLogger that wants request/response bodies to be logged.
+type BodyLoggingPolicy interface {
+	EnabledLoggingResponseBody() bool
+	EnabledLoggingRequestBody() bool
+}
+
+// RedactedValue replaces secrets (access tokens, client secrets, passwords...) in logged requests and responses.
+const RedactedValue = "[REDACTED]"
+
+// headers never written to logs as is
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"Boxapi":              true, // may include shared_link_password
+}
+
+// json fields never written to logs as is
+var redactedFields = map[string]bool{
+	"access_token":         true,
+	"refresh_token":        true,
+	"client_secret":        true,
+	"assertion":            true,
+	"password":             true,
+	"shared_link_password": true,
+}
+
+// form/query parameters never written to logs as is.
+// "code" and "token" are only secrets in the oauth2 forms, so they are not redacted from json.
+var redactedParams = map[string]bool{
+	"access_token":         true,
+	"refresh_token":        true,
+	"client_secret":        true,
+	"assertion":            true,
+	"code":                 true,
+	"token":                true,
+	"password":             true,
+	"shared_link_password": true,
+}
+
+func redactHeader(header http.Header) map[string]string {
+	r := make(map[string]string, len(header))
+	for key, values := range header {
+		if redactedHeaders[http.CanonicalHeaderKey(key)] {
+			r[key] = RedactedValue
+			continue
+		}
+		r[key] = strings.Join(values, ", ")
+	}
+	return r
+}
+
+func redactValues(values url.Values) url.Values {
+	r := make(url.Values, len(values))
+	for key, v := range values {
+		if redactedParams[key] {
+			r[key] = []string{RedactedValue}
+			continue
+		}
+		r[key] = v
+	}
+	return r
+}
+
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	c := *u
+	c.RawQuery = redactValues(u.Query()).Encode()
+	return c.String()
+}
+
+func redactJSONValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			if redactedFields[key] && value != nil {
+				t[key] = RedactedValue
+				continue
+			}
+			t[key] = redactJSONValue(value)
+		}
+		return t
+	case []interface{}:
+		for i, value := range t {
+			t[i] = redactJSONValue(value)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// redactBody returns the body suitable for logging.
+// Bodies that can not be parsed as the given content type are omitted entirely.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	switch contentType {
+	case ContentTypeApplicationJson, ContentTypeApplicationJsonPatch:
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return "<unparsable json body omitted>"
+		}
+		b, err := json.Marshal(redactJSONValue(v))
+		if err != nil {
+			return "<unparsable json body omitted>"
+		}
+		return string(b)
+	case ContentTypeFormUrlEncoded:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "<unparsable form body omitted>"
+		}
+		return redactValues(values).Encode()
+	default:
+		return "<body omitted>"
+	}
+}
+
+func mediaType(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.TrimSpace(strings.ToLower(contentType))
+}
+
+func structuredBodyLoggingPolicy() (requestBody bool, responseBody bool) {
+	if p, ok := StructuredLog.(BodyLoggingPolicy); ok {
+		return p.EnabledLoggingRequestBody(), p.EnabledLoggingResponseBody()
+	}
+	return false, false
+}
+
+func logDebug(msg string, keysAndValues ...interface{}) {
+	if StructuredLog != nil {
+		StructuredLog.Debug(msg, keysAndValues...)
+	}
+}
+
+func logInfo(msg string, keysAndValues ...interface{}) {
+	if StructuredLog != nil {
+		StructuredLog.Info(msg, keysAndValues...)
+	}
+}
+
+func logWarn(msg string, keysAndValues ...interface{}) {
+	if StructuredLog != nil {
+		StructuredLog.Warn(msg, keysAndValues...)
+	}
+}
@@ -0,0 +1,151 @@
+package goboxer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type recordingLogger struct {
+	Main
+	records     []string
+	requestBody bool
+}
+
+func (l *recordingLogger) RequestDumpf(format string, args ...interface{}) {
+	l.records = append(l.records, fmt.Sprintf(format, args...))
+}
+func (l *recordingLogger) ResponseDumpf(format string, args ...interface{}) {
+	l.records = append(l.records, fmt.Sprintf(format, args...))
+}
+func (l *recordingLogger) EnabledLoggingRequestBody() bool {
+	return l.requestBody
+}
+
+type recordingStructuredLogger struct {
+	records []string
+	bodies  bool
+}
+
+func (l *recordingStructuredLogger) record(msg string, args ...interface{}) {
+	l.records = append(l.records, fmt.Sprintf("%s %v", msg, args))
+}
+func (l *recordingStructuredLogger) Debug(msg string, args ...interface{}) { l.record(msg, args...) }
+func (l *recordingStructuredLogger) Info(msg string, args ...interface{})  { l.record(msg, args...) }
+func (l *recordingStructuredLogger) Warn(msg string, args ...interface{})  { l.record(msg, args...) }
+func (l *recordingStructuredLogger) Error(msg string, args ...interface{}) { l.record(msg, args...) }
+func (l *recordingStructuredLogger) EnabledLoggingResponseBody() bool      { return l.bodies }
+func (l *recordingStructuredLogger) EnabledLoggingRequestBody() bool       { return l.bodies }
+
+func Test_redactBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"empty", ContentTypeApplicationJson, "", ""},
+		{"json/token response", ContentTypeApplicationJson,
+			`{"access_token":"AT","refresh_token":"RT","expires_in":3600}`,
+			`{"access_token":"[REDACTED]","expires_in":3600,"refresh_token":"[REDACTED]"}`},
+		{"json/shared link password", ContentTypeApplicationJson,
+			`{"shared_link":{"access":"open","password":"secret"}}`,
+			`{"shared_link":{"access":"open","password":"[REDACTED]"}}`},
+		{"json/password removal is kept", ContentTypeApplicationJson,
+			`{"shared_link":{"password":null}}`,
+			`{"shared_link":{"password":null}}`},
+		{"json/error code is kept", ContentTypeApplicationJson,
+			`{"type":"error","code":"not_found"}`,
+			`{"code":"not_found","type":"error"}`},
+		{"json/invalid", ContentTypeApplicationJson, `{"access_token":`, "<unparsable json body omitted>"},
+		{"json patch", ContentTypeApplicationJsonPatch,
+			`[{"op":"add","path":"/shared_link","value":{"password":"secret"}}]`,
+			`[{"op":"add","path":"/shared_link","value":{"password":"[REDACTED]"}}]`},
+		{"json patch/invalid", ContentTypeApplicationJsonPatch, `[{"op":`, "<unparsable json body omitted>"},
+		{"form", ContentTypeFormUrlEncoded,
+			"client_id=CID&client_secret=SECRET&code=CODE&grant_type=authorization_code",
+			"client_id=CID&client_secret=%5BREDACTED%5D&code=%5BREDACTED%5D&grant_type=authorization_code"},
+		{"other", "application/octet-stream", "binary", "<body omitted>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("redactBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_logRequest_Redaction(t *testing.T) {
+	defer func() {
+		Log = nil
+		StructuredLog = nil
+	}()
+
+	newRequest := func() *http.Request {
+		var params = url.Values{}
+		params.Add("grant_type", "refresh_token")
+		params.Add("refresh_token", "REFRESH_TOKEN_VALUE")
+		params.Add("client_secret", "CLIENT_SECRET_VALUE")
+		r, _ := http.NewRequest(http.MethodPost, "https://example.com/oauth2/token", strings.NewReader(params.Encode()))
+		r.Header.Set(httpHeaderAuthorization, "Bearer ACCESS_TOKEN_VALUE")
+		r.Header.Set(httpHeaderContentType, ContentTypeFormUrlEncoded)
+		return r
+	}
+	secrets := []string{"ACCESS_TOKEN_VALUE", "REFRESH_TOKEN_VALUE", "CLIENT_SECRET_VALUE"}
+
+	tests := []struct {
+		name        string
+		requestBody bool
+		wantBody    bool
+	}{
+		{"request body enabled", true, true},
+		{"request body disabled", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printf := &recordingLogger{requestBody: tt.requestBody}
+			structured := &recordingStructuredLogger{bodies: tt.requestBody}
+			Log = printf
+			StructuredLog = structured
+
+			logRequest(http.MethodPost, newRequest())
+
+			for _, out := range []string{strings.Join(printf.records, ""), strings.Join(structured.records, "")} {
+				for _, secret := range secrets {
+					if strings.Contains(out, secret) {
+						t.Errorf("secret %s is logged: %s", secret, out)
+					}
+				}
+				if !strings.Contains(out, RedactedValue) {
+					t.Errorf("redacted marker is not logged: %s", out)
+				}
+				if got := strings.Contains(out, "grant_type=refresh_token"); got != tt.wantBody {
+					t.Errorf("body logged = %t, want %t: %s", got, tt.wantBody, out)
+				}
+			}
+		})
+	}
+}
+
+func Test_logResponse_Redaction(t *testing.T) {
+	defer func() {
+		StructuredLog = nil
+	}()
+	structured := &recordingStructuredLogger{bodies: true}
+	StructuredLog = structured
+
+	resp := &http.Response{Header: http.Header{}, StatusCode: 200}
+	resp.Header.Set(httpHeaderContentType, ContentTypeApplicationJson+"; charset=utf-8")
+	logResponse(resp, []byte(`{"access_token":"ACCESS_TOKEN_VALUE","token_type":"bearer"}`), 10)
+
+	out := strings.Join(structured.records, "")
+	if strings.Contains(out, "ACCESS_TOKEN_VALUE") {
+		t.Errorf("secret is logged: %s", out)
+	}
+	if !strings.Contains(out, `"token_type":"bearer"`) {
+		t.Errorf("response body is not logged: %s", out)
+	}
+}
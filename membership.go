@@ -53,9 +53,9 @@ func (m *Membership) ResourceType() BoxResourceType {
 	return MembershipResource
 }
 
-func NewMembership(api *APIConn) *Membership {
+func NewMembership(api Connection) *Membership {
 	return &Membership{
-		apiInfo: &apiInfo{api: api},
+		apiInfo: api.connInfo(),
 	}
 }
 
@@ -67,7 +67,14 @@ func (m *Membership) GetMembershipReq(membershipId string) *Request {
 	var url string
 	url = fmt.Sprintf("%s%s%s", m.apiInfo.api.BaseURL, "group_memberships/", membershipId)
 
-	return NewRequest(m.apiInfo.api, url, GET, nil, nil)
+	return m.apiInfo.newRequest(url, GET, nil, nil)
+}
+
+// Get Membership
+//
+// GetMembershipBatch adds GetMembershipReq to the batch request, and returns the handle of the group membership.
+func (m *Membership) GetMembershipBatch(batch *BatchRequest, membershipId string) *PendingMembership {
+	return batch.addMembership(m.GetMembershipReq(membershipId))
 }
 
 // Get Membership
@@ -83,7 +90,7 @@ func (m *Membership) GetMembership(membershipId string) (*Membership, error) {
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
 	membership := Membership{apiInfo: m.apiInfo}
@@ -113,7 +120,14 @@ func (m *Membership) CreateMembershipReq() *Request {
 		data.Role = m.Role
 	}
 	b, _ := json.Marshal(data)
-	return NewRequest(m.apiInfo.api, url, POST, nil, bytes.NewReader(b))
+	return m.apiInfo.newRequest(url, POST, nil, bytes.NewReader(b))
+}
+
+// Create Membership
+//
+// CreateMembershipBatch adds CreateMembershipReq to the batch request, and returns the handle of the group membership.
+func (m *Membership) CreateMembershipBatch(batch *BatchRequest) *PendingMembership {
+	return batch.addMembership(m.CreateMembershipReq())
 }
 
 // Create Membership
@@ -129,7 +143,7 @@ func (m *Membership) CreateMembership() (*Membership, error) {
 	}
 
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
 	membership := &Membership{apiInfo: m.apiInfo}
@@ -183,7 +197,14 @@ func (m *Membership) UpdateMembershipReq(membershipId string) *Request {
 		data.Role = m.Role
 	}
 	b, _ := json.Marshal(data)
-	return NewRequest(m.apiInfo.api, url, PUT, nil, bytes.NewReader(b))
+	return m.apiInfo.newRequest(url, PUT, nil, bytes.NewReader(b))
+}
+
+// Update Membership
+//
+// UpdateMembershipBatch adds UpdateMembershipReq to the batch request, and returns the handle of the group membership.
+func (m *Membership) UpdateMembershipBatch(batch *BatchRequest, membershipId string) *PendingMembership {
+	return batch.addMembership(m.UpdateMembershipReq(membershipId))
 }
 
 // Update Membership
@@ -199,7 +220,7 @@ func (m *Membership) UpdateMembership(membershipId string) (*Membership, error)
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
 	membership := Membership{apiInfo: m.apiInfo}
@@ -219,7 +240,14 @@ func (m *Membership) DeleteMembershipReq(membershipId string) *Request {
 	var url string
 	url = fmt.Sprintf("%s%s%s", m.apiInfo.api.BaseURL, "group_memberships/", membershipId)
 
-	return NewRequest(m.apiInfo.api, url, DELETE, nil, nil)
+	return m.apiInfo.newRequest(url, DELETE, nil, nil)
+}
+
+// Delete Membership
+//
+// DeleteMembershipBatch adds DeleteMembershipReq to the batch request, and returns the handle of its error.
+func (m *Membership) DeleteMembershipBatch(batch *BatchRequest, membershipId string) *PendingNoContent {
+	return batch.addNoContent(m.DeleteMembershipReq(membershipId))
 }
 
 // Delete Membership
@@ -235,7 +263,7 @@ func (m *Membership) DeleteMembership(membershipId string) error {
 	}
 
 	if resp.ResponseCode != http.StatusNoContent {
-		return newApiStatusError(resp.Body)
+		return newApiStatusError(resp)
 	}
 	return nil
 }
@@ -251,7 +279,7 @@ func (m *Membership) GetMembershipForGroupReq(groupId string, offset int32, limi
 	}
 	url = fmt.Sprintf("%s%s%s%s?offset=%d&limit=%d", m.apiInfo.api.BaseURL, "groups/", groupId, "/memberships", offset, limit)
 
-	return NewRequest(m.apiInfo.api, url, GET, nil, nil)
+	return m.apiInfo.newRequest(url, GET, nil, nil)
 }
 
 // Get Memberships for Group
@@ -267,7 +295,7 @@ func (m *Membership) GetMembershipForGroup(groupId string, offset int32, limit i
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, 0, 0, 0, newApiStatusError(resp.Body)
+		return nil, 0, 0, 0, newApiStatusError(resp)
 	}
 
 	memberships := struct {
@@ -287,6 +315,25 @@ func (m *Membership) GetMembershipForGroup(groupId string, offset int32, limit i
 	return memberships.Entries, memberships.Offset, memberships.Limit, memberships.TotalCount, nil
 }
 
+// Get All Memberships for Group
+//
+// GetMembershipForGroupIter returns the iterator of all the memberships of the group. If pageSize is 0, 100 is used.
+func (m *Membership) GetMembershipForGroupIter(groupId string, pageSize int) *MembershipIterator {
+	return &MembershipIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := m.GetMembershipForGroup(groupId, int32(offset), int32(limit))
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get All Memberships for Group
+//
+// GetMembershipForGroupAll returns all the memberships of the group.
+func (m *Membership) GetMembershipForGroupAll(groupId string) ([]*Membership, error) {
+	var r []*Membership
+	err := m.GetMembershipForGroupIter(groupId, 1000).allInto(&r)
+	return r, err
+}
+
 // Get Memberships for User
 //
 // Returns all of the group memberships for a given user. Note this is only available to group admins. To retrieve group memberships for the user making the API request, use the users/me/memberships endpoint.
@@ -298,7 +345,7 @@ func (m *Membership) GetMembershipForUserReq(userId string, offset int32, limit
 	}
 	url = fmt.Sprintf("%s%s%s%s?offset=%d&limit=%d", m.apiInfo.api.BaseURL, "users/", userId, "/memberships", offset, limit)
 
-	return NewRequest(m.apiInfo.api, url, GET, nil, nil)
+	return m.apiInfo.newRequest(url, GET, nil, nil)
 }
 
 // Get Memberships for User
@@ -314,7 +361,7 @@ func (m *Membership) GetMembershipForUser(userId string, offset int32, limit int
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, 0, 0, 0, newApiStatusError(resp.Body)
+		return nil, 0, 0, 0, newApiStatusError(resp)
 	}
 
 	memberships := struct {
@@ -334,6 +381,25 @@ func (m *Membership) GetMembershipForUser(userId string, offset int32, limit int
 	return memberships.Entries, memberships.Offset, memberships.Limit, memberships.TotalCount, nil
 }
 
+// Get All Memberships for User
+//
+// GetMembershipForUserIter returns the iterator of all the memberships of the user. If pageSize is 0, 100 is used.
+func (m *Membership) GetMembershipForUserIter(userId string, pageSize int) *MembershipIterator {
+	return &MembershipIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := m.GetMembershipForUser(userId, int32(offset), int32(limit))
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get All Memberships for User
+//
+// GetMembershipForUserAll returns all the memberships of the user.
+func (m *Membership) GetMembershipForUserAll(userId string) ([]*Membership, error) {
+	var r []*Membership
+	err := m.GetMembershipForUserIter(userId, 1000).allInto(&r)
+	return r, err
+}
+
 // Get Collaborations for Group
 //
 // Returns all of the group collaborations for a given group. Note this is only available to group admins.
@@ -345,7 +411,7 @@ func (m *Membership) GetCollaborationsForGroupReq(groupId string, offset int32,
 	}
 	url = fmt.Sprintf("%s%s%s%s?offset=%d&limit=%d", m.apiInfo.api.BaseURL, "groups/", groupId, "/collaborations", offset, limit)
 
-	return NewRequest(m.apiInfo.api, url, GET, nil, nil)
+	return m.apiInfo.newRequest(url, GET, nil, nil)
 }
 
 // Get Collaborations for Group
@@ -361,7 +427,7 @@ func (m *Membership) GetCollaborationsForGroup(groupId string, offset int32, lim
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, 0, 0, 0, newApiStatusError(resp.Body)
+		return nil, 0, 0, 0, newApiStatusError(resp)
 	}
 
 	collabs := struct {
@@ -380,3 +446,22 @@ func (m *Membership) GetCollaborationsForGroup(groupId string, offset int32, lim
 	}
 	return collabs.Entries, collabs.Offset, collabs.Limit, collabs.TotalCount, nil
 }
+
+// Get All Collaborations for Group
+//
+// GetCollaborationsForGroupIter returns the iterator of all the collaborations of the group. If pageSize is 0, 100 is used.
+func (m *Membership) GetCollaborationsForGroupIter(groupId string, pageSize int) *CollaborationIterator {
+	return &CollaborationIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := m.GetCollaborationsForGroup(groupId, int32(offset), int32(limit))
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get All Collaborations for Group
+//
+// GetCollaborationsForGroupAll returns all the collaborations of the group.
+func (m *Membership) GetCollaborationsForGroupAll(groupId string) ([]*Collaboration, error) {
+	var r []*Collaboration
+	err := m.GetCollaborationsForGroupIter(groupId, 1000).allInto(&r)
+	return r, err
+}
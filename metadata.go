@@ -0,0 +1,375 @@
+package goboxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// MetadataScopeGlobal is the scope of the "properties" template, available in all enterprises.
+	MetadataScopeGlobal = "global"
+	// MetadataScopeEnterprise is the scope of the templates of the enterprise of the user.
+	MetadataScopeEnterprise = "enterprise"
+)
+
+// MetadataFields returns the field to request the metadata instance with the fields parameter of
+// GetFileInfo, GetInfo etc. (e.g. "metadata.enterprise.contract")
+func MetadataFields(scope string, templateKey string) string {
+	return fmt.Sprintf("metadata.%s.%s", scope, templateKey)
+}
+
+// MetadataInstance is a metadata instance applied to a file or a folder.
+//
+// The keys starting with "$" are the properties of the instance ($id, $template, $scope...),
+// and the other keys are the values of the fields of the template.
+// Use Unmarshal to decode the instance into a user struct.
+type MetadataInstance map[string]interface{}
+
+func (mi MetadataInstance) str(key string) string {
+	s, _ := mi[key].(string)
+	return s
+}
+
+func (mi MetadataInstance) ID() string {
+	return mi.str("$id")
+}
+
+// Parent returns the item of the instance. (e.g. "file_12345")
+func (mi MetadataInstance) Parent() string {
+	return mi.str("$parent")
+}
+
+func (mi MetadataInstance) Template() string {
+	return mi.str("$template")
+}
+
+func (mi MetadataInstance) Scope() string {
+	return mi.str("$scope")
+}
+
+// Version returns the version of the instance, incremented on each update.
+func (mi MetadataInstance) Version() int {
+	v, _ := mi["$version"].(float64)
+	return int(v)
+}
+
+func (mi MetadataInstance) CanEdit() bool {
+	v, _ := mi["$canEdit"].(bool)
+	return v
+}
+
+// Values returns the values of the fields, without the properties of the instance.
+func (mi MetadataInstance) Values() map[string]interface{} {
+	values := map[string]interface{}{}
+	for k, v := range mi {
+		if !strings.HasPrefix(k, "$") {
+			values[k] = v
+		}
+	}
+	return values
+}
+
+// Unmarshal decodes the instance into v, as the instance was unmarshaled from the json.
+//
+//	var contract struct {
+//		Vendor string  `json:"vendor"`
+//		Amount float64 `json:"amount"`
+//	}
+//	err := instance.Unmarshal(&contract)
+func (mi MetadataInstance) Unmarshal(v interface{}) error {
+	b, err := json.Marshal(mi)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal metadata instance: %w", err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return xerrors.Errorf("failed to unmarshal metadata instance: %w", err)
+	}
+	return nil
+}
+
+type MetadataOp string
+
+const (
+	MetadataOpAdd     MetadataOp = "add"
+	MetadataOpReplace MetadataOp = "replace"
+	MetadataOpRemove  MetadataOp = "remove"
+	MetadataOpTest    MetadataOp = "test"
+)
+
+// MetadataOperation is a JSON-Patch operation to update a metadata instance.
+type MetadataOperation struct {
+	Op    MetadataOp  `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// metadataPath returns the JSON pointer of the field. The leading "/" may be omitted.
+func metadataPath(path string) string {
+	if strings.HasPrefix(path, "/") {
+		return path
+	}
+	return "/" + path
+}
+
+// MetadataAdd adds the value of the field. (e.g. MetadataAdd("/status", "active"))
+func MetadataAdd(path string, value interface{}) MetadataOperation {
+	return MetadataOperation{Op: MetadataOpAdd, Path: metadataPath(path), Value: value}
+}
+
+// MetadataReplace replaces the value of the existing field.
+func MetadataReplace(path string, value interface{}) MetadataOperation {
+	return MetadataOperation{Op: MetadataOpReplace, Path: metadataPath(path), Value: value}
+}
+
+// MetadataRemove removes the field.
+func MetadataRemove(path string) MetadataOperation {
+	return MetadataOperation{Op: MetadataOpRemove, Path: metadataPath(path)}
+}
+
+// MetadataTest checks the value of the field. If the value differs, no operation of the update is applied.
+func MetadataTest(path string, value interface{}) MetadataOperation {
+	return MetadataOperation{Op: MetadataOpTest, Path: metadataPath(path), Value: value}
+}
+
+func metadataUrl(info *apiInfo, itemPath string, id string, scope string, templateKey string) string {
+	return fmt.Sprintf("%s%s%s/metadata/%s/%s", info.api.BaseURL, itemPath, id, scope, templateKey)
+}
+
+func allMetadataReq(info *apiInfo, itemPath string, id string) *Request {
+	url := fmt.Sprintf("%s%s%s/metadata", info.api.BaseURL, itemPath, id)
+	return info.newRequest(url, GET, nil, nil)
+}
+
+func createMetadataReq(info *apiInfo, itemPath string, id string, scope string, templateKey string, values interface{}) *Request {
+	bodyBytes, _ := json.Marshal(values)
+	return info.newRequest(metadataUrl(info, itemPath, id, scope, templateKey), POST, nil, bytes.NewReader(bodyBytes))
+}
+
+func updateMetadataReq(info *apiInfo, itemPath string, id string, scope string, templateKey string, ops []MetadataOperation) *Request {
+	if ops == nil {
+		ops = []MetadataOperation{}
+	}
+	bodyBytes, _ := json.Marshal(ops)
+	header := http.Header{}
+	header.Set(httpHeaderContentType, ContentTypeApplicationJsonPatch)
+	return info.newRequest(metadataUrl(info, itemPath, id, scope, templateKey), PUT, header, bytes.NewReader(bodyBytes))
+}
+
+func sendForAllMetadata(req *Request) ([]MetadataInstance, error) {
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := struct {
+		Entries []MetadataInstance `json:"entries"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r.Entries, nil
+}
+
+func sendForMetadata(req *Request, expectedCode int) (MetadataInstance, error) {
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != expectedCode {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := MetadataInstance{}
+	err = UnmarshalJSONWrapper(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func sendForMetadataDeletion(req *Request) error {
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
+
+// Get All Metadata on File
+//
+// Retrieves all metadata instances on a file.
+// https://developer.box.com/reference#get-all-metadata-on-file
+func (f *File) GetAllMetadataReq(fileId string) *Request {
+	return allMetadataReq(f.apiInfo, "files/", fileId)
+}
+
+// Get All Metadata on File
+//
+// Retrieves all metadata instances on a file.
+// https://developer.box.com/reference#get-all-metadata-on-file
+func (f *File) GetAllMetadata(fileId string) ([]MetadataInstance, error) {
+	return sendForAllMetadata(f.GetAllMetadataReq(fileId))
+}
+
+// Get Metadata on File
+//
+// Retrieves the metadata instance of the template on a file. Returns ErrNotFound if not applied.
+// https://developer.box.com/reference#get-metadata-1
+func (f *File) GetMetadataReq(fileId string, scope string, templateKey string) *Request {
+	return f.apiInfo.newRequest(metadataUrl(f.apiInfo, "files/", fileId, scope, templateKey), GET, nil, nil)
+}
+
+// Get Metadata on File
+//
+// Retrieves the metadata instance of the template on a file. Returns ErrNotFound if not applied.
+// https://developer.box.com/reference#get-metadata-1
+func (f *File) GetMetadata(fileId string, scope string, templateKey string) (MetadataInstance, error) {
+	return sendForMetadata(f.GetMetadataReq(fileId, scope, templateKey), http.StatusOK)
+}
+
+// Create Metadata on File
+//
+// Applies the metadata template to a file with the values (a map or a struct marshaled to json).
+// Returns ErrConflict if the template is already applied.
+// https://developer.box.com/reference#create-metadata
+func (f *File) CreateMetadataReq(fileId string, scope string, templateKey string, values interface{}) *Request {
+	return createMetadataReq(f.apiInfo, "files/", fileId, scope, templateKey, values)
+}
+
+// Create Metadata on File
+//
+// Applies the metadata template to a file with the values (a map or a struct marshaled to json).
+// Returns ErrConflict if the template is already applied.
+// https://developer.box.com/reference#create-metadata
+func (f *File) CreateMetadata(fileId string, scope string, templateKey string, values interface{}) (MetadataInstance, error) {
+	return sendForMetadata(f.CreateMetadataReq(fileId, scope, templateKey, values), http.StatusCreated)
+}
+
+// Update Metadata on File
+//
+// Updates the metadata instance on a file with JSON-Patch operations. The operations are applied atomically.
+// https://developer.box.com/reference#update-metadata
+func (f *File) UpdateMetadataReq(fileId string, scope string, templateKey string, ops []MetadataOperation) *Request {
+	return updateMetadataReq(f.apiInfo, "files/", fileId, scope, templateKey, ops)
+}
+
+// Update Metadata on File
+//
+// Updates the metadata instance on a file with JSON-Patch operations. The operations are applied atomically.
+// https://developer.box.com/reference#update-metadata
+func (f *File) UpdateMetadata(fileId string, scope string, templateKey string, ops []MetadataOperation) (MetadataInstance, error) {
+	return sendForMetadata(f.UpdateMetadataReq(fileId, scope, templateKey, ops), http.StatusOK)
+}
+
+// Delete Metadata on File
+//
+// Removes the metadata instance of the template from a file.
+// https://developer.box.com/reference#delete-metadata
+func (f *File) DeleteMetadataReq(fileId string, scope string, templateKey string) *Request {
+	return f.apiInfo.newRequest(metadataUrl(f.apiInfo, "files/", fileId, scope, templateKey), DELETE, nil, nil)
+}
+
+// Delete Metadata on File
+//
+// Removes the metadata instance of the template from a file.
+// https://developer.box.com/reference#delete-metadata
+func (f *File) DeleteMetadata(fileId string, scope string, templateKey string) error {
+	return sendForMetadataDeletion(f.DeleteMetadataReq(fileId, scope, templateKey))
+}
+
+// Get All Metadata on Folder
+//
+// Retrieves all metadata instances on a folder.
+// https://developer.box.com/reference#get-all-metadata-on-folder
+func (f *Folder) GetAllMetadataReq(folderId string) *Request {
+	return allMetadataReq(f.apiInfo, "folders/", folderId)
+}
+
+// Get All Metadata on Folder
+//
+// Retrieves all metadata instances on a folder.
+// https://developer.box.com/reference#get-all-metadata-on-folder
+func (f *Folder) GetAllMetadata(folderId string) ([]MetadataInstance, error) {
+	return sendForAllMetadata(f.GetAllMetadataReq(folderId))
+}
+
+// Get Metadata on Folder
+//
+// Retrieves the metadata instance of the template on a folder. Returns ErrNotFound if not applied.
+// https://developer.box.com/reference#get-metadata-on-folder
+func (f *Folder) GetMetadataReq(folderId string, scope string, templateKey string) *Request {
+	return f.apiInfo.newRequest(metadataUrl(f.apiInfo, "folders/", folderId, scope, templateKey), GET, nil, nil)
+}
+
+// Get Metadata on Folder
+//
+// Retrieves the metadata instance of the template on a folder. Returns ErrNotFound if not applied.
+// https://developer.box.com/reference#get-metadata-on-folder
+func (f *Folder) GetMetadata(folderId string, scope string, templateKey string) (MetadataInstance, error) {
+	return sendForMetadata(f.GetMetadataReq(folderId, scope, templateKey), http.StatusOK)
+}
+
+// Create Metadata on Folder
+//
+// Applies the metadata template to a folder with the values (a map or a struct marshaled to json).
+// Returns ErrConflict if the template is already applied.
+// https://developer.box.com/reference#create-metadata-on-folder
+func (f *Folder) CreateMetadataReq(folderId string, scope string, templateKey string, values interface{}) *Request {
+	return createMetadataReq(f.apiInfo, "folders/", folderId, scope, templateKey, values)
+}
+
+// Create Metadata on Folder
+//
+// Applies the metadata template to a folder with the values (a map or a struct marshaled to json).
+// Returns ErrConflict if the template is already applied.
+// https://developer.box.com/reference#create-metadata-on-folder
+func (f *Folder) CreateMetadata(folderId string, scope string, templateKey string, values interface{}) (MetadataInstance, error) {
+	return sendForMetadata(f.CreateMetadataReq(folderId, scope, templateKey, values), http.StatusCreated)
+}
+
+// Update Metadata on Folder
+//
+// Updates the metadata instance on a folder with JSON-Patch operations. The operations are applied atomically.
+// https://developer.box.com/reference#update-metadata-on-folder
+func (f *Folder) UpdateMetadataReq(folderId string, scope string, templateKey string, ops []MetadataOperation) *Request {
+	return updateMetadataReq(f.apiInfo, "folders/", folderId, scope, templateKey, ops)
+}
+
+// Update Metadata on Folder
+//
+// Updates the metadata instance on a folder with JSON-Patch operations. The operations are applied atomically.
+// https://developer.box.com/reference#update-metadata-on-folder
+func (f *Folder) UpdateMetadata(folderId string, scope string, templateKey string, ops []MetadataOperation) (MetadataInstance, error) {
+	return sendForMetadata(f.UpdateMetadataReq(folderId, scope, templateKey, ops), http.StatusOK)
+}
+
+// Delete Metadata on Folder
+//
+// Removes the metadata instance of the template from a folder.
+// https://developer.box.com/reference#delete-metadata-on-folder
+func (f *Folder) DeleteMetadataReq(folderId string, scope string, templateKey string) *Request {
+	return f.apiInfo.newRequest(metadataUrl(f.apiInfo, "folders/", folderId, scope, templateKey), DELETE, nil, nil)
+}
+
+// Delete Metadata on Folder
+//
+// Removes the metadata instance of the template from a folder.
+// https://developer.box.com/reference#delete-metadata-on-folder
+func (f *Folder) DeleteMetadata(folderId string, scope string, templateKey string) error {
+	return sendForMetadataDeletion(f.DeleteMetadataReq(folderId, scope, templateKey))
+}
@@ -0,0 +1,92 @@
+package goboxer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+func TestMetadata_Reqs(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	tests := []struct {
+		name            string
+		req             *Request
+		wantMethod      Method
+		wantUrl         string
+		wantContentType string
+		wantBody        string
+	}{
+		{"all on file", NewFile(apiConn).GetAllMetadataReq("10001"),
+			GET, url + "/2.0/files/10001/metadata", "", ""},
+		{"get on folder", NewFolder(apiConn).GetMetadataReq("20001", MetadataScopeGlobal, "properties"),
+			GET, url + "/2.0/folders/20001/metadata/global/properties", "", ""},
+		{"create on file", NewFile(apiConn).CreateMetadataReq("10001", MetadataScopeEnterprise, "contract", map[string]interface{}{"vendor": "Acme"}),
+			POST, url + "/2.0/files/10001/metadata/enterprise/contract", "", `{"vendor":"Acme"}`},
+		{"update on folder", NewFolder(apiConn).UpdateMetadataReq("20001", MetadataScopeEnterprise, "contract", []MetadataOperation{
+			MetadataTest("/vendor", "Acme"), MetadataReplace("amount", 0), MetadataAdd("/a~1b", true), MetadataRemove("/status")}),
+			PUT, url + "/2.0/folders/20001/metadata/enterprise/contract", ContentTypeApplicationJsonPatch,
+			`[{"op":"test","path":"/vendor","value":"Acme"},{"op":"replace","path":"/amount","value":0},{"op":"add","path":"/a~1b","value":true},{"op":"remove","path":"/status"}]`},
+		{"update without operations", NewFile(apiConn).UpdateMetadataReq("10001", MetadataScopeGlobal, "properties", nil),
+			PUT, url + "/2.0/files/10001/metadata/global/properties", ContentTypeApplicationJsonPatch, `[]`},
+		{"delete on file", NewFile(apiConn).DeleteMetadataReq("10001", MetadataScopeGlobal, "properties"),
+			DELETE, url + "/2.0/files/10001/metadata/global/properties", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Method != tt.wantMethod || tt.req.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want %v %s", tt.req.Method, tt.req.Url, tt.wantMethod, tt.wantUrl)
+			}
+			if got := tt.req.headers.Get(httpHeaderContentType); got != tt.wantContentType {
+				t.Errorf("Content-Type = %s, want %s", got, tt.wantContentType)
+			}
+			var body []byte
+			if tt.req.body != nil {
+				body, _ = ioutil.ReadAll(tt.req.body)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestMetadataInstance(t *testing.T) {
+	data := []byte(`{"type":"file","id":"10001","metadata":{"enterprise_12345":{"contract":{
+"$id":"01234500-12f1-1234-aa12-b1d234cb567e","$parent":"file_10001","$type":"contract-6bcba49f","$typeVersion":2,
+"$version":3,"$template":"contract","$scope":"enterprise_12345","$canEdit":true,"vendor":"Acme","amount":100.5}}}}`)
+	file := &File{}
+	if err := json.Unmarshal(data, file); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if file.Metadata.Instance(MetadataScopeGlobal, "contract") != nil {
+		t.Errorf("instance of other scope is returned")
+	}
+	mi := file.Metadata.Instance(MetadataScopeEnterprise, "contract")
+	if mi == nil {
+		t.Fatalf("instance is not found in %v", file.Metadata)
+	}
+	if mi.ID() != "01234500-12f1-1234-aa12-b1d234cb567e" || mi.Parent() != "file_10001" || mi.Template() != "contract" ||
+		mi.Scope() != "enterprise_12345" || mi.Version() != 3 || !mi.CanEdit() {
+		t.Errorf("unexpected properties of the instance: %v", mi)
+	}
+	if values := mi.Values(); len(values) != 2 || values["vendor"] != "Acme" {
+		t.Errorf("Values() = %v", values)
+	}
+
+	var contract struct {
+		Vendor string  `json:"vendor"`
+		Amount float64 `json:"amount"`
+		Scope  string  `json:"$scope"`
+	}
+	if err := mi.Unmarshal(&contract); err != nil || contract.Vendor != "Acme" || contract.Amount != 100.5 || contract.Scope != "enterprise_12345" {
+		t.Errorf("Unmarshal() = %+v, %v", contract, err)
+	}
+	var wrongType struct {
+		Vendor int `json:"vendor"`
+	}
+	if err := mi.Unmarshal(&wrongType); err == nil {
+		t.Errorf("Unmarshal() into the wrong type succeeded")
+	}
+}
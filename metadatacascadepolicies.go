@@ -0,0 +1,230 @@
+package goboxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type ConflictResolution string
+
+func (cr *ConflictResolution) String() string {
+	if cr == nil {
+		return "<nil>"
+	}
+	return string(*cr)
+}
+
+const (
+	// ConflictResolutionNone keeps the values of the instances already applied.
+	ConflictResolutionNone ConflictResolution = "none"
+	// ConflictResolutionOverwrite overwrites the values of the instances already applied.
+	ConflictResolutionOverwrite ConflictResolution = "overwrite"
+)
+
+// MetadataCascadePolicy applies the metadata instance on a folder to all the items in the folder.
+type MetadataCascadePolicy struct {
+	apiInfo         *apiInfo
+	ID              string `json:"id,omitempty"`
+	Type            string `json:"type,omitempty"`
+	OwnerEnterprise *struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	} `json:"owner_enterprise,omitempty"`
+	Parent      *ItemMini `json:"parent,omitempty"`
+	Scope       string    `json:"scope,omitempty"`
+	TemplateKey string    `json:"templateKey,omitempty"`
+}
+
+func NewMetadataCascadePolicy(api Connection) *MetadataCascadePolicy {
+	return &MetadataCascadePolicy{apiInfo: api.connInfo()}
+}
+
+func (mcp *MetadataCascadePolicy) sendForPolicy(req *Request, expectedCode int) (*MetadataCascadePolicy, error) {
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != expectedCode {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &MetadataCascadePolicy{apiInfo: mcp.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Get Metadata Cascade Policies
+//
+// Returns the metadata cascade policies on the folder, paginated by marker.
+// nextMarker is empty if there are no more policies.
+// https://developer.box.com/reference#get-all-metadata-cascade-policies
+//
+//	ownerEnterpriseId: returns the policies of the enterprise ("" means the enterprise of the user)
+func (mcp *MetadataCascadePolicy) ListReq(folderId string, ownerEnterpriseId string, marker string, limit int) *Request {
+	urlBase := fmt.Sprintf("%s%s", mcp.apiInfo.api.BaseURL, "metadata_cascade_policies")
+	query := fmt.Sprintf("?folder_id=%s&limit=%d", url.QueryEscape(folderId), limit)
+	if ownerEnterpriseId != "" {
+		query += fmt.Sprintf("&owner_enterprise_id=%s", url.QueryEscape(ownerEnterpriseId))
+	}
+	if marker != "" {
+		query += fmt.Sprintf("&marker=%s", url.QueryEscape(marker))
+	}
+	return mcp.apiInfo.newRequest(urlBase+query, GET, nil, nil)
+}
+
+// Get Metadata Cascade Policies
+//
+// Returns the metadata cascade policies on the folder, paginated by marker.
+// nextMarker is empty if there are no more policies.
+// https://developer.box.com/reference#get-all-metadata-cascade-policies
+//
+//	ownerEnterpriseId: returns the policies of the enterprise ("" means the enterprise of the user)
+func (mcp *MetadataCascadePolicy) List(folderId string, ownerEnterpriseId string, marker string, limit int) (outPolicies []*MetadataCascadePolicy, nextMarker string, err error) {
+	req := mcp.ListReq(folderId, ownerEnterpriseId, marker, limit)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, "", newApiStatusError(resp)
+	}
+
+	policies := struct {
+		NextMarker string                   `json:"next_marker,omitempty"`
+		Entries    []*MetadataCascadePolicy `json:"entries"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &policies)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, p := range policies.Entries {
+		p.apiInfo = mcp.apiInfo
+	}
+	return policies.Entries, policies.NextMarker, nil
+}
+
+// Get All Metadata Cascade Policies
+//
+// ListIter returns the iterator of all the metadata cascade policies on the folder. If pageSize is 0, 100 is used.
+func (mcp *MetadataCascadePolicy) ListIter(folderId string, ownerEnterpriseId string, pageSize int) *MetadataCascadePolicyIterator {
+	return &MetadataCascadePolicyIterator{newMarkerIterator(pageSize, func(marker string, limit int) ([]interface{}, string, error) {
+		page, nextMarker, err := mcp.List(folderId, ownerEnterpriseId, marker, limit)
+		return pageItems(page), nextMarker, err
+	})}
+}
+
+// Get All Metadata Cascade Policies
+//
+// ListAll returns all the metadata cascade policies on the folder.
+func (mcp *MetadataCascadePolicy) ListAll(folderId string, ownerEnterpriseId string) ([]*MetadataCascadePolicy, error) {
+	var r []*MetadataCascadePolicy
+	err := mcp.ListIter(folderId, ownerEnterpriseId, 100).allInto(&r)
+	return r, err
+}
+
+// Get Metadata Cascade Policy
+//
+// Retrieves a specific metadata cascade policy.
+// https://developer.box.com/reference#get-a-metadata-cascade-policy
+func (mcp *MetadataCascadePolicy) GetInfoReq(policyId string) *Request {
+	url := fmt.Sprintf("%s%s%s", mcp.apiInfo.api.BaseURL, "metadata_cascade_policies/", policyId)
+	return mcp.apiInfo.newRequest(url, GET, nil, nil)
+}
+
+// Get Metadata Cascade Policy
+//
+// Retrieves a specific metadata cascade policy.
+// https://developer.box.com/reference#get-a-metadata-cascade-policy
+func (mcp *MetadataCascadePolicy) GetInfo(policyId string) (*MetadataCascadePolicy, error) {
+	return mcp.sendForPolicy(mcp.GetInfoReq(policyId), http.StatusOK)
+}
+
+// Create Metadata Cascade Policy
+//
+// Creates a policy that applies the metadata instance of the template on the folder to all the items in the folder.
+// The instance must be applied to the folder before.
+// https://developer.box.com/reference#create-a-metadata-cascade-policy
+func (mcp *MetadataCascadePolicy) CreateReq(folderId string, scope string, templateKey string) *Request {
+	url := fmt.Sprintf("%s%s", mcp.apiInfo.api.BaseURL, "metadata_cascade_policies")
+	body := map[string]string{
+		"folder_id":   folderId,
+		"scope":       scope,
+		"templateKey": templateKey,
+	}
+	bodyBytes, _ := json.Marshal(body)
+	return mcp.apiInfo.newRequest(url, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Create Metadata Cascade Policy
+//
+// Creates a policy that applies the metadata instance of the template on the folder to all the items in the folder.
+// The instance must be applied to the folder before.
+// https://developer.box.com/reference#create-a-metadata-cascade-policy
+func (mcp *MetadataCascadePolicy) Create(folderId string, scope string, templateKey string) (*MetadataCascadePolicy, error) {
+	return mcp.sendForPolicy(mcp.CreateReq(folderId, scope, templateKey), http.StatusCreated)
+}
+
+// Delete Metadata Cascade Policy
+//
+// Deletes a metadata cascade policy. The instances already applied are not removed.
+// https://developer.box.com/reference#delete-a-metadata-cascade-policy
+func (mcp *MetadataCascadePolicy) DeleteReq(policyId string) *Request {
+	url := fmt.Sprintf("%s%s%s", mcp.apiInfo.api.BaseURL, "metadata_cascade_policies/", policyId)
+	return mcp.apiInfo.newRequest(url, DELETE, nil, nil)
+}
+
+// Delete Metadata Cascade Policy
+//
+// Deletes a metadata cascade policy. The instances already applied are not removed.
+// https://developer.box.com/reference#delete-a-metadata-cascade-policy
+func (mcp *MetadataCascadePolicy) Delete(policyId string) error {
+	req := mcp.DeleteReq(policyId)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
+
+// Force Apply Metadata Cascade Policy
+//
+// Applies the metadata instance of the policy to all the items in the folder now.
+// conflictResolution decides whether the instances already applied to the items are overwritten.
+// The policy is applied asynchronously.
+// https://developer.box.com/reference#force-apply-a-metadata-cascade-policy
+func (mcp *MetadataCascadePolicy) ForceApplyReq(policyId string, conflictResolution ConflictResolution) *Request {
+	url := fmt.Sprintf("%s%s%s%s", mcp.apiInfo.api.BaseURL, "metadata_cascade_policies/", policyId, "/apply")
+	bodyBytes, _ := json.Marshal(map[string]ConflictResolution{"conflict_resolution": conflictResolution})
+	return mcp.apiInfo.newRequest(url, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Force Apply Metadata Cascade Policy
+//
+// Applies the metadata instance of the policy to all the items in the folder now.
+// conflictResolution decides whether the instances already applied to the items are overwritten.
+// The policy is applied asynchronously.
+// https://developer.box.com/reference#force-apply-a-metadata-cascade-policy
+func (mcp *MetadataCascadePolicy) ForceApply(policyId string, conflictResolution ConflictResolution) error {
+	req := mcp.ForceApplyReq(policyId, conflictResolution)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusAccepted {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package goboxer
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestMetadataCascadePolicy_Reqs(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	tests := []struct {
+		name       string
+		req        *Request
+		wantMethod Method
+		wantUrl    string
+		wantBody   string
+	}{
+		{"list", NewMetadataCascadePolicy(apiConn).ListReq("20001", "", "", 100),
+			GET, url + "/2.0/metadata_cascade_policies?folder_id=20001&limit=100", ""},
+		{"list with owner and marker", NewMetadataCascadePolicy(apiConn).ListReq("20001", "12345", "m1", 10),
+			GET, url + "/2.0/metadata_cascade_policies?folder_id=20001&limit=10&owner_enterprise_id=12345&marker=m1", ""},
+		{"get", NewMetadataCascadePolicy(apiConn).GetInfoReq("30001"),
+			GET, url + "/2.0/metadata_cascade_policies/30001", ""},
+		{"create", NewMetadataCascadePolicy(apiConn).CreateReq("20001", MetadataScopeEnterprise, "contract"),
+			POST, url + "/2.0/metadata_cascade_policies", `{"folder_id":"20001","scope":"enterprise","templateKey":"contract"}`},
+		{"delete", NewMetadataCascadePolicy(apiConn).DeleteReq("30001"),
+			DELETE, url + "/2.0/metadata_cascade_policies/30001", ""},
+		{"force apply", NewMetadataCascadePolicy(apiConn).ForceApplyReq("30001", ConflictResolutionOverwrite),
+			POST, url + "/2.0/metadata_cascade_policies/30001/apply", `{"conflict_resolution":"overwrite"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Method != tt.wantMethod || tt.req.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want %v %s", tt.req.Method, tt.req.Url, tt.wantMethod, tt.wantUrl)
+			}
+			var body []byte
+			if tt.req.body != nil {
+				body, _ = ioutil.ReadAll(tt.req.body)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package goboxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// Metadata finds files and folders by their metadata instances. (Metadata.Query)
+//
+// The metadata instances of a file or folder are got by File.GetAllMetadata, Folder.GetMetadata, etc.
+type Metadata struct {
+	apiInfo *apiInfo
+}
+
+func NewMetadata(api Connection) *Metadata {
+	return &Metadata{apiInfo: api.connInfo()}
+}
+
+// MetadataQueryOrder is a sort key of the results of the metadata query.
+type MetadataQueryOrder struct {
+	FieldKey string `json:"field_key"`
+	// "ASC" or "DESC"
+	Direction string `json:"direction"`
+}
+
+// MetadataQueryFrom returns the "from" of the metadata query for the template. (e.g. "enterprise_12345.contract")
+// The enterprise scope must be qualified with the enterprise id.
+func MetadataQueryFrom(scope string, templateKey string) string {
+	return scope + "." + templateKey
+}
+
+// Metadata Query
+//
+// Returns the files and folders in the ancestor folder (recursively) whose instance of the template matches the query.
+// The values in the query must be bound by params (e.g. query: "amount >= :min", params: {"min": 100}),
+// use MetadataQueryBuilder to build them. Results are paginated by marker, nextMarker is empty if there are no more items.
+// https://developer.box.com/reference#metadata-query
+//
+//	from: the template of the instances. (e.g. "enterprise_12345.contract")
+//	fields: the fields of the items, request "metadata.<scope>.<templateKey>" to get the instance with the items.
+func (m *Metadata) QueryReq(from string, ancestorFolderId string, query string, params map[string]interface{}, orderBy []MetadataQueryOrder, fields []string, marker string, limit int) *Request {
+	url := fmt.Sprintf("%s%s", m.apiInfo.api.BaseURL, "metadata_queries/execute_read")
+
+	if limit > 100 {
+		limit = 100
+	}
+	body := map[string]interface{}{
+		"from":               from,
+		"ancestor_folder_id": ancestorFolderId,
+		"limit":              limit,
+	}
+	if query != "" {
+		body["query"] = query
+		body["query_params"] = params
+	}
+	if len(orderBy) != 0 {
+		body["order_by"] = orderBy
+	}
+	if len(fields) != 0 {
+		body["fields"] = fields
+	}
+	if marker != "" {
+		body["marker"] = marker
+	}
+	bodyBytes, _ := json.Marshal(body)
+	return m.apiInfo.newRequest(url, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Metadata Query
+//
+// Returns the files and folders in the ancestor folder (recursively) whose instance of the template matches the query.
+// The values in the query must be bound by params (e.g. query: "amount >= :min", params: {"min": 100}),
+// use MetadataQueryBuilder to build them. Results are paginated by marker, nextMarker is empty if there are no more items.
+// https://developer.box.com/reference#metadata-query
+//
+//	from: the template of the instances. (e.g. "enterprise_12345.contract")
+//	fields: the fields of the items, request "metadata.<scope>.<templateKey>" to get the instance with the items.
+func (m *Metadata) Query(from string, ancestorFolderId string, query string, params map[string]interface{}, orderBy []MetadataQueryOrder, fields []string, marker string, limit int) (outResources []BoxResource, nextMarker string, err error) {
+	req := m.QueryReq(from, ancestorFolderId, query, params, orderBy, fields, marker, limit)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, "", newApiStatusError(resp)
+	}
+	items := struct {
+		NextMarker string            `json:"next_marker,omitempty"`
+		Entries    []json.RawMessage `json:"entries"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &items)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var entries []BoxResource
+	for _, entity := range items.Entries {
+		boxResource, err := ParseResource(entity)
+		if err != nil {
+			return nil, "", err
+		}
+		setApiInfo(boxResource, m.apiInfo)
+		entries = append(entries, boxResource)
+	}
+	return entries, items.NextMarker, nil
+}
+
+// Metadata Query
+//
+// QueryIter returns the iterator of all the files and folders matching the metadata query. If pageSize is 0, 100 is used.
+func (m *Metadata) QueryIter(from string, ancestorFolderId string, query string, params map[string]interface{}, orderBy []MetadataQueryOrder, fields []string, pageSize int) *ItemIterator {
+	return &ItemIterator{newMarkerIterator(pageSize, func(marker string, limit int) ([]interface{}, string, error) {
+		page, nextMarker, err := m.Query(from, ancestorFolderId, query, params, orderBy, fields, marker, limit)
+		return pageItems(page), nextMarker, err
+	})}
+}
+
+// Metadata Query
+//
+// QueryAll returns all the files and folders matching the metadata query.
+func (m *Metadata) QueryAll(from string, ancestorFolderId string, query string, params map[string]interface{}, orderBy []MetadataQueryOrder, fields []string) ([]BoxResource, error) {
+	var r []BoxResource
+	err := m.QueryIter(from, ancestorFolderId, query, params, orderBy, fields, 100).allInto(&r)
+	return r, err
+}
+
+var metadataQueryFieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type metadataCondition struct {
+	field  string
+	op     string
+	values []interface{}
+	any    []*MetadataQueryBuilder
+}
+
+// MetadataQueryBuilder builds the query and the params of the metadata query.
+// The values are always bound as params, so they are never interpreted as a part of the query.
+// The conditions are joined by AND, use Or to join the alternatives.
+//
+//	query, params, err := goboxer.NewMetadataQueryBuilder().
+//		Equal("vendor", vendor).
+//		Or(goboxer.NewMetadataQueryBuilder().GreaterThan("amount", 100), goboxer.NewMetadataQueryBuilder().IsNull("amount")).
+//		Build()
+//	// query: "vendor = :p0 AND (amount > :p1 OR amount IS NULL)"
+type MetadataQueryBuilder struct {
+	conditions []*metadataCondition
+}
+
+func NewMetadataQueryBuilder() *MetadataQueryBuilder {
+	return &MetadataQueryBuilder{}
+}
+
+func (b *MetadataQueryBuilder) add(field string, op string, values ...interface{}) *MetadataQueryBuilder {
+	b.conditions = append(b.conditions, &metadataCondition{field: field, op: op, values: values})
+	return b
+}
+
+func (b *MetadataQueryBuilder) Equal(field string, value interface{}) *MetadataQueryBuilder {
+	return b.add(field, "=", value)
+}
+func (b *MetadataQueryBuilder) NotEqual(field string, value interface{}) *MetadataQueryBuilder {
+	return b.add(field, "!=", value)
+}
+func (b *MetadataQueryBuilder) GreaterThan(field string, value interface{}) *MetadataQueryBuilder {
+	return b.add(field, ">", value)
+}
+func (b *MetadataQueryBuilder) GreaterThanOrEqual(field string, value interface{}) *MetadataQueryBuilder {
+	return b.add(field, ">=", value)
+}
+func (b *MetadataQueryBuilder) LessThan(field string, value interface{}) *MetadataQueryBuilder {
+	return b.add(field, "<", value)
+}
+func (b *MetadataQueryBuilder) LessThanOrEqual(field string, value interface{}) *MetadataQueryBuilder {
+	return b.add(field, "<=", value)
+}
+
+// Like matches the string field with the pattern, "%" matches any characters. ILike ignores the case.
+func (b *MetadataQueryBuilder) Like(field string, pattern string) *MetadataQueryBuilder {
+	return b.add(field, "LIKE", pattern)
+}
+func (b *MetadataQueryBuilder) ILike(field string, pattern string) *MetadataQueryBuilder {
+	return b.add(field, "ILIKE", pattern)
+}
+func (b *MetadataQueryBuilder) In(field string, values ...interface{}) *MetadataQueryBuilder {
+	return b.add(field, "IN", values...)
+}
+func (b *MetadataQueryBuilder) NotIn(field string, values ...interface{}) *MetadataQueryBuilder {
+	return b.add(field, "NOT IN", values...)
+}
+func (b *MetadataQueryBuilder) IsNull(field string) *MetadataQueryBuilder {
+	return b.add(field, "IS NULL")
+}
+func (b *MetadataQueryBuilder) IsNotNull(field string) *MetadataQueryBuilder {
+	return b.add(field, "IS NOT NULL")
+}
+
+// Or adds the condition that matches if any of the alternatives matches.
+func (b *MetadataQueryBuilder) Or(alternatives ...*MetadataQueryBuilder) *MetadataQueryBuilder {
+	b.conditions = append(b.conditions, &metadataCondition{any: alternatives})
+	return b
+}
+
+// Build returns the query and the params for Metadata.Query.
+// The params are named p0, p1... in the order of the conditions.
+// time.Time values are formatted in RFC3339 for date fields.
+func (b *MetadataQueryBuilder) Build() (query string, params map[string]interface{}, err error) {
+	params = map[string]interface{}{}
+	query, err = b.build(params)
+	if err != nil {
+		return "", nil, err
+	}
+	return query, params, nil
+}
+
+func (b *MetadataQueryBuilder) build(params map[string]interface{}) (string, error) {
+	var conditions []string
+	for _, c := range b.conditions {
+		if c.any != nil {
+			var alternatives []string
+			for _, alt := range c.any {
+				s, err := alt.build(params)
+				if err != nil {
+					return "", err
+				}
+				if len(alt.conditions) > 1 {
+					s = "(" + s + ")"
+				}
+				alternatives = append(alternatives, s)
+			}
+			if len(alternatives) == 0 {
+				return "", xerrors.New("Or requires at least one alternative")
+			}
+			conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+			continue
+		}
+
+		if !metadataQueryFieldPattern.MatchString(c.field) {
+			return "", xerrors.Errorf("invalid field key %q", c.field)
+		}
+		var names []string
+		for _, v := range c.values {
+			name := fmt.Sprintf("p%d", len(params))
+			if t, ok := v.(time.Time); ok {
+				v = t.Format(time.RFC3339)
+			}
+			params[name] = v
+			names = append(names, ":"+name)
+		}
+		switch c.op {
+		case "IS NULL", "IS NOT NULL":
+			conditions = append(conditions, c.field+" "+c.op)
+		case "IN", "NOT IN":
+			if len(names) == 0 {
+				return "", xerrors.Errorf("%s of %q requires at least one value", c.op, c.field)
+			}
+			conditions = append(conditions, c.field+" "+c.op+" ("+strings.Join(names, ", ")+")")
+		default:
+			conditions = append(conditions, c.field+" "+c.op+" "+names[0])
+		}
+	}
+	if len(conditions) == 0 {
+		return "", xerrors.New("no conditions")
+	}
+	return strings.Join(conditions, " AND "), nil
+}
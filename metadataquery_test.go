@@ -0,0 +1,80 @@
+package goboxer
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMetadataQueryBuilder_Build(t *testing.T) {
+	b := NewMetadataQueryBuilder
+	signedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		builder    *MetadataQueryBuilder
+		wantQuery  string
+		wantParams map[string]interface{}
+		wantErr    bool
+	}{
+		{"comparisons", b().Equal("vendor", "Acme' OR 1=1").NotEqual("status", "void").GreaterThan("amount", 100).
+			GreaterThanOrEqual("amount", 50.5).LessThan("year", 2020).LessThanOrEqual("signedAt", signedAt),
+			"vendor = :p0 AND status != :p1 AND amount > :p2 AND amount >= :p3 AND year < :p4 AND signedAt <= :p5",
+			map[string]interface{}{"p0": "Acme' OR 1=1", "p1": "void", "p2": 100, "p3": 50.5, "p4": 2020, "p5": "2020-01-02T03:04:05Z"}, false},
+		{"like, in and null", b().Like("vendor", "Ac%").ILike("note", "%x%").In("status", "draft", "signed").NotIn("year", 2019).IsNull("tags").IsNotNull("amount"),
+			"vendor LIKE :p0 AND note ILIKE :p1 AND status IN (:p2, :p3) AND year NOT IN (:p4) AND tags IS NULL AND amount IS NOT NULL",
+			map[string]interface{}{"p0": "Ac%", "p1": "%x%", "p2": "draft", "p3": "signed", "p4": 2019}, false},
+		{"or", b().Equal("vendor", "Acme").Or(b().GreaterThan("amount", 100).LessThan("amount", 200), b().IsNull("amount")),
+			"vendor = :p0 AND ((amount > :p1 AND amount < :p2) OR amount IS NULL)",
+			map[string]interface{}{"p0": "Acme", "p1": 100, "p2": 200}, false},
+		{"no conditions", b(), "", nil, true},
+		{"invalid field", b().Equal("vendor = 'x' OR vendor", "y"), "", nil, true},
+		{"in without values", b().In("status"), "", nil, true},
+		{"or without alternatives", b().Or(), "", nil, true},
+		{"empty alternative", b().Or(b()), "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, params, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("params = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestMetadataQuery_QueryReq(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	tests := []struct {
+		name     string
+		req      *Request
+		wantBody string
+	}{
+		{"minimum", NewMetadata(apiConn).QueryReq(MetadataQueryFrom("enterprise_12345", "contract"), "0", "", nil, nil, nil, "", 100),
+			`{"ancestor_folder_id":"0","from":"enterprise_12345.contract","limit":100}`},
+		{"all", NewMetadata(apiConn).QueryReq("enterprise_12345.contract", "20001", "amount >= :p0", map[string]interface{}{"p0": 100},
+			[]MetadataQueryOrder{{FieldKey: "amount", Direction: "DESC"}}, []string{"name", "metadata.enterprise_12345.contract"}, "m1", 500),
+			`{"ancestor_folder_id":"20001","fields":["name","metadata.enterprise_12345.contract"],"from":"enterprise_12345.contract","limit":100,` +
+				`"marker":"m1","order_by":[{"field_key":"amount","direction":"DESC"}],"query":"amount \u003e= :p0","query_params":{"p0":100}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Method != POST || tt.req.Url != url+"/2.0/metadata_queries/execute_read" {
+				t.Errorf("got %v %s", tt.req.Method, tt.req.Url)
+			}
+			body, _ := ioutil.ReadAll(tt.req.body)
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package goboxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type MetadataFieldType string
+
+func (ft *MetadataFieldType) String() string {
+	if ft == nil {
+		return "<nil>"
+	}
+	return string(*ft)
+}
+
+const (
+	MetadataFieldString      MetadataFieldType = "string"
+	MetadataFieldFloat       MetadataFieldType = "float"
+	MetadataFieldDate        MetadataFieldType = "date"
+	MetadataFieldEnum        MetadataFieldType = "enum"
+	MetadataFieldMultiSelect MetadataFieldType = "multiSelect"
+)
+
+// MetadataFieldOption is an option of an enum or multiSelect field.
+type MetadataFieldOption struct {
+	ID  string `json:"id,omitempty"`
+	Key string `json:"key"`
+}
+
+// MetadataTemplateField is a field definition of a metadata template.
+type MetadataTemplateField struct {
+	ID          string                 `json:"id,omitempty"`
+	Type        MetadataFieldType      `json:"type"`
+	Key         string                 `json:"key"`
+	DisplayName string                 `json:"displayName"`
+	Description string                 `json:"description,omitempty"`
+	Hidden      bool                   `json:"hidden,omitempty"`
+	Options     []*MetadataFieldOption `json:"options,omitempty"`
+}
+
+// NewMetadataField returns the definition of the field. options are the keys of the options of an enum or multiSelect field.
+func NewMetadataField(typ MetadataFieldType, key string, displayName string, options ...string) *MetadataTemplateField {
+	field := &MetadataTemplateField{Type: typ, Key: key, DisplayName: displayName}
+	for _, option := range options {
+		field.Options = append(field.Options, &MetadataFieldOption{Key: option})
+	}
+	return field
+}
+
+type MetadataTemplate struct {
+	apiInfo                *apiInfo
+	ID                     string                   `json:"id,omitempty"`
+	Type                   string                   `json:"type,omitempty"`
+	Scope                  string                   `json:"scope,omitempty"`
+	TemplateKey            string                   `json:"templateKey,omitempty"`
+	DisplayName            string                   `json:"displayName,omitempty"`
+	Hidden                 bool                     `json:"hidden"`
+	Fields                 []*MetadataTemplateField `json:"fields"`
+	CopyInstanceOnItemCopy bool                     `json:"copyInstanceOnItemCopy"`
+}
+
+func NewMetadataTemplate(api Connection) *MetadataTemplate {
+	return &MetadataTemplate{apiInfo: api.connInfo()}
+}
+
+// SetTemplateKey sets the key of the template (for Create). If not set, the key is generated from the display name.
+func (mt *MetadataTemplate) SetTemplateKey(templateKey string) *MetadataTemplate {
+	mt.TemplateKey = templateKey
+	return mt
+}
+func (mt *MetadataTemplate) SetDisplayName(displayName string) *MetadataTemplate {
+	mt.DisplayName = displayName
+	return mt
+}
+func (mt *MetadataTemplate) SetHidden(hidden bool) *MetadataTemplate {
+	mt.Hidden = hidden
+	return mt
+}
+func (mt *MetadataTemplate) SetCopyInstanceOnItemCopy(copyInstanceOnItemCopy bool) *MetadataTemplate {
+	mt.CopyInstanceOnItemCopy = copyInstanceOnItemCopy
+	return mt
+}
+func (mt *MetadataTemplate) AddField(field *MetadataTemplateField) *MetadataTemplate {
+	mt.Fields = append(mt.Fields, field)
+	return mt
+}
+
+// Field returns the definition of the field, or nil.
+func (mt *MetadataTemplate) Field(key string) *MetadataTemplateField {
+	for _, f := range mt.Fields {
+		if f.Key == key {
+			return f
+		}
+	}
+	return nil
+}
+
+type MetadataTemplateOp string
+
+const (
+	MetadataTemplateOpEditTemplate            MetadataTemplateOp = "editTemplate"
+	MetadataTemplateOpAddField                MetadataTemplateOp = "addField"
+	MetadataTemplateOpEditField               MetadataTemplateOp = "editField"
+	MetadataTemplateOpRemoveField             MetadataTemplateOp = "removeField"
+	MetadataTemplateOpAddEnumOption           MetadataTemplateOp = "addEnumOption"
+	MetadataTemplateOpRemoveEnumOption        MetadataTemplateOp = "removeEnumOption"
+	MetadataTemplateOpAddMultiSelectOption    MetadataTemplateOp = "addMultiSelectOption"
+	MetadataTemplateOpRemoveMultiSelectOption MetadataTemplateOp = "removeMultiSelectOption"
+)
+
+// MetadataTemplateOperation is an operation to update a metadata template.
+type MetadataTemplateOperation struct {
+	Op                   MetadataTemplateOp `json:"op"`
+	Data                 interface{}        `json:"data,omitempty"`
+	FieldKey             string             `json:"fieldKey,omitempty"`
+	EnumOptionKey        string             `json:"enumOptionKey,omitempty"`
+	MultiSelectOptionKey string             `json:"multiSelectOptionKey,omitempty"`
+}
+
+// EditMetadataTemplate changes the properties of the template. (e.g. {"displayName": "Contract", "hidden": false})
+func EditMetadataTemplate(changes map[string]interface{}) MetadataTemplateOperation {
+	return MetadataTemplateOperation{Op: MetadataTemplateOpEditTemplate, Data: changes}
+}
+
+// AddMetadataField adds the field to the template.
+func AddMetadataField(field *MetadataTemplateField) MetadataTemplateOperation {
+	return MetadataTemplateOperation{Op: MetadataTemplateOpAddField, Data: field}
+}
+
+// EditMetadataField changes the properties of the field. (e.g. {"displayName": "Amount"})
+func EditMetadataField(fieldKey string, changes map[string]interface{}) MetadataTemplateOperation {
+	return MetadataTemplateOperation{Op: MetadataTemplateOpEditField, FieldKey: fieldKey, Data: changes}
+}
+
+// RemoveMetadataField removes the field and its values in all the instances.
+func RemoveMetadataField(fieldKey string) MetadataTemplateOperation {
+	return MetadataTemplateOperation{Op: MetadataTemplateOpRemoveField, FieldKey: fieldKey}
+}
+
+// AddMetadataEnumOption adds the option to the enum field.
+func AddMetadataEnumOption(fieldKey string, optionKey string) MetadataTemplateOperation {
+	return MetadataTemplateOperation{Op: MetadataTemplateOpAddEnumOption, FieldKey: fieldKey, Data: map[string]string{"key": optionKey}}
+}
+
+// RemoveMetadataEnumOption removes the option from the enum field.
+func RemoveMetadataEnumOption(fieldKey string, optionKey string) MetadataTemplateOperation {
+	return MetadataTemplateOperation{Op: MetadataTemplateOpRemoveEnumOption, FieldKey: fieldKey, EnumOptionKey: optionKey}
+}
+
+// AddMetadataMultiSelectOption adds the option to the multiSelect field.
+func AddMetadataMultiSelectOption(fieldKey string, optionKey string) MetadataTemplateOperation {
+	return MetadataTemplateOperation{Op: MetadataTemplateOpAddMultiSelectOption, FieldKey: fieldKey, Data: map[string]string{"key": optionKey}}
+}
+
+// RemoveMetadataMultiSelectOption removes the option from the multiSelect field.
+func RemoveMetadataMultiSelectOption(fieldKey string, optionKey string) MetadataTemplateOperation {
+	return MetadataTemplateOperation{Op: MetadataTemplateOpRemoveMultiSelectOption, FieldKey: fieldKey, MultiSelectOptionKey: optionKey}
+}
+
+func (mt *MetadataTemplate) schemaUrl(scope string, templateKey string) string {
+	return fmt.Sprintf("%s%s%s/%s/schema", mt.apiInfo.api.BaseURL, "metadata_templates/", scope, templateKey)
+}
+
+func (mt *MetadataTemplate) sendForTemplate(req *Request, expectedCode int) (*MetadataTemplate, error) {
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != expectedCode {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &MetadataTemplate{apiInfo: mt.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Get Metadata Template by Name
+//
+// Retrieves the metadata template by scope and template key.
+// https://developer.box.com/reference#get-metadata-schema
+func (mt *MetadataTemplate) GetInfoReq(scope string, templateKey string) *Request {
+	return mt.apiInfo.newRequest(mt.schemaUrl(scope, templateKey), GET, nil, nil)
+}
+
+// Get Metadata Template by Name
+//
+// Retrieves the metadata template by scope and template key.
+// https://developer.box.com/reference#get-metadata-schema
+func (mt *MetadataTemplate) GetInfo(scope string, templateKey string) (*MetadataTemplate, error) {
+	return mt.sendForTemplate(mt.GetInfoReq(scope, templateKey), http.StatusOK)
+}
+
+// Get Metadata Template by ID
+//
+// Retrieves the metadata template by its ID.
+// https://developer.box.com/reference#get-metadata-template-by-id
+func (mt *MetadataTemplate) GetInfoByIdReq(templateId string) *Request {
+	url := fmt.Sprintf("%s%s%s", mt.apiInfo.api.BaseURL, "metadata_templates/", templateId)
+	return mt.apiInfo.newRequest(url, GET, nil, nil)
+}
+
+// Get Metadata Template by ID
+//
+// Retrieves the metadata template by its ID.
+// https://developer.box.com/reference#get-metadata-template-by-id
+func (mt *MetadataTemplate) GetInfoById(templateId string) (*MetadataTemplate, error) {
+	return mt.sendForTemplate(mt.GetInfoByIdReq(templateId), http.StatusOK)
+}
+
+// Create Metadata Template
+//
+// Creates a metadata template in the scope with the template key, display name, fields... set by SetXxx and AddField.
+// https://developer.box.com/reference#create-metadata-schema
+func (mt *MetadataTemplate) CreateReq(scope string) *Request {
+	url := fmt.Sprintf("%s%s", mt.apiInfo.api.BaseURL, "metadata_templates/schema")
+
+	body := *mt
+	body.Scope = scope
+	if body.Fields == nil {
+		body.Fields = []*MetadataTemplateField{}
+	}
+	bodyBytes, _ := json.Marshal(&body)
+	return mt.apiInfo.newRequest(url, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Create Metadata Template
+//
+// Creates a metadata template in the scope with the template key, display name, fields... set by SetXxx and AddField.
+// https://developer.box.com/reference#create-metadata-schema
+func (mt *MetadataTemplate) Create(scope string) (*MetadataTemplate, error) {
+	return mt.sendForTemplate(mt.CreateReq(scope), http.StatusCreated)
+}
+
+// Update Metadata Template
+//
+// Updates the metadata template with the operations. The operations are applied atomically.
+// https://developer.box.com/reference#update-metadata-schema
+func (mt *MetadataTemplate) UpdateReq(scope string, templateKey string, ops []MetadataTemplateOperation) *Request {
+	if ops == nil {
+		ops = []MetadataTemplateOperation{}
+	}
+	bodyBytes, _ := json.Marshal(ops)
+	return mt.apiInfo.newRequest(mt.schemaUrl(scope, templateKey), PUT, nil, bytes.NewReader(bodyBytes))
+}
+
+// Update Metadata Template
+//
+// Updates the metadata template with the operations. The operations are applied atomically.
+// https://developer.box.com/reference#update-metadata-schema
+func (mt *MetadataTemplate) Update(scope string, templateKey string, ops []MetadataTemplateOperation) (*MetadataTemplate, error) {
+	return mt.sendForTemplate(mt.UpdateReq(scope, templateKey, ops), http.StatusOK)
+}
+
+// Delete Metadata Template
+//
+// Deletes the metadata template and all its instances.
+// https://developer.box.com/reference#delete-metadata-schema
+func (mt *MetadataTemplate) DeleteReq(scope string, templateKey string) *Request {
+	return mt.apiInfo.newRequest(mt.schemaUrl(scope, templateKey), DELETE, nil, nil)
+}
+
+// Delete Metadata Template
+//
+// Deletes the metadata template and all its instances.
+// https://developer.box.com/reference#delete-metadata-schema
+func (mt *MetadataTemplate) Delete(scope string, templateKey string) error {
+	req := mt.DeleteReq(scope, templateKey)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
+
+// Get Enterprise Templates
+//
+// Returns the metadata templates of the enterprise of the user, paginated by marker.
+// nextMarker is empty if there are no more templates.
+// https://developer.box.com/reference#get-enterprise-templates
+func (mt *MetadataTemplate) EnterpriseTemplatesReq(marker string, limit int) *Request {
+	urlBase := fmt.Sprintf("%s%s", mt.apiInfo.api.BaseURL, "metadata_templates/enterprise")
+	if limit > 500 {
+		limit = 500
+	}
+	query := fmt.Sprintf("?limit=%d", limit)
+	if marker != "" {
+		query += fmt.Sprintf("&marker=%s", url.QueryEscape(marker))
+	}
+	return mt.apiInfo.newRequest(urlBase+query, GET, nil, nil)
+}
+
+// Get Enterprise Templates
+//
+// Returns the metadata templates of the enterprise of the user, paginated by marker.
+// nextMarker is empty if there are no more templates.
+// https://developer.box.com/reference#get-enterprise-templates
+func (mt *MetadataTemplate) EnterpriseTemplates(marker string, limit int) (outTemplates []*MetadataTemplate, nextMarker string, err error) {
+	req := mt.EnterpriseTemplatesReq(marker, limit)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, "", newApiStatusError(resp)
+	}
+
+	templates := struct {
+		NextMarker string              `json:"next_marker,omitempty"`
+		Entries    []*MetadataTemplate `json:"entries"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &templates)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, t := range templates.Entries {
+		t.apiInfo = mt.apiInfo
+	}
+	return templates.Entries, templates.NextMarker, nil
+}
+
+// Get All Enterprise Templates
+//
+// EnterpriseTemplatesIter returns the iterator of all the metadata templates of the enterprise. If pageSize is 0, 100 is used.
+func (mt *MetadataTemplate) EnterpriseTemplatesIter(pageSize int) *MetadataTemplateIterator {
+	return &MetadataTemplateIterator{newMarkerIterator(pageSize, func(marker string, limit int) ([]interface{}, string, error) {
+		page, nextMarker, err := mt.EnterpriseTemplates(marker, limit)
+		return pageItems(page), nextMarker, err
+	})}
+}
+
+// Get All Enterprise Templates
+//
+// EnterpriseTemplatesAll returns all the metadata templates of the enterprise.
+func (mt *MetadataTemplate) EnterpriseTemplatesAll() ([]*MetadataTemplate, error) {
+	var r []*MetadataTemplate
+	err := mt.EnterpriseTemplatesIter(500).allInto(&r)
+	return r, err
+}
@@ -0,0 +1,97 @@
+package goboxer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+func TestMetadataTemplate_Reqs(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	template := NewMetadataTemplate(apiConn).SetTemplateKey("contract").SetDisplayName("Contract").SetCopyInstanceOnItemCopy(true).
+		AddField(NewMetadataField(MetadataFieldString, "vendor", "Vendor")).
+		AddField(NewMetadataField(MetadataFieldEnum, "status", "Status", "draft", "signed"))
+
+	tests := []struct {
+		name       string
+		req        *Request
+		wantMethod Method
+		wantUrl    string
+		wantBody   string
+	}{
+		{"get", NewMetadataTemplate(apiConn).GetInfoReq(MetadataScopeEnterprise, "contract"),
+			GET, url + "/2.0/metadata_templates/enterprise/contract/schema", ""},
+		{"get by id", NewMetadataTemplate(apiConn).GetInfoByIdReq("f7a9891f"),
+			GET, url + "/2.0/metadata_templates/f7a9891f", ""},
+		{"create", template.CreateReq(MetadataScopeEnterprise),
+			POST, url + "/2.0/metadata_templates/schema",
+			`{"scope":"enterprise","templateKey":"contract","displayName":"Contract","hidden":false,"fields":[` +
+				`{"type":"string","key":"vendor","displayName":"Vendor"},` +
+				`{"type":"enum","key":"status","displayName":"Status","options":[{"key":"draft"},{"key":"signed"}]}],"copyInstanceOnItemCopy":true}`},
+		{"create without fields", NewMetadataTemplate(apiConn).SetDisplayName("Empty").CreateReq(MetadataScopeEnterprise),
+			POST, url + "/2.0/metadata_templates/schema",
+			`{"scope":"enterprise","displayName":"Empty","hidden":false,"fields":[],"copyInstanceOnItemCopy":false}`},
+		{"update", NewMetadataTemplate(apiConn).UpdateReq(MetadataScopeEnterprise, "contract", []MetadataTemplateOperation{
+			EditMetadataTemplate(map[string]interface{}{"hidden": true}),
+			AddMetadataField(NewMetadataField(MetadataFieldFloat, "amount", "Amount")),
+			EditMetadataField("vendor", map[string]interface{}{"displayName": "Supplier"}),
+			RemoveMetadataField("note"),
+			AddMetadataEnumOption("status", "expired"),
+			RemoveMetadataEnumOption("status", "draft"),
+			AddMetadataMultiSelectOption("tags", "legal"),
+			RemoveMetadataMultiSelectOption("tags", "hr"),
+		}),
+			PUT, url + "/2.0/metadata_templates/enterprise/contract/schema",
+			`[{"op":"editTemplate","data":{"hidden":true}},` +
+				`{"op":"addField","data":{"type":"float","key":"amount","displayName":"Amount"}},` +
+				`{"op":"editField","data":{"displayName":"Supplier"},"fieldKey":"vendor"},` +
+				`{"op":"removeField","fieldKey":"note"},` +
+				`{"op":"addEnumOption","data":{"key":"expired"},"fieldKey":"status"},` +
+				`{"op":"removeEnumOption","fieldKey":"status","enumOptionKey":"draft"},` +
+				`{"op":"addMultiSelectOption","data":{"key":"legal"},"fieldKey":"tags"},` +
+				`{"op":"removeMultiSelectOption","fieldKey":"tags","multiSelectOptionKey":"hr"}]`},
+		{"delete", NewMetadataTemplate(apiConn).DeleteReq(MetadataScopeEnterprise, "contract"),
+			DELETE, url + "/2.0/metadata_templates/enterprise/contract/schema", ""},
+		{"enterprise templates", NewMetadataTemplate(apiConn).EnterpriseTemplatesReq("", 100),
+			GET, url + "/2.0/metadata_templates/enterprise?limit=100", ""},
+		{"enterprise templates with marker", NewMetadataTemplate(apiConn).EnterpriseTemplatesReq("m+1", 1000),
+			GET, url + "/2.0/metadata_templates/enterprise?limit=500&marker=m%2B1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Method != tt.wantMethod || tt.req.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want %v %s", tt.req.Method, tt.req.Url, tt.wantMethod, tt.wantUrl)
+			}
+			var body []byte
+			if tt.req.body != nil {
+				body, _ = ioutil.ReadAll(tt.req.body)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestMetadataTemplate_Unmarshal(t *testing.T) {
+	data := []byte(`{"id":"58063d82-4128-7b43-bba9-92f706befcdf","type":"metadata_template","scope":"enterprise_12345",
+"templateKey":"contract","displayName":"Contract","hidden":false,"copyInstanceOnItemCopy":true,"fields":[
+{"id":"822227e0","type":"string","key":"vendor","displayName":"Vendor","hidden":false},
+{"id":"822227e1","type":"multiSelect","key":"tags","displayName":"Tags","hidden":false,"options":[{"id":"1","key":"legal"},{"id":"2","key":"hr"}]}]}`)
+	template := &MetadataTemplate{}
+	if err := json.Unmarshal(data, template); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if template.Scope != "enterprise_12345" || template.TemplateKey != "contract" || !template.CopyInstanceOnItemCopy {
+		t.Errorf("unexpected template: %+v", template)
+	}
+	if template.Field("status") != nil {
+		t.Errorf("undefined field is returned")
+	}
+	tags := template.Field("tags")
+	if tags == nil || tags.Type != MetadataFieldMultiSelect || len(tags.Options) != 2 || tags.Options[1].Key != "hr" {
+		t.Errorf("Field(tags) = %+v", tags)
+	}
+}
@@ -0,0 +1,16 @@
+package goboxer
+
+import (
+	"time"
+)
+
+// MetricsHook receives the metrics of the requests made by this library. (Metrics)
+//
+// Its methods are called from the goroutines sending requests concurrently.
+type MetricsHook interface {
+	// RequestCompleted is called for every HTTP request attempt, including retries.
+	// status is 0 if no response was received (e.g. network errors, or failed fast by CircuitBreaker).
+	RequestCompleted(method string, host string, status int, duration time.Duration)
+	// CircuitStateChanged is called when the state of the circuit of the host is changed by CircuitBreaker.
+	CircuitStateChanged(host string, from CircuitState, to CircuitState)
+}
@@ -0,0 +1,97 @@
+package goboxer
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// DefaultModifyAttempts is the number of attempts of ModifyFile and ModifyFolder when maxAttempts is not positive.
+const DefaultModifyAttempts = 3
+
+// ModifyFile updates the file with optimistic concurrency control.
+//
+// The current file is fetched and passed to mutate, which changes it with SetXxx methods (e.g. SetName).
+// The changes are sent with the etag of the fetched file as "If-Match" header.
+// If the file has been changed in the meantime (412 Precondition Failed),
+// the file is fetched and mutate is applied again, up to maxAttempts times.
+// If mutate returns an error, the modification is aborted and the error is returned.
+// If mutate changes nothing, the fetched file is returned without an update.
+func ModifyFile(api Connection, fileId string, maxAttempts int, mutate func(file *File) error) (*File, error) {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultModifyAttempts
+	}
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var file *File
+		file, err = NewFile(api).GetFileInfo(fileId, false, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err = mutate(file); err != nil {
+			return nil, err
+		}
+		if file.changedFlag == 0 {
+			return file, nil
+		}
+		var updated *File
+		updated, err = file.Update(fileId, toString(file.ETag), nil)
+		if err == nil {
+			return updated, nil
+		}
+		if !xerrors.Is(err, ErrPreconditionFailed) {
+			return nil, err
+		}
+		logModifyConflict("file", fileId, attempt)
+		if cache := api.connInfo().api.Cache; cache != nil {
+			cache.InvalidateFile(fileId)
+		}
+	}
+	return nil, err
+}
+
+// ModifyFolder updates the folder with optimistic concurrency control.
+//
+// The current folder is fetched and passed to mutate, which changes it with SetXxx methods (e.g. SetName).
+// The changes are sent with the etag of the fetched folder as "If-Match" header.
+// If the folder has been changed in the meantime (412 Precondition Failed),
+// the folder is fetched and mutate is applied again, up to maxAttempts times.
+// If mutate returns an error, the modification is aborted and the error is returned.
+// If mutate changes nothing, the fetched folder is returned without an update.
+func ModifyFolder(api Connection, folderId string, maxAttempts int, mutate func(folder *Folder) error) (*Folder, error) {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultModifyAttempts
+	}
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var folder *Folder
+		folder, err = NewFolder(api).GetInfo(folderId, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err = mutate(folder); err != nil {
+			return nil, err
+		}
+		if folder.changedFlag == 0 {
+			return folder, nil
+		}
+		var updated *Folder
+		updated, err = folder.Update(folderId, toString(folder.ETag), nil)
+		if err == nil {
+			return updated, nil
+		}
+		if !xerrors.Is(err, ErrPreconditionFailed) {
+			return nil, err
+		}
+		logModifyConflict("folder", folderId, attempt)
+		if cache := api.connInfo().api.Cache; cache != nil {
+			cache.InvalidateFolder(folderId)
+		}
+	}
+	return nil, err
+}
+
+func logModifyConflict(itemType string, id string, attempt int) {
+	if Log != nil {
+		Log.Debugf("[goboxer] The %s %s has been changed, retrying (attempt %d)\n", itemType, id, attempt)
+	}
+	logDebug("goboxer modify conflict", "type", itemType, "id", id, "attempt", attempt)
+}
@@ -0,0 +1,118 @@
+package goboxer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+// etagServer serves a file and a folder whose etag is incremented by every update,
+// and changes them concurrently (conflicts) times before accepting an update.
+func etagServer(conflicts int) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var requests []string
+	etag := 0
+	name := "original"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.Header.Get("If-Match"))
+		itemType := strings.TrimSuffix(strings.Split(r.URL.Path, "/")[2], "s")
+		w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+		if r.Method == http.MethodPut {
+			if conflicts > 0 {
+				conflicts--
+				etag++
+			}
+			if r.Header.Get("If-Match") != fmt.Sprint(etag) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte(`{"type":"error","status":412,"code":"precondition_failed"}`))
+				return
+			}
+			var body struct {
+				Name string `json:"name"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			name = body.Name
+			etag++
+		}
+		_, _ = fmt.Fprintf(w, `{"type":"%s","id":"10","etag":"%d","name":"%s","item_collection":{"total_count":0,"entries":[]}}`, itemType, etag, name)
+	}))
+	return ts, &requests
+}
+
+func TestModifyFile(t *testing.T) {
+	ts, requests := etagServer(2)
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	calls := 0
+	file, err := ModifyFile(apiConn, "10", 3, func(file *File) error {
+		calls++
+		file.SetName(*file.Name + "+")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyFile() error = %v", err)
+	}
+	// mutate is applied to the latest file only
+	if *file.Name != "original+" || *file.ETag != "3" || calls != 3 {
+		t.Errorf("ModifyFile() = %s (etag %s), mutate called %d times", *file.Name, *file.ETag, calls)
+	}
+	if got, want := strings.Join(*requests, ","), "GET ,PUT 0,GET ,PUT 1,GET ,PUT 2"; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// gives up after maxAttempts
+	ts2, _ := etagServer(10)
+	defer ts2.Close()
+	_, err = ModifyFile(commonInit(ts2.URL), "10", 2, func(file *File) error {
+		file.SetName("x")
+		return nil
+	})
+	if !xerrors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("ModifyFile() error = %v, want ErrPreconditionFailed", err)
+	}
+
+	// aborted by mutate
+	abort := xerrors.New("abort")
+	if _, err := ModifyFile(apiConn, "10", 3, func(file *File) error { return abort }); err != abort {
+		t.Errorf("ModifyFile() error = %v, want %v", err, abort)
+	}
+}
+
+func TestModifyFolder(t *testing.T) {
+	ts, requests := etagServer(1)
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+	apiConn.Cache = NewResponseCache(NewLRUCache(10), 0)
+
+	folder, err := ModifyFolder(apiConn, "10", 0, func(folder *Folder) error {
+		folder.SetName("renamed")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyFolder() error = %v", err)
+	}
+	if *folder.Name != "renamed" || *folder.ETag != "2" {
+		t.Errorf("ModifyFolder() = %s (etag %s)", *folder.Name, *folder.ETag)
+	}
+	if got, want := strings.Join(*requests, ","), "GET ,PUT 0,GET ,PUT 1"; got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// nothing changed, nothing updated
+	*requests = nil
+	if _, err := ModifyFolder(apiConn, "10", 0, func(folder *Folder) error { return nil }); err != nil {
+		t.Errorf("ModifyFolder() error = %v", err)
+	}
+	if got := strings.Join(*requests, ","); got != "GET " {
+		t.Errorf("requests = %s, want GET only", got)
+	}
+}
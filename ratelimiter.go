@@ -0,0 +1,82 @@
+package goboxer
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of the HTTP requests made through a connection (APIConn.RateLimiter).
+//
+// Wait blocks until a request is allowed, or returns an error if ctx is done.
+// *rate.Limiter of golang.org/x/time/rate satisfies this interface.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// NewRateLimiter returns the token bucket RateLimiter which allows requestsPerSecond requests per second on average,
+// and bursts of up to burst requests. If requestsPerSecond is 0 or less, requests are not limited.
+func NewRateLimiter(requestsPerSecond float64, burst int) RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	var interval time.Duration
+	if requestsPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return &tokenBucket{interval: interval, burst: burst}
+}
+
+// tokenBucket is the token bucket implemented as GCRA (generic cell rate algorithm).
+type tokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	// tat is the theoretical arrival time of the next request.
+	tat time.Time
+}
+
+func (tb *tokenBucket) Wait(ctx context.Context) error {
+	if tb.interval == 0 {
+		return ctx.Err()
+	}
+	tb.mu.Lock()
+	now := time.Now()
+	tat := tb.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	tb.tat = tat.Add(tb.interval)
+	delay := tb.tat.Add(-time.Duration(tb.burst) * tb.interval).Sub(now)
+	tb.mu.Unlock()
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give back the reserved token
+		tb.mu.Lock()
+		tb.tat = tb.tat.Add(-tb.interval)
+		tb.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// rateLimitedTransport waits for the limiter before every request (including retries).
+type rateLimitedTransport struct {
+	limiter RateLimiter
+	base    http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
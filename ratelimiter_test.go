@@ -0,0 +1,69 @@
+package goboxer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(50, 5)
+	begin := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(begin); elapsed > 15*time.Millisecond {
+		t.Errorf("burst is limited: %v", elapsed)
+	}
+	for i := 0; i < 5; i++ {
+		_ = limiter.Wait(context.Background())
+	}
+	if elapsed := time.Since(begin); elapsed < 90*time.Millisecond {
+		t.Errorf("5 requests after the burst took %v, want 100ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewRateLimiter(0.1, 1).Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait() error = %v, want context.Canceled", err)
+	}
+}
+
+type countingLimiter struct {
+	count int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.count, 1)
+	return nil
+}
+
+func TestAPIConn_RateLimiter(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first request is retried
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set(HttpHeaderRetryAfter, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+		_, _ = w.Write([]byte(`{"type":"user","id":"1"}`))
+	}))
+	defer ts.Close()
+
+	apiConn := commonInit(ts.URL)
+	limiter := &countingLimiter{}
+	apiConn.RateLimiter = limiter
+	if _, err := NewUser(apiConn).GetCurrentUser(nil); err != nil {
+		t.Fatalf("GetCurrentUser() error = %v", err)
+	}
+	if limiter.count != 2 || requests != 2 {
+		t.Errorf("Wait() is called %d times for %d requests", limiter.count, requests)
+	}
+}
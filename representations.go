@@ -0,0 +1,328 @@
+package goboxer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultMaxPollingAttempts = 10
+	// maxPollingWaitSeconds bounds the wait between the polling requests.
+	maxPollingWaitSeconds = 30
+)
+
+type ThumbnailExtension string
+
+const (
+	ThumbnailPng ThumbnailExtension = "png"
+	ThumbnailJpg ThumbnailExtension = "jpg"
+)
+
+// Get Thumbnail
+//
+// Retrieves a thumbnail, or smaller image representation, of this file.
+// Sizes of 32x32, 64x64, 128x128, and 256x256 can be returned in the .png format
+// and sizes of 32x32, 94x94, 160x160, and 320x320 can be returned in the .jpg format.
+// minWidth and minHeight are omitted if 0.
+// https://developer.box.com/reference#get-a-thumbnail-for-a-file
+func (f *File) ThumbnailReq(fileId string, extension ThumbnailExtension, minWidth int, minHeight int) *Request {
+	var urlBase string
+	var params []string
+	urlBase = fmt.Sprintf("%s%s%s%s%s", f.apiInfo.api.BaseURL, "files/", fileId, "/thumbnail.", extension)
+	if minWidth > 0 {
+		params = append(params, fmt.Sprintf("min_width=%d", minWidth))
+	}
+	if minHeight > 0 {
+		params = append(params, fmt.Sprintf("min_height=%d", minHeight))
+	}
+	if len(params) > 0 {
+		urlBase += "?" + strings.Join(params, "&")
+	}
+	return f.apiInfo.newRequest(urlBase, GET, nil, nil)
+}
+
+// Get Thumbnail
+//
+// Retrieves a thumbnail, or smaller image representation, of this file.
+// If the thumbnail is not generated yet (202 Accepted), the request is retried after Retry-After seconds
+// up to APIConn.MaxPollingAttempts times.
+// If the thumbnail can not be generated, a placeholder image is returned.
+// https://developer.box.com/reference#get-a-thumbnail-for-a-file
+func (f *File) Thumbnail(fileId string, extension ThumbnailExtension, minWidth int, minHeight int) (*Response, error) {
+	return f.ThumbnailContext(context.Background(), fileId, extension, minWidth, minHeight)
+}
+
+// ThumbnailContext gets the thumbnail with the context. See Thumbnail.
+// When ctx is done, the request in flight and the wait for the generation are aborted.
+func (f *File) ThumbnailContext(ctx context.Context, fileId string, extension ThumbnailExtension, minWidth int, minHeight int) (*Response, error) {
+	for attempt := 1; ; attempt++ {
+		req := f.ThumbnailReq(fileId, extension, minWidth, minHeight)
+		resp, err := req.SendContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.ResponseCode {
+		case http.StatusOK:
+			return resp, nil
+		case http.StatusAccepted:
+			if attempt >= f.apiInfo.api.maxPollingAttempts() {
+				err = xerrors.Errorf("thumbnail of the file %s is not generated in %d attempts: %w", fileId, attempt, ErrNotReady)
+				return nil, newApiOtherError(err, "")
+			}
+			if err = waitForGeneration(ctx, resp, attempt); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, newApiStatusError(resp)
+		}
+	}
+}
+
+// Representation hints for GetRepresentations. (X-Rep-Hints header)
+// Multiple hints can be concatenated, e.g. RepHintPdf + RepHintExtractedText.
+// See https://developer.box.com/guides/representations/
+const (
+	RepHintPdf            = "[pdf]"
+	RepHintPng1024        = "[png?dimensions=1024x1024]"
+	RepHintPng2048        = "[png?dimensions=2048x2048]"
+	RepHintJpg320         = "[jpg?dimensions=320x320]"
+	RepHintExtractedText  = "[extracted_text]"
+	RepHintMultiPageImage = "[png?dimensions=2048x2048,paged=true]"
+)
+
+type RepresentationState string
+
+const (
+	// RepresentationStateSuccess means the representation is generated and can be downloaded.
+	RepresentationStateSuccess RepresentationState = "success"
+	// RepresentationStateViewable means the representation is partially generated (e.g. the first pages of a paged representation).
+	RepresentationStateViewable RepresentationState = "viewable"
+	// RepresentationStatePending means the representation is being generated.
+	RepresentationStatePending RepresentationState = "pending"
+	// RepresentationStateNone means the generation has not been started. Requesting Info.URL starts it.
+	RepresentationStateNone RepresentationState = "none"
+	// RepresentationStateError means the generation failed, e.g. the file is corrupted or not supported.
+	RepresentationStateError RepresentationState = "error"
+)
+
+type Representation struct {
+	Representation string            `json:"representation,omitempty"`
+	Properties     map[string]string `json:"properties,omitempty"`
+	Info           *struct {
+		URL string `json:"url,omitempty"`
+	} `json:"info,omitempty"`
+	Status *struct {
+		State RepresentationState `json:"state,omitempty"`
+	} `json:"status,omitempty"`
+	Content *struct {
+		URLTemplate string `json:"url_template,omitempty"`
+	} `json:"content,omitempty"`
+}
+
+// State returns the state of the generation of the representation.
+func (r *Representation) State() RepresentationState {
+	if r == nil || r.Status == nil {
+		return RepresentationStateNone
+	}
+	return r.Status.State
+}
+
+// IsReady reports whether the representation can be downloaded.
+func (r *Representation) IsReady() bool {
+	return r.State() == RepresentationStateSuccess || r.State() == RepresentationStateViewable
+}
+
+// Get Representations
+//
+// Retrieves the representations of the file matching repHints (e.g. RepHintPdf + RepHintExtractedText).
+// https://developer.box.com/reference#get-representations
+func (f *File) GetRepresentationsReq(fileId string, repHints string) *Request {
+	var urlBase string
+	urlBase = fmt.Sprintf("%s%s%s?%s", f.apiInfo.api.BaseURL, "files/", fileId, BuildFieldsQueryParams([]string{"representations"}))
+	var headers http.Header
+	if repHints != "" {
+		headers = http.Header{}
+		headers.Set("X-Rep-Hints", repHints)
+	}
+	return f.apiInfo.newRequest(urlBase, GET, headers, nil)
+}
+
+// Get Representations
+//
+// Retrieves the representations of the file matching repHints (e.g. RepHintPdf + RepHintExtractedText).
+// The representations may not be generated yet. Use WaitRepresentation to wait for the generation.
+// https://developer.box.com/reference#get-representations
+func (f *File) GetRepresentations(fileId string, repHints string) ([]*Representation, error) {
+	return f.getRepresentations(context.Background(), fileId, repHints)
+}
+
+func (f *File) getRepresentations(ctx context.Context, fileId string, repHints string) ([]*Representation, error) {
+	req := f.GetRepresentationsReq(fileId, repHints)
+	resp, err := req.SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	file := struct {
+		Representations struct {
+			Entries []*Representation `json:"entries"`
+		} `json:"representations"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &file)
+	if err != nil {
+		return nil, err
+	}
+	return file.Representations.Entries, nil
+}
+
+// WaitRepresentation requests the info url of the representation until it is generated (success or viewable),
+// up to APIConn.MaxPollingAttempts times, and returns the generated representation.
+// Requesting the info url also starts the generation of the representation whose state is none.
+// ErrRepresentationFailed is returned as soon as the state is error.
+func (f *File) WaitRepresentation(rep *Representation) (*Representation, error) {
+	return f.WaitRepresentationContext(context.Background(), rep)
+}
+
+// WaitRepresentationContext waits for the generation of the representation with the context. See WaitRepresentation.
+// When ctx is done, the request in flight and the wait for the generation are aborted.
+func (f *File) WaitRepresentationContext(ctx context.Context, rep *Representation) (*Representation, error) {
+	if rep.IsReady() {
+		return rep, nil
+	}
+	if rep.State() == RepresentationStateError {
+		return nil, representationFailed(rep)
+	}
+	if rep.Info == nil || rep.Info.URL == "" {
+		return nil, newApiOtherError(xerrors.Errorf("representation %s has no info url", rep.Representation), "")
+	}
+	for attempt := 1; ; attempt++ {
+		req := f.apiInfo.newRequest(rep.Info.URL, GET, nil, nil)
+		resp, err := req.SendContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if resp.ResponseCode != http.StatusOK {
+			return nil, newApiStatusError(resp)
+		}
+		polled := &Representation{}
+		err = UnmarshalJSONWrapper(resp.Body, polled)
+		if err != nil {
+			return nil, err
+		}
+		if polled.IsReady() {
+			return polled, nil
+		}
+		if polled.State() == RepresentationStateError {
+			return nil, representationFailed(polled)
+		}
+		if attempt >= f.apiInfo.api.maxPollingAttempts() {
+			err = xerrors.Errorf("representation %s is %s after %d attempts: %w", rep.Representation, polled.State(), attempt, ErrNotReady)
+			return nil, newApiOtherError(err, "")
+		}
+		if err = waitForGeneration(ctx, resp, attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// DownloadRepresentation downloads the asset of the representation, waiting for the generation if needed.
+//
+// assetPath is the asset in the representation, e.g. "1.png" for the first page of a paged png representation.
+// It is empty for a single file representation, e.g. pdf or extracted_text.
+func (f *File) DownloadRepresentation(rep *Representation, assetPath string) (*Response, error) {
+	return f.DownloadRepresentationContext(context.Background(), rep, assetPath)
+}
+
+// DownloadRepresentationContext downloads the asset of the representation with the context. See DownloadRepresentation.
+// When ctx is done, the request in flight and the wait for the generation are aborted.
+func (f *File) DownloadRepresentationContext(ctx context.Context, rep *Representation, assetPath string) (*Response, error) {
+	rep, err := f.WaitRepresentationContext(ctx, rep)
+	if err != nil {
+		return nil, err
+	}
+	if rep.Content == nil || rep.Content.URLTemplate == "" {
+		return nil, newApiOtherError(xerrors.Errorf("representation %s has no content url", rep.Representation), "")
+	}
+	contentUrl := strings.Replace(rep.Content.URLTemplate, "{+asset_path}", assetPath, 1)
+	for attempt := 1; ; attempt++ {
+		req := f.apiInfo.newRequest(contentUrl, GET, nil, nil)
+		resp, err := req.SendContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.ResponseCode {
+		case http.StatusOK:
+			return resp, nil
+		case http.StatusAccepted:
+			// the asset of a viewable representation may not be generated yet
+			if attempt >= f.apiInfo.api.maxPollingAttempts() {
+				err = xerrors.Errorf("asset %s of representation %s is not generated in %d attempts: %w", assetPath, rep.Representation, attempt, ErrNotReady)
+				return nil, newApiOtherError(err, "")
+			}
+			if err = waitForGeneration(ctx, resp, attempt); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, newApiStatusError(resp)
+		}
+	}
+}
+
+// Representation gets the first representation of the file matching repHints, waits for the generation
+// and downloads its asset. See DownloadRepresentation for assetPath.
+func (f *File) Representation(fileId string, repHints string, assetPath string) (*Response, error) {
+	return f.RepresentationContext(context.Background(), fileId, repHints, assetPath)
+}
+
+// RepresentationContext gets the representation of the file with the context. See Representation.
+// When ctx is done, the request in flight and the wait for the generation are aborted.
+func (f *File) RepresentationContext(ctx context.Context, fileId string, repHints string, assetPath string) (*Response, error) {
+	reps, err := f.getRepresentations(ctx, fileId, repHints)
+	if err != nil {
+		return nil, err
+	}
+	if len(reps) == 0 {
+		err = xerrors.Errorf("no representation of the file %s matches %s: %w", fileId, repHints, ErrNotFound)
+		return nil, newApiOtherError(err, "")
+	}
+	return f.DownloadRepresentationContext(ctx, reps[0], assetPath)
+}
+
+func representationFailed(rep *Representation) error {
+	err := xerrors.Errorf("generation of representation %s failed: %w", rep.Representation, ErrRepresentationFailed)
+	return newApiOtherError(err, "")
+}
+
+func (ac *APIConn) maxPollingAttempts() int {
+	if ac.MaxPollingAttempts > 0 {
+		return ac.MaxPollingAttempts
+	}
+	return defaultMaxPollingAttempts
+}
+
+// waitForGeneration waits before the next polling request, or returns the error when ctx is done.
+func waitForGeneration(ctx context.Context, resp *Response, attempt int) error {
+	wait := retryAfterSeconds(resp.Headers, attempt)
+	if wait > maxPollingWaitSeconds {
+		wait = maxPollingWaitSeconds
+	}
+	if Log != nil {
+		Log.Debugf("[goboxer] Not generated yet, retrying after %d seconds (attempt %d)\n", wait, attempt)
+	}
+	logDebug("goboxer waiting for generation", "url", resp.Request.Url, "retry_after", wait, "attempt", attempt)
+	if err := sleepContext(ctx, time.Duration(wait)*time.Second); err != nil {
+		return newApiOtherError(xerrors.Errorf("waiting for generation is aborted: %w", err), "")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package goboxer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+func TestFile_Thumbnail(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.URL.Path]++
+		n := attempts[r.URL.Path]
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/2.0/files/10001/thumbnail.png" && r.URL.RawQuery == "min_width=256&min_height=256" && n > 2:
+			w.Header().Set(httpHeaderContentType, "image/png")
+			_, _ = w.Write([]byte("PNG"))
+		case r.URL.Path == "/2.0/files/404/thumbnail.png":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/2.0/files/10003/thumbnail.png":
+			w.Header().Set(HttpHeaderRetryAfter, "30")
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.Header().Set(HttpHeaderRetryAfter, "0")
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	apiConn.MaxPollingAttempts = 3
+
+	resp, err := NewFile(apiConn).Thumbnail("10001", ThumbnailPng, 256, 256)
+	if err != nil || string(resp.Body) != "PNG" || attempts["/2.0/files/10001/thumbnail.png"] != 3 {
+		t.Errorf("Thumbnail() = %v, %v after %d attempts", resp, err, attempts["/2.0/files/10001/thumbnail.png"])
+	}
+	if _, err := NewFile(apiConn).Thumbnail("10002", ThumbnailJpg, 0, 0); !xerrors.Is(err, ErrNotReady) {
+		t.Errorf("Thumbnail() error = %v, want ErrNotReady", err)
+	}
+	if _, err := NewFile(apiConn).Thumbnail("404", ThumbnailPng, 0, 0); !xerrors.Is(err, ErrNotFound) {
+		t.Errorf("Thumbnail() error = %v, want ErrNotFound", err)
+	}
+	// the wait for the generation is aborted by ctx
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, cancel)
+	begin := time.Now()
+	if _, err := NewFile(apiConn).ThumbnailContext(ctx, "10003", ThumbnailPng, 0, 0); !xerrors.Is(err, context.Canceled) {
+		t.Errorf("ThumbnailContext() error = %v, want context.Canceled", err)
+	}
+	if time.Since(begin) > 5*time.Second {
+		t.Errorf("the wait for the generation is not aborted")
+	}
+	if got := NewFile(apiConn).ThumbnailReq("1", ThumbnailJpg, 0, 94).Url; got != ts.URL+"/2.0/files/1/thumbnail.jpg?min_height=94" {
+		t.Errorf("ThumbnailReq() url = %s", got)
+	}
+}
+
+func TestFile_Representation(t *testing.T) {
+	var mu sync.Mutex
+	polled := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+		switch r.URL.Path {
+		case "/2.0/files/10001":
+			if r.URL.Query().Get("fields") != "representations" || r.Header.Get("X-Rep-Hints") != RepHintPdf+RepHintExtractedText {
+				t.Errorf("unexpected request: %s %v", r.URL, r.Header)
+			}
+			_, _ = fmt.Fprintf(w, `{"type":"file","id":"10001","representations":{"entries":[
+{"representation":"pdf","properties":{},"info":{"url":"%[1]s/info/pdf"},"status":{"state":"none"},"content":{"url_template":"%[1]s/content/pdf/{+asset_path}"}},
+{"representation":"extracted_text","properties":{},"info":{"url":"%[1]s/info/text"},"status":{"state":"success"},"content":{"url_template":"%[1]s/content/text/{+asset_path}"}}
+]}}`, ts.URL)
+		case "/info/pdf":
+			mu.Lock()
+			polled++
+			state := "pending"
+			if polled > 1 {
+				state = "success"
+			}
+			mu.Unlock()
+			w.Header().Set(HttpHeaderRetryAfter, "0")
+			_, _ = fmt.Fprintf(w, `{"representation":"pdf","info":{"url":"%[1]s/info/pdf"},"status":{"state":"%[2]s"},"content":{"url_template":"%[1]s/content/pdf/{+asset_path}"}}`, ts.URL, state)
+		case "/info/broken":
+			_, _ = fmt.Fprintf(w, `{"representation":"pdf","info":{"url":"%[1]s/info/broken"},"status":{"state":"error"}}`, ts.URL)
+		case "/content/pdf/":
+			w.Header().Set(httpHeaderContentType, "application/pdf")
+			_, _ = w.Write([]byte("PDF"))
+		case "/content/text/":
+			w.Header().Set(httpHeaderContentType, "text/plain")
+			_, _ = w.Write([]byte("TEXT"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	resp, err := NewFile(apiConn).Representation("10001", RepHintPdf+RepHintExtractedText, "")
+	if err != nil || string(resp.Body) != "PDF" || polled != 2 {
+		t.Fatalf("Representation() = %v, %v, polled %d times", resp, err, polled)
+	}
+
+	reps, err := NewFile(apiConn).GetRepresentations("10001", RepHintPdf+RepHintExtractedText)
+	if err != nil || len(reps) != 2 || reps[0].State() != RepresentationStateNone || !reps[1].IsReady() {
+		t.Fatalf("GetRepresentations() = %v, %v", reps, err)
+	}
+	// ready representation is downloaded without polling
+	resp, err = NewFile(apiConn).DownloadRepresentation(reps[1], "")
+	if err != nil || string(resp.Body) != "TEXT" {
+		t.Errorf("DownloadRepresentation() = %v, %v", resp, err)
+	}
+
+	// failed generation is returned without waiting for the remaining attempts
+	broken := &Representation{Representation: "pdf"}
+	_ = json.Unmarshal([]byte(fmt.Sprintf(`{"representation":"pdf","info":{"url":"%s/info/broken"},"status":{"state":"pending"}}`, ts.URL)), broken)
+	if _, err := NewFile(apiConn).WaitRepresentation(broken); !xerrors.Is(err, ErrRepresentationFailed) {
+		t.Errorf("WaitRepresentation() error = %v, want ErrRepresentationFailed", err)
+	}
+	broken.Status.State = RepresentationStateError
+	if _, err := NewFile(apiConn).DownloadRepresentation(broken, ""); !xerrors.Is(err, ErrRepresentationFailed) {
+		t.Errorf("DownloadRepresentation() error = %v, want ErrRepresentationFailed", err)
+	}
+}
@@ -2,6 +2,7 @@ package goboxer
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,8 +11,10 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -62,12 +65,33 @@ var client = &http.Client{
 	Transport: transport,
 }
 
+// httpClient returns the client for the requests made through this connection.
+func (ac *APIConn) httpClient() *http.Client {
+	if ac == nil || (ac.Transport == nil && ac.RateLimiter == nil && ac.CircuitBreaker == nil) {
+		return client
+	}
+	var rt http.RoundTripper = transport
+	if ac.Transport != nil {
+		rt = ac.Transport
+	}
+	if ac.RateLimiter != nil {
+		rt = &rateLimitedTransport{limiter: ac.RateLimiter, base: rt}
+	}
+	// requests fail fast without waiting for the rate limiter while the circuit is open
+	if ac.CircuitBreaker != nil {
+		rt = &circuitBreakerTransport{breaker: ac.CircuitBreaker, base: rt}
+	}
+	return &http.Client{Transport: rt}
+}
+
 const (
 	httpHeaderAuthorization = "Authorization"
 	httpHeaderUserAgent     = "User-Agent"
 	httpHeaderContentType   = "Content-Type"
 	httpAuthType            = "Bearer"
 	httpHeaderAsUser        = "As-User"
+	httpHeaderNotifications = "Box-Notifications"
+	httpHeaderIfMatch       = "If-Match"
 	HttpHeaderRetryAfter    = "Retry-After"
 )
 
@@ -90,6 +114,47 @@ func (req *Request) AsUser(userId string) *Request {
 	return req
 }
 
+// Suppress email notifications (e.g. for new collaborations, uploads, moves and deletes) of this request.
+//
+// To suppress notifications of all requests, use APIConn.SuppressNotifications instead.
+// This functionality required "Suppress notifications" permission.
+// See https://developer.box.com/reference#suppressing-notifications
+func (req *Request) SuppressNotifications() *Request {
+	if req.headers == nil {
+		req.headers = http.Header{}
+	}
+	req.headers.Set(httpHeaderNotifications, "off")
+	return req
+}
+
+// Set "If-Match" header of this request, to make the request fail with 412 Precondition Failed
+// if the etag of the item has been changed. Empty etag is ignored.
+//
+// See https://developer.box.com/reference#etags
+func (req *Request) IfMatch(etag string) *Request {
+	if etag == "" {
+		return req
+	}
+	if req.headers == nil {
+		req.headers = http.Header{}
+	}
+	req.headers.Set(httpHeaderIfMatch, etag)
+	return req
+}
+
+// effectiveHeaders returns the headers of this request with the connection wide settings applied.
+func (req *Request) effectiveHeaders() http.Header {
+	if req.apiConn == nil || !req.apiConn.SuppressNotifications || req.headers.Get(httpHeaderNotifications) != "" {
+		return req.headers
+	}
+	h := make(http.Header, len(req.headers)+1)
+	for k, v := range req.headers {
+		h[k] = v
+	}
+	h.Set(httpHeaderNotifications, "off")
+	return h
+}
+
 func NewRequest(apiConn *APIConn, url string, method Method, headers http.Header, body io.Reader) *Request {
 	h := make(http.Header, len(headers))
 	for k, v := range headers {
@@ -110,6 +175,12 @@ func NewRequest(apiConn *APIConn, url string, method Method, headers http.Header
 }
 
 func (req *Request) Send() (*Response, error) {
+	return req.SendContext(context.Background())
+}
+
+// SendContext sends the request with the context.
+// When ctx is done, the request in flight and the wait for its retry are aborted.
+func (req *Request) SendContext(ctx context.Context) (*Response, error) {
 	var (
 		resp   *http.Response
 		err    error
@@ -124,7 +195,7 @@ func (req *Request) Send() (*Response, error) {
 	url = req.Url
 	method = convertMethodStr(req.Method)
 
-	newRequest, err := http.NewRequest(method, url, req.body)
+	newRequest, err := http.NewRequestWithContext(ctx, method, url, req.body)
 	if err != nil {
 		err = xerrors.Errorf("failed to create request: %w", err)
 		return nil, newApiOtherError(err, "")
@@ -140,7 +211,7 @@ func (req *Request) Send() (*Response, error) {
 	}
 
 	newRequest.Header.Add(httpHeaderUserAgent, req.apiConn.UserAgent)
-	for key, values := range req.headers {
+	for key, values := range req.effectiveHeaders() {
 		if key != httpHeaderUserAgent && key != httpHeaderAuthorization {
 			for _, v := range values {
 				newRequest.Header.Add(key, v)
@@ -160,7 +231,11 @@ func (req *Request) Send() (*Response, error) {
 
 	logRequest(method, newRequest)
 
-	resp, rttInMillis, err := send(newRequest)
+	var parent string
+	if req.apiConn.Cache != nil {
+		parent = req.apiConn.Cache.parentOf(req)
+	}
+	resp, rttInMillis, err := send(req.apiConn.httpClient(), newRequest)
 	if err != nil {
 		err = xerrors.Errorf("failed to send request: %w", err)
 		return nil, newApiOtherError(err, "")
@@ -189,59 +264,92 @@ func (req *Request) Send() (*Response, error) {
 		ContentType:  resp.Header.Get(httpHeaderContentType),
 		RTTInMillis:  rttInMillis,
 	}
+	if req.apiConn.Cache != nil {
+		req.apiConn.Cache.invalidateMutated(req, result, parent)
+	}
 
 	return result, nil
 }
 
 func logRequest(method string, request *http.Request) {
-	if Log == nil {
+	if Log == nil && StructuredLog == nil {
 		return
 	}
-	builder := strings.Builder{}
-	builder.WriteString(fmt.Sprintf("---\nRequest URL: %s %s\n", method, request.URL))
-	builder.WriteString("RequestHeader:\n")
-	for key, value := range request.Header {
-		builder.WriteString(fmt.Sprintf("\t  %s: %v\n", key, value))
+	headers := redactHeader(request.Header)
+	contentType := mediaType(request.Header.Get(httpHeaderContentType))
+
+	var reqBody []byte
+	logPrintfBody := Log != nil && Log.EnabledLoggingRequestBody()
+	logStructuredBody, _ := structuredBodyLoggingPolicy()
+	if logPrintfBody || logStructuredBody {
+		switch contentType {
+		case ContentTypeApplicationJson, ContentTypeApplicationJsonPatch, ContentTypeFormUrlEncoded:
+			if request.GetBody != nil {
+				if readCloser, _ := request.GetBody(); readCloser != nil {
+					reqBody, _ = ioutil.ReadAll(readCloser)
+					_ = readCloser.Close()
+				}
+			}
+		default:
+		}
 	}
-	switch request.Header.Get(httpHeaderContentType) {
-	case ContentTypeApplicationJson:
-		fallthrough
-	case ContentTypeFormUrlEncoded:
-		if readCloser, _ := request.GetBody(); readCloser != nil {
-			reqBody, _ := ioutil.ReadAll(readCloser)
-			builder.WriteString(fmt.Sprintf("RequestBody:\n%s\n", string(reqBody)))
+
+	if Log != nil {
+		builder := strings.Builder{}
+		builder.WriteString(fmt.Sprintf("---\nRequest URL: %s %s\n", method, redactURL(request.URL)))
+		builder.WriteString("RequestHeader:\n")
+		for key, value := range headers {
+			builder.WriteString(fmt.Sprintf("\t  %s: %v\n", key, value))
 		}
-	default:
+		if logPrintfBody && reqBody != nil {
+			builder.WriteString(fmt.Sprintf("RequestBody:\n%s\n", redactBody(contentType, reqBody)))
+		}
+		builder.WriteString("---\n")
+		Log.RequestDumpf("[goboxer] Request\n%s", builder.String())
+	}
+	if StructuredLog != nil {
+		kv := []interface{}{"method", method, "url", redactURL(request.URL), "headers", headers}
+		if logStructuredBody && reqBody != nil {
+			kv = append(kv, "body", redactBody(contentType, reqBody))
+		}
+		logDebug("goboxer request", kv...)
 	}
-	builder.WriteString("---\n")
-	Log.RequestDumpf("[goboxer] Request\n%s", builder.String())
 }
 
 func logResponse(resp *http.Response, respBodyBytes []byte, rttInMillis int64) {
-	if Log == nil {
+	if Log == nil && StructuredLog == nil {
 		return
 	}
-	builder := strings.Builder{}
-	builder.WriteString(fmt.Sprintf("---\nHTTP Status Code: %d\n", resp.StatusCode))
-	builder.WriteString("ResponseHeader:\n")
-	for key, value := range resp.Header {
-		builder.WriteString(fmt.Sprintf("\t  %s: %v\n", key, value))
-	}
-	builder.WriteString(fmt.Sprintf("Maybe Compressed response: %t\n", resp.ContentLength == -1 && resp.Uncompressed))
+	headers := redactHeader(resp.Header)
+	contentType := mediaType(resp.Header.Get(httpHeaderContentType))
+	maybeCompressed := resp.ContentLength == -1 && resp.Uncompressed
+
+	if Log != nil {
+		builder := strings.Builder{}
+		builder.WriteString(fmt.Sprintf("---\nHTTP Status Code: %d\n", resp.StatusCode))
+		builder.WriteString("ResponseHeader:\n")
+		for key, value := range headers {
+			builder.WriteString(fmt.Sprintf("\t  %s: %v\n", key, value))
+		}
+		builder.WriteString(fmt.Sprintf("Maybe Compressed response: %t\n", maybeCompressed))
 
-	if Log.EnabledLoggingResponseBody() {
-		switch resp.Header.Get(httpHeaderContentType) {
-		case ContentTypeApplicationJson:
-			builder.WriteString(fmt.Sprintf("ResponseBody:\n%s\n", string(respBodyBytes)))
-		default:
+		if Log.EnabledLoggingResponseBody() && contentType == ContentTypeApplicationJson {
+			builder.WriteString(fmt.Sprintf("ResponseBody:\n%s\n", redactBody(contentType, respBodyBytes)))
 		}
+		builder.WriteString("---\n")
+		Log.ResponseDumpf("[goboxer] Response\n%s", builder.String())
+		Log.Debugf("[goboxer] Request turn around time: %d [ms]\n", rttInMillis)
+	}
+	if StructuredLog != nil {
+		kv := []interface{}{"status", resp.StatusCode, "headers", headers, "maybe_compressed", maybeCompressed, "rtt_ms", rttInMillis}
+		if _, logStructuredBody := structuredBodyLoggingPolicy(); logStructuredBody && contentType == ContentTypeApplicationJson {
+			kv = append(kv, "body", redactBody(contentType, respBodyBytes))
+		}
+		logDebug("goboxer response", kv...)
 	}
-	builder.WriteString("---\n")
-	Log.ResponseDumpf("[goboxer] Response\n%s", builder.String())
-	Log.Debugf("[goboxer] Request turn around time: %d [ms]\n", rttInMillis)
 }
 
-func send(request *http.Request) (resp *http.Response, rttInMillis int64, err error) {
+func send(client *http.Client, request *http.Request) (resp *http.Response, rttInMillis int64, err error) {
 
 	bodyCloser := request.Body
 	defer func() {
@@ -259,14 +367,23 @@ func send(request *http.Request) (resp *http.Response, rttInMillis int64, err er
 			request.Body = bodyNoClose
 		}
 
+		attemptStart := time.Now()
 		resp, err = client.Do(request)
 		a := time.Now()
 		rttInMillis = (a.UnixNano() - b.UnixNano()) / 1000000
+		if Metrics != nil {
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			Metrics.RequestCompleted(request.Method, request.URL.Host, status, a.Sub(attemptStart))
+		}
 		if err != nil {
 			err = xerrors.Errorf("failed to request response: %w", err)
 			if Log != nil {
 				Log.Warnf("%v\n", err)
 			}
+			logWarn("goboxer request failed", "error", err)
 			return nil, rttInMillis, newApiOtherError(err, "")
 		}
 
@@ -277,40 +394,67 @@ func send(request *http.Request) (resp *http.Response, rttInMillis int64, err er
 			if Log != nil {
 				Log.Warnf("Retry count reached max count\n")
 			}
+			logWarn("goboxer retry count reached max count", "status", resp.StatusCode)
 			break
 		}
-		var retryAfter int
-		if resp.StatusCode == http.StatusTooManyRequests {
-			retryAfter, _ = strconv.Atoi(resp.Header.Get(HttpHeaderRetryAfter))
-		} else {
-			exponent := 5 - (retryCount - 1)
-			minWindow := 0.5
-			maxWindow := 1.5
-			rand.Seed(time.Now().Unix())
-			jitter := (rand.Float64() * (maxWindow - minWindow)) + minWindow
-
-			retryAfter = int(math.Pow(2, float64(exponent)) * jitter)
-		}
+		retryAfter := retryAfterSeconds(resp.Header, 5-(retryCount-1))
 		if Log != nil {
 			Log.Infof("Retry request...after %d secs.\n", retryAfter)
 		}
-		time.Sleep(time.Duration(retryAfter) * time.Second)
+		logInfo("goboxer retry request", "status", resp.StatusCode, "retry_after_sec", retryAfter)
+		if err = sleepContext(request.Context(), time.Duration(retryAfter)*time.Second); err != nil {
+			_ = resp.Body.Close()
+			return nil, rttInMillis, newApiOtherError(xerrors.Errorf("retry is aborted: %w", err), "")
+		}
 	}
 	return resp, rttInMillis, nil
 }
 
+// sleepContext waits for d, or returns ctx.Err() when ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func isResponseRetryable(responseCode int) bool {
 	return responseCode >= 500 || responseCode == http.StatusTooManyRequests
 }
 
+// retryAfterSeconds returns the seconds to wait before the attempt-th (1-origin) retry.
+// "Retry-After" header is honored if present, otherwise exponential backoff with jitter is used.
+func retryAfterSeconds(header http.Header, attempt int) int {
+	if retryAfter, err := strconv.Atoi(header.Get(HttpHeaderRetryAfter)); err == nil && retryAfter >= 0 {
+		return retryAfter
+	}
+	minWindow := 0.5
+	maxWindow := 1.5
+	rand.Seed(time.Now().Unix())
+	jitter := (rand.Float64() * (maxWindow - minWindow)) + minWindow
+
+	return int(math.Pow(2, float64(attempt)) * jitter)
+}
+
 type BatchRequest struct {
 	Request
+	pending []*pending
 }
 
-func NewBatchRequest(apiConn *APIConn) *BatchRequest {
-	return &BatchRequest{
-		Request{apiConn: apiConn, shouldAuthenticate: true},
+func NewBatchRequest(conn Connection) *BatchRequest {
+	info := conn.connInfo()
+	br := &BatchRequest{
+		Request: Request{apiConn: info.api, shouldAuthenticate: true},
 	}
+	if info.asUser != "" {
+		br.headers = http.Header{}
+		br.AsUser(info.asUser)
+	}
+	return br
 }
 
 type BatchResponse struct {
@@ -318,62 +462,162 @@ type BatchResponse struct {
 	Responses []*Response
 }
 
-func (req *Request) MarshalJSON() ([]byte, error) {
+// relativeUrl returns the url of this request relative to BaseURL (or BaseUploadURL), with the leading slash.
+func (req *Request) relativeUrl() (string, error) {
+	for _, base := range []string{req.apiConn.BaseURL, req.apiConn.BaseUploadURL} {
+		if base == "" {
+			continue
+		}
+		base = strings.TrimSuffix(base, "/")
+		if strings.HasPrefix(req.Url, base+"/") || strings.HasPrefix(req.Url, base+"?") {
+			return "/" + strings.TrimPrefix(req.Url[len(base):], "/"), nil
+		}
+	}
+	return "", xerrors.Errorf("url is not under BaseURL nor BaseUploadURL: %s", req.Url)
+}
 
-	method := convertMethodStr(req.Method)
-	baseUrlLen := len(req.apiConn.BaseURL)
-	relativeUrl := string(req.Url[baseUrlLen-1:])
-	relativeUrlBytes, err := json.Marshal(relativeUrl)
+// MarshalJSON encodes the request as a sub request of a batch request.
+// The body is buffered, so the request can be encoded (and sent) again.
+func (req *Request) MarshalJSON() ([]byte, error) {
+	relativeUrl, err := req.relativeUrl()
 	if err != nil {
 		return nil, err
 	}
-
-	var buf bytes.Buffer
-
-	buf.WriteString("{")
-
-	buf.WriteString(`"method":"`)
-	buf.WriteString(method)
-	buf.WriteString(`",`)
-
-	buf.WriteString(`"relative_url":`)
-	buf.Write(relativeUrlBytes)
+	subRequest := struct {
+		Method      string            `json:"method"`
+		RelativeUrl string            `json:"relative_url"`
+		Body        json.RawMessage   `json:"body,omitempty"`
+		Headers     map[string]string `json:"headers,omitempty"`
+	}{
+		Method:      convertMethodStr(req.Method),
+		RelativeUrl: relativeUrl,
+	}
 
 	if req.body != nil {
 		all, err := ioutil.ReadAll(req.body)
 		if err != nil {
 			return nil, err
 		}
-		buf.WriteString(`,`)
-		buf.WriteString(`"body":`)
-		buf.Write(all)
-	}
-
-	if req.headers != nil && len(req.headers) != 0 {
-		buf.WriteString(`,`)
-		buf.WriteString(`"headers":{`)
-
-		// FIXME json formatting...
-		for key, value := range req.headers {
-			buf.WriteString(`"` + key + `":"`)
-			for i, v := range value {
-				if i != 0 {
-					buf.WriteString(` `)
-				}
-				buf.WriteString(v)
+		req.body = bytes.NewReader(all)
+		if len(bytes.TrimSpace(all)) != 0 {
+			if !json.Valid(all) {
+				return nil, xerrors.Errorf("body of a batch sub request must be json: %s", req.Url)
 			}
-			buf.WriteString(`"`)
+			subRequest.Body = all
 		}
-		buf.WriteString(`}`)
 	}
 
-	buf.WriteString("}")
+	if headers := req.effectiveHeaders(); len(headers) != 0 {
+		subRequest.Headers = make(map[string]string, len(headers))
+		for key, values := range headers {
+			subRequest.Headers[key] = strings.Join(values, ", ")
+		}
+	}
 
-	return buf.Bytes(), nil
+	return json.Marshal(subRequest)
 }
 
+// maxBatchRequests is the maximum number of sub requests in a batch request.
+const maxBatchRequests = 20
+
 // Execute batch request
+//
+// Requests are split into batch requests of up to 20 sub requests, which is the maximum Box accepts.
+// Sub requests that returned 429 or 5xx are retried (honoring their "Retry-After") up to MaxRequestAttempts of the connection.
+// Responses are aligned to the order of requests.
+// If a batch request fails, the responses of the sub requests already executed are returned with the error,
+// and the responses of the sub requests not sent yet are nil.
+// https://developer.box.com/reference#batch-api
 func (req *BatchRequest) ExecuteBatch(requests []*Request) (*BatchResponse, error) {
+	return req.ExecuteBatchContext(context.Background(), requests)
+}
+
+// ExecuteBatchContext executes the batch request with the context. See ExecuteBatch.
+// When ctx is done, the batch request in flight and the wait for the retry are aborted.
+func (req *BatchRequest) ExecuteBatchContext(ctx context.Context, requests []*Request) (*BatchResponse, error) {
+	result, _, err := req.executeBatch(ctx, requests)
+	return result, err
+}
+
+// executeBatch executes the batch request. See ExecuteBatch.
+// When it fails, unsent is the index of the first sub request never sent to Box.
+// The sub requests before unsent without a response may have been executed by Box.
+func (req *BatchRequest) executeBatch(ctx context.Context, requests []*Request) (result *BatchResponse, unsent int, err error) {
+	result = &BatchResponse{Responses: []*Response{}}
+	if len(requests) == 0 {
+		return result, 0, nil
+	}
+	maxAttempts := req.apiConn.MaxRequestAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	responses := make([]*Response, len(requests))
+	result.Responses = responses
+	parents := make([]string, len(requests))
+	if req.apiConn.Cache != nil {
+		for i, r := range requests {
+			parents[i] = req.apiConn.Cache.parentOf(r)
+		}
+	}
+	for start := 0; start < len(requests); start += maxBatchRequests {
+		end := start + maxBatchRequests
+		if end > len(requests) {
+			end = len(requests)
+		}
+		pending := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			pending = append(pending, i)
+		}
+
+		for attempt := 1; ; attempt++ {
+			chunk := make([]*Request, len(pending))
+			for i, index := range pending {
+				chunk[i] = requests[index]
+			}
+			batchResp, sent, err := req.executeChunk(ctx, chunk)
+			if err != nil {
+				if sent {
+					return result, end, err
+				}
+				return result, start, err
+			}
+			result.Response = batchResp.Response
+
+			var retry []int
+			retryAfter := 0
+			for i, resp := range batchResp.Responses {
+				responses[pending[i]] = resp
+				if req.apiConn.Cache != nil {
+					req.apiConn.Cache.invalidateMutated(resp.Request, resp, parents[pending[i]])
+				}
+				if isResponseRetryable(resp.ResponseCode) {
+					retry = append(retry, pending[i])
+					if ra := retryAfterSeconds(resp.Headers, attempt); ra > retryAfter {
+						retryAfter = ra
+					}
+				}
+			}
+			if len(retry) == 0 || attempt >= maxAttempts {
+				break
+			}
+			if Log != nil {
+				Log.Infof("Retry %d sub requests...after %d secs.\n", len(retry), retryAfter)
+			}
+			logInfo("goboxer retry batch sub requests", "count", len(retry), "retry_after_sec", retryAfter)
+			if err := sleepContext(ctx, time.Duration(retryAfter)*time.Second); err != nil {
+				return result, end, newApiOtherError(xerrors.Errorf("retry is aborted: %w", err), "")
+			}
+			pending = retry
+		}
+	}
+	return result, len(requests), nil
+}
+
+// executeChunk sends the requests as a batch request.
+// sent reports whether the batch request may have reached Box, i.e. its headers were written.
+// If sent is false, none of the requests were executed.
+func (req *BatchRequest) executeChunk(ctx context.Context, requests []*Request) (result *BatchResponse, sent bool, err error) {
 	batchUrl := req.apiConn.BaseURL + "batch"
 
 	var buf bytes.Buffer
@@ -386,29 +630,33 @@ func (req *BatchRequest) ExecuteBatch(requests []*Request) (*BatchResponse, erro
 		batchReqJson, err := json.Marshal(&r)
 		if err != nil {
 			err = xerrors.Errorf("json marshaling error: %w", err)
-			return nil, newApiOtherError(err, "")
+			return nil, false, newApiOtherError(err, "")
 		}
 		buf.Write(batchReqJson)
 	}
 	buf.WriteString("]}")
 
-	newRequest, err := http.NewRequest("POST", batchUrl, bytes.NewReader(buf.Bytes()))
+	var wrote int32
+	trace := &httptrace.ClientTrace{
+		WroteHeaders: func() { atomic.StoreInt32(&wrote, 1) },
+	}
+	newRequest, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), "POST", batchUrl, bytes.NewReader(buf.Bytes()))
 	if err != nil {
 		err = xerrors.Errorf("failed to generate request: %w", err)
-		return nil, newApiOtherError(err, "")
+		return nil, false, newApiOtherError(err, "")
 	}
 	if req.shouldAuthenticate {
 		token, err := req.apiConn.lockAccessToken()
 		if err != nil {
 			err = xerrors.Errorf("failed to generate request: %w", err)
-			return nil, newApiOtherError(err, "")
+			return nil, false, newApiOtherError(err, "")
 		}
 		defer req.apiConn.unlockAccessToken()
 		newRequest.Header.Add(httpHeaderAuthorization, httpAuthType+" "+token)
 	}
 
 	newRequest.Header.Add(httpHeaderUserAgent, req.apiConn.UserAgent)
-	for key, values := range req.headers {
+	for key, values := range req.effectiveHeaders() {
 		for _, v := range values {
 			newRequest.Header.Add(key, v)
 		}
@@ -416,10 +664,10 @@ func (req *BatchRequest) ExecuteBatch(requests []*Request) (*BatchResponse, erro
 
 	logRequest("POST", newRequest)
 
-	resp, rttInMillis, err := send(newRequest)
+	resp, rttInMillis, err := send(req.apiConn.httpClient(), newRequest)
 	if err != nil {
 		err = xerrors.Errorf("failed to send request: %w", err)
-		return nil, newApiOtherError(err, "")
+		return nil, atomic.LoadInt32(&wrote) == 1, newApiOtherError(err, "")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -429,12 +677,15 @@ func (req *BatchRequest) ExecuteBatch(requests []*Request) (*BatchResponse, erro
 
 	logResponse(resp, respBodyBytes, rttInMillis)
 
-	var result *BatchResponse
-
 	var responses []*Response
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, newApiStatusError(respBodyBytes)
+		return nil, true, newApiStatusError(&Response{
+			ResponseCode: resp.StatusCode,
+			Headers:      resp.Header,
+			Body:         respBodyBytes,
+			Request:      &Request{apiConn: req.apiConn, Url: batchUrl, Method: POST},
+		})
 	}
 	var r struct {
 		Responses []struct {
@@ -445,9 +696,13 @@ func (req *BatchRequest) ExecuteBatch(requests []*Request) (*BatchResponse, erro
 	}
 	err = UnmarshalJSONWrapper(respBodyBytes, &r)
 	if err != nil {
-		return nil, err
+		return nil, true, err
 	}
 	rs := r.Responses
+	if len(rs) != len(requests) {
+		err = xerrors.Errorf("number of responses(%d) does not match number of requests(%d)", len(rs), len(requests))
+		return nil, true, newApiOtherError(err, "")
+	}
 	for i, v := range rs {
 		httpHeader := http.Header{}
 		for hi, hv := range v.Headers {
@@ -476,5 +731,5 @@ func (req *BatchRequest) ExecuteBatch(requests []*Request) (*BatchResponse, erro
 		},
 		Responses: responses,
 	}
-	return result, nil
+	return result, true, nil
 }
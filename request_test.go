@@ -2,17 +2,20 @@ package goboxer
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/xerrors"
 )
 
 var mainObj Main
@@ -259,6 +262,8 @@ func TestBatchRequest_ExecuteBatch(t *testing.T) {
 	defer ts.Close()
 
 	apiConn := commonInit(ts.URL)
+	// sub requests returned 429 are retried by ExecuteBatch, see TestBatchRequest_ExecuteBatch_Retry
+	apiConn.MaxRequestAttempts = 1
 
 	batchRequest := NewBatchRequest(apiConn)
 	baseURL := ts.URL
@@ -543,10 +548,10 @@ func TestRequest_RetryProcess(t *testing.T) {
 		errType interface{}
 	}{
 		{"retry by http status 429", args{"429", []string{"type"}},
-			normal, true, &ApiOtherError{},
+			normal, true, &ApiStatusError{Status: 429},
 		},
 		{"retry by http status 500", args{"500", []string{"id"}},
-			normal, true, &ApiOtherError{},
+			normal, true, &ApiStatusError{Status: 500},
 		},
 	}
 	for _, tt := range tests {
@@ -601,3 +606,250 @@ func TestRequest_RetryProcess(t *testing.T) {
 		})
 	}
 }
+
+func TestRequest_SuppressNotifications(t *testing.T) {
+	var got []string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			got = append(got, r.Header.Get(httpHeaderNotifications))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"type":"folder","id":"0"}`))
+		},
+	))
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		connection bool
+		request    bool
+		want       string
+	}{
+		{"default", false, false, ""},
+		{"per request", false, true, "off"},
+		{"per connection", true, false, "off"},
+		{"both", true, true, "off"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			apiConn := commonInit(ts.URL)
+			apiConn.SuppressNotifications = tt.connection
+
+			req := NewRequest(apiConn, ts.URL+"/folders/0", DELETE, nil, nil)
+			if tt.request {
+				req = req.SuppressNotifications()
+			}
+			if _, err := req.Send(); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("Box-Notifications = %v, want %q", got, tt.want)
+			}
+			if tt.connection && !tt.request && req.headers.Get(httpHeaderNotifications) != "" {
+				t.Errorf("connection setting must not modify the request headers")
+			}
+		})
+	}
+}
+
+func TestRequest_MarshalJSON_SuppressNotifications(t *testing.T) {
+	apiConn := commonInit("https://example.com")
+	apiConn.SuppressNotifications = true
+
+	req := NewRequest(apiConn, apiConn.BaseURL+"folders/0", DELETE, nil, nil)
+	b, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	if !strings.Contains(string(b), `"Box-Notifications":"off"`) {
+		t.Errorf("Box-Notifications is not included in batch sub request: %s", b)
+	}
+}
+
+func TestRequest_relativeUrl(t *testing.T) {
+	apiConn := NewAPIConnWithAccessToken("ACCESS_TOKEN").SetEndpoints(Endpoints{
+		BaseURL:       "https://proxy.example.com/box/2.0",
+		BaseUploadURL: "https://proxy.example.com/box-upload/api/2.0/",
+	})
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"base", apiConn.BaseURL + "folders/0?fields=id", "/folders/0?fields=id", false},
+		{"upload", apiConn.BaseUploadURL + "files/content", "/files/content", false},
+		{"prefix of other path", "https://proxy.example.com/box/2.0x/folders/0", "", true},
+		{"other host", "https://api.box.com/2.0/folders/0", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRequest(apiConn, tt.url, GET, nil, nil).relativeUrl()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("relativeUrl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("relativeUrl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequest_MarshalJSON(t *testing.T) {
+	apiConn := commonInit("https://example.com")
+
+	header := http.Header{}
+	header.Set("If-Match", `"1"`)
+	header.Add("X-Multi", "a")
+	header.Add("X-Multi", "b")
+	req := NewRequest(apiConn, apiConn.BaseURL+"folders/0?fields=id", PUT, header, strings.NewReader(`{"name": "a\"b"}`))
+
+	for i := 0; i < 2; i++ {
+		b, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("MarshalJSON() error = %v", err)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("MarshalJSON() returned invalid json: %v\n%s", err, b)
+		}
+		want := map[string]interface{}{
+			"method":       "PUT",
+			"relative_url": "/folders/0?fields=id",
+			"body":         map[string]interface{}{"name": `a"b`},
+			"headers":      map[string]interface{}{"If-Match": `"1"`, "X-Multi": "a, b"},
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("MarshalJSON() #%d differs: (-got +want)\n%s", i, diff)
+		}
+	}
+
+	req = NewRequest(apiConn, apiConn.BaseURL+"folders/0", POST, nil, strings.NewReader("name=a"))
+	if _, err := json.Marshal(req); err == nil {
+		t.Errorf("MarshalJSON() must fail for non json body")
+	}
+}
+
+func TestBatchRequest_ExecuteBatch_Retry(t *testing.T) {
+	var batchSizes []int
+	attempts := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Requests []struct {
+					Method      string `json:"method"`
+					RelativeURL string `json:"relative_url"`
+				} `json:"requests"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("invalid batch request: %v", err)
+			}
+			batchSizes = append(batchSizes, len(body.Requests))
+
+			var responses []map[string]interface{}
+			for _, sub := range body.Requests {
+				id := strings.TrimPrefix(sub.RelativeURL, "/files/")
+				attempts[id]++
+				n, _ := strconv.Atoi(id)
+				switch {
+				case n == 44:
+					// always fails
+					responses = append(responses, map[string]interface{}{"status": 503, "headers": map[string]interface{}{"Retry-After": 0}})
+				case n%7 == 0 && attempts[id] == 1:
+					responses = append(responses, map[string]interface{}{"status": 429, "headers": map[string]interface{}{"Retry-After": 0}})
+				default:
+					responses = append(responses, map[string]interface{}{"status": 200, "response": map[string]interface{}{"type": "file", "id": id}})
+				}
+			}
+			w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+		},
+	))
+	defer ts.Close()
+
+	apiConn := commonInit(ts.URL)
+	apiConn.MaxRequestAttempts = 3
+
+	var requests []*Request
+	for i := 0; i < 45; i++ {
+		requests = append(requests, NewFile(apiConn).GetFileInfoReq(strconv.Itoa(i), false, nil))
+	}
+	got, err := NewBatchRequest(apiConn).ExecuteBatch(requests)
+	if err != nil {
+		t.Fatalf("ExecuteBatch() error = %v", err)
+	}
+
+	// 0-19: 0,7,14 retried / 20-39: 21,28,35 retried / 40-44: 42 retried, 44 retried twice
+	if diff := cmp.Diff(batchSizes, []int{20, 3, 20, 3, 5, 2, 1}); diff != "" {
+		t.Errorf("batch sizes differ: (-got +want)\n%s", diff)
+	}
+	if len(got.Responses) != len(requests) {
+		t.Fatalf("len(Responses) = %d, want %d", len(got.Responses), len(requests))
+	}
+	for i, resp := range got.Responses {
+		if resp.Request != requests[i] {
+			t.Errorf("Responses[%d] is not aligned to the request", i)
+		}
+		if i == 44 {
+			if resp.ResponseCode != 503 || attempts["44"] != 3 {
+				t.Errorf("Responses[44] = %d after %d attempts, want 503 after 3 attempts", resp.ResponseCode, attempts["44"])
+			}
+			continue
+		}
+		if resp.ResponseCode != http.StatusOK || !strings.Contains(string(resp.Body), `"id":"`+strconv.Itoa(i)+`"`) {
+			t.Errorf("Responses[%d] = %d %s", i, resp.ResponseCode, resp.Body)
+		}
+	}
+}
+
+func TestBatchRequest_ExecuteBatch_PartialFailure(t *testing.T) {
+	batches := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			batches++
+			var body struct {
+				Requests []json.RawMessage `json:"requests"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+			if batches == 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"type":"error","status":400,"code":"bad_request"}`))
+				return
+			}
+			var responses []map[string]interface{}
+			for range body.Requests {
+				responses = append(responses, map[string]interface{}{"status": 201, "response": map[string]interface{}{"type": "folder", "id": "1"}})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+		},
+	))
+	defer ts.Close()
+
+	apiConn := commonInit(ts.URL)
+
+	got, err := NewBatchRequest(apiConn).ExecuteBatch(nil)
+	if err != nil || got == nil || len(got.Responses) != 0 || batches != 0 {
+		t.Fatalf("ExecuteBatch(nil) = %v, %v", got, err)
+	}
+
+	var requests []*Request
+	for i := 0; i < 45; i++ {
+		requests = append(requests, NewFolder(apiConn).CreateReq("0", "folder"+strconv.Itoa(i), nil))
+	}
+	got, err = NewBatchRequest(apiConn).ExecuteBatch(requests)
+	var apiErr *ApiStatusError
+	if !xerrors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Fatalf("ExecuteBatch() error = %v", err)
+	}
+	if got == nil || len(got.Responses) != len(requests) {
+		t.Fatalf("ExecuteBatch() = %v, want the responses aligned to the requests", got)
+	}
+	for i, resp := range got.Responses {
+		// only the first chunk has been executed
+		if executed := resp != nil && resp.ResponseCode == http.StatusCreated; executed != (i < 20) {
+			t.Errorf("Responses[%d] = %v", i, resp)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package goboxer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SearchScope string
+
+func (ss *SearchScope) String() string {
+	if ss == nil {
+		return "<nil>"
+	}
+	return string(*ss)
+}
+
+const (
+	SearchScopeUserContent       SearchScope = "user_content"
+	SearchScopeEnterpriseContent SearchScope = "enterprise_content"
+)
+
+type SearchContentType string
+
+func (ct *SearchContentType) String() string {
+	if ct == nil {
+		return "<nil>"
+	}
+	return string(*ct)
+}
+
+const (
+	SearchContentTypeName        SearchContentType = "name"
+	SearchContentTypeDescription SearchContentType = "description"
+	SearchContentTypeFileContent SearchContentType = "file_content"
+	SearchContentTypeComments    SearchContentType = "comments"
+	SearchContentTypeTags        SearchContentType = "tags"
+)
+
+type SearchTrashContent string
+
+func (tc *SearchTrashContent) String() string {
+	if tc == nil {
+		return "<nil>"
+	}
+	return string(*tc)
+}
+
+const (
+	SearchTrashContentNonTrashedOnly SearchTrashContent = "non_trashed_only"
+	SearchTrashContentTrashedOnly    SearchTrashContent = "trashed_only"
+	SearchTrashContentAllItems       SearchTrashContent = "all_items"
+)
+
+type SearchSort string
+
+func (ss *SearchSort) String() string {
+	if ss == nil {
+		return "<nil>"
+	}
+	return string(*ss)
+}
+
+const (
+	SearchSortRelevance  SearchSort = "relevance"
+	SearchSortModifiedAt SearchSort = "modified_at"
+)
+
+// MetadataSearchFilter limits the search results to the items with the instance of the template matching the filters.
+type MetadataSearchFilter struct {
+	Scope       string                 `json:"scope"`
+	TemplateKey string                 `json:"templateKey"`
+	Filters     map[string]interface{} `json:"filters"`
+}
+
+func NewMetadataSearchFilter(scope string, templateKey string) *MetadataSearchFilter {
+	return &MetadataSearchFilter{Scope: scope, TemplateKey: templateKey, Filters: map[string]interface{}{}}
+}
+
+// Equal matches the field with the value. For an enum or multiSelect field, value can be a slice to match any of them.
+func (mf *MetadataSearchFilter) Equal(field string, value interface{}) *MetadataSearchFilter {
+	mf.Filters[field] = searchValue(value)
+	return mf
+}
+
+// Range matches the float or date field in the range [from, to]. nil means no bound.
+func (mf *MetadataSearchFilter) Range(field string, from interface{}, to interface{}) *MetadataSearchFilter {
+	r := map[string]interface{}{}
+	if from != nil {
+		r["gt"] = searchValue(from)
+	}
+	if to != nil {
+		r["lt"] = searchValue(to)
+	}
+	mf.Filters[field] = r
+	return mf
+}
+
+func searchValue(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return v
+}
+
+// Search finds files and folders. Set the conditions by SetXxx and call Execute.
+type Search struct {
+	apiInfo           *apiInfo
+	query             string
+	scope             SearchScope
+	itemType          ItemType
+	fileExtensions    []string
+	createdAtRange    string
+	updatedAtRange    string
+	sizeRange         string
+	ownerUserIds      []string
+	ancestorFolderIds []string
+	contentTypes      []SearchContentType
+	trashContent      SearchTrashContent
+	mdFilters         []*MetadataSearchFilter
+	sort              SearchSort
+	direction         string
+}
+
+func NewSearch(api Connection) *Search {
+	return &Search{apiInfo: api.connInfo()}
+}
+
+func (s *Search) SetQuery(query string) *Search {
+	s.query = query
+	return s
+}
+func (s *Search) SetScope(scope SearchScope) *Search {
+	s.scope = scope
+	return s
+}
+
+// SetType limits the results to files (TYPE_FILE) or folders (TYPE_FOLDER).
+func (s *Search) SetType(itemType ItemType) *Search {
+	s.itemType = itemType
+	return s
+}
+
+// SetFileExtensions limits the results to the files with the extensions. (e.g. "pdf", "docx")
+func (s *Search) SetFileExtensions(extensions ...string) *Search {
+	s.fileExtensions = extensions
+	return s
+}
+
+// SetCreatedAtRange limits the results to the items created in [from, to]. The zero time means no bound.
+func (s *Search) SetCreatedAtRange(from time.Time, to time.Time) *Search {
+	s.createdAtRange = timeRange(from, to)
+	return s
+}
+
+// SetUpdatedAtRange limits the results to the items updated in [from, to]. The zero time means no bound.
+func (s *Search) SetUpdatedAtRange(from time.Time, to time.Time) *Search {
+	s.updatedAtRange = timeRange(from, to)
+	return s
+}
+
+func timeRange(from time.Time, to time.Time) string {
+	var r [2]string
+	for i, t := range []time.Time{from, to} {
+		if !t.IsZero() {
+			r[i] = t.Format(time.RFC3339)
+		}
+	}
+	if r[0] == "" && r[1] == "" {
+		return ""
+	}
+	return r[0] + "," + r[1]
+}
+
+// SetSizeRange limits the results to the items whose size is in [lower, upper] bytes. A negative value means no bound.
+func (s *Search) SetSizeRange(lower int64, upper int64) *Search {
+	var r [2]string
+	for i, size := range []int64{lower, upper} {
+		if size >= 0 {
+			r[i] = strconv.FormatInt(size, 10)
+		}
+	}
+	s.sizeRange = ""
+	if r[0] != "" || r[1] != "" {
+		s.sizeRange = r[0] + "," + r[1]
+	}
+	return s
+}
+func (s *Search) SetOwnerUserIds(userIds ...string) *Search {
+	s.ownerUserIds = userIds
+	return s
+}
+func (s *Search) SetAncestorFolderIds(folderIds ...string) *Search {
+	s.ancestorFolderIds = folderIds
+	return s
+}
+
+// SetContentTypes limits the parts of the items where the query is searched.
+func (s *Search) SetContentTypes(contentTypes ...SearchContentType) *Search {
+	s.contentTypes = contentTypes
+	return s
+}
+func (s *Search) SetTrashContent(trashContent SearchTrashContent) *Search {
+	s.trashContent = trashContent
+	return s
+}
+
+// AddMetadataFilter adds the metadata filter. Currently Box accepts only one filter.
+func (s *Search) AddMetadataFilter(filter *MetadataSearchFilter) *Search {
+	s.mdFilters = append(s.mdFilters, filter)
+	return s
+}
+
+// SetSort sets the order of the results. direction is "ASC" or "DESC", only for SearchSortModifiedAt.
+func (s *Search) SetSort(sort SearchSort, direction string) *Search {
+	s.sort = sort
+	s.direction = direction
+	return s
+}
+
+// Searching for Content
+//
+// Searches for files and folders with the conditions set by SetXxx.
+// Either a query or a metadata filter is required.
+// https://developer.box.com/reference#searching-for-content
+func (s *Search) ExecuteReq(offset int, limit int, fields []string) *Request {
+	urlBase := fmt.Sprintf("%s%s", s.apiInfo.api.BaseURL, "search")
+
+	if limit > 200 {
+		limit = 200
+	}
+	var params []string
+	add := func(name string, value string) {
+		if value != "" {
+			params = append(params, name+"="+url.QueryEscape(value))
+		}
+	}
+	add("query", s.query)
+	add("scope", string(s.scope))
+	add("type", string(s.itemType))
+	add("file_extensions", strings.Join(s.fileExtensions, ","))
+	add("created_at_range", s.createdAtRange)
+	add("updated_at_range", s.updatedAtRange)
+	add("size_range", s.sizeRange)
+	add("owner_user_ids", strings.Join(s.ownerUserIds, ","))
+	add("ancestor_folder_ids", strings.Join(s.ancestorFolderIds, ","))
+	var contentTypes []string
+	for _, ct := range s.contentTypes {
+		contentTypes = append(contentTypes, string(ct))
+	}
+	add("content_types", strings.Join(contentTypes, ","))
+	add("trash_content", string(s.trashContent))
+	if len(s.mdFilters) != 0 {
+		mdFilters, _ := json.Marshal(s.mdFilters)
+		add("mdfilters", string(mdFilters))
+	}
+	add("sort", string(s.sort))
+	add("direction", s.direction)
+	params = append(params, fmt.Sprintf("offset=%d", offset), fmt.Sprintf("limit=%d", limit))
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		params = append(params, fieldsParam)
+	}
+	return s.apiInfo.newRequest(urlBase+"?"+strings.Join(params, "&"), GET, nil, nil)
+}
+
+// Searching for Content
+//
+// Searches for files and folders with the conditions set by SetXxx.
+// Either a query or a metadata filter is required.
+// https://developer.box.com/reference#searching-for-content
+func (s *Search) Execute(offset int, limit int, fields []string) (outResources []BoxResource, outOffset int, outLimit int, outTotalCount int, err error) {
+	req := s.ExecuteReq(offset, limit, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, 0, 0, 0, newApiStatusError(resp)
+	}
+	results := struct {
+		TotalCount int               `json:"total_count"`
+		Entries    []json.RawMessage `json:"entries"`
+		Offset     int               `json:"offset"`
+		Limit      int               `json:"limit"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &results)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	var entries []BoxResource
+	for _, entity := range results.Entries {
+		boxResource, err := ParseResource(entity)
+		if err != nil {
+			return nil, 0, 0, 0, err
+		}
+		setApiInfo(boxResource, s.apiInfo)
+		entries = append(entries, boxResource)
+	}
+	return entries, results.Offset, results.Limit, results.TotalCount, nil
+}
+
+// Searching for Content
+//
+// ExecuteIter returns the iterator of all the files and folders matching the search. If pageSize is 0, 100 is used.
+func (s *Search) ExecuteIter(fields []string, pageSize int) *ItemIterator {
+	return &ItemIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := s.Execute(offset, limit, fields)
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Searching for Content
+//
+// ExecuteAll returns all the files and folders matching the search.
+func (s *Search) ExecuteAll(fields []string) ([]BoxResource, error) {
+	var r []BoxResource
+	err := s.ExecuteIter(fields, 200).allInto(&r)
+	return r, err
+}
@@ -0,0 +1,49 @@
+package goboxer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearch_ExecuteReq(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+	from := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		search  *Search
+		offset  int
+		limit   int
+		fields  []string
+		wantUrl string
+	}{
+		{"query only", NewSearch(apiConn).SetQuery("annual report"), 0, 100, nil,
+			url + "/2.0/search?query=annual+report&offset=0&limit=100"},
+		{"all options", NewSearch(apiConn).SetQuery("report").SetScope(SearchScopeEnterpriseContent).SetType(TYPE_FILE).
+			SetFileExtensions("pdf", "docx").SetCreatedAtRange(from, time.Time{}).SetUpdatedAtRange(time.Time{}, from).
+			SetSizeRange(1024, -1).SetOwnerUserIds("1", "2").SetAncestorFolderIds("20001").
+			SetContentTypes(SearchContentTypeName, SearchContentTypeTags).SetTrashContent(SearchTrashContentAllItems).
+			SetSort(SearchSortModifiedAt, "ASC"), 200, 500, []string{"name", "size"},
+			url + "/2.0/search?query=report&scope=enterprise_content&type=file&file_extensions=pdf%2Cdocx" +
+				"&created_at_range=2020-01-02T03%3A04%3A05Z%2C&updated_at_range=%2C2020-01-02T03%3A04%3A05Z&size_range=1024%2C" +
+				"&owner_user_ids=1%2C2&ancestor_folder_ids=20001&content_types=name%2Ctags&trash_content=all_items" +
+				"&sort=modified_at&direction=ASC&offset=200&limit=200&fields=name,size"},
+		{"unbounded ranges are omitted", NewSearch(apiConn).SetQuery("q").SetCreatedAtRange(time.Time{}, time.Time{}).SetSizeRange(-1, -1), 0, 10, nil,
+			url + "/2.0/search?query=q&offset=0&limit=10"},
+		{"metadata filter", NewSearch(apiConn).AddMetadataFilter(NewMetadataSearchFilter(MetadataScopeEnterprise, "contract").
+			Equal("vendor", "Acme").Range("amount", 100, nil).Range("signedAt", nil, from)), 0, 100, nil,
+			url + "/2.0/search?mdfilters=" +
+				"%5B%7B%22scope%22%3A%22enterprise%22%2C%22templateKey%22%3A%22contract%22%2C%22filters%22%3A%7B" +
+				"%22amount%22%3A%7B%22gt%22%3A100%7D%2C%22signedAt%22%3A%7B%22lt%22%3A%222020-01-02T03%3A04%3A05Z%22%7D%2C%22vendor%22%3A%22Acme%22%7D%7D%5D" +
+				"&offset=0&limit=100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.search.ExecuteReq(tt.offset, tt.limit, tt.fields)
+			if req.Method != GET || req.Url != tt.wantUrl {
+				t.Errorf("got %v %s,\nwant %v %s", req.Method, req.Url, GET, tt.wantUrl)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package goboxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type ResolutionState string
+
+func (rs *ResolutionState) String() string {
+	if rs == nil {
+		return "<nil>"
+	}
+	return string(*rs)
+}
+
+const (
+	ResolutionStateCompleted  ResolutionState = "completed"
+	ResolutionStateIncomplete ResolutionState = "incomplete"
+	ResolutionStateApproved   ResolutionState = "approved"
+	ResolutionStateRejected   ResolutionState = "rejected"
+)
+
+type TaskAssignment struct {
+	apiInfo         *apiInfo
+	Type            *string          `json:"type,omitempty"`
+	ID              *string          `json:"id,omitempty"`
+	Item            *ItemMini        `json:"item,omitempty"`
+	AssignedTo      *UserGroupMini   `json:"assigned_to,omitempty"`
+	Message         *string          `json:"message,omitempty"`
+	CompletedAt     *time.Time       `json:"completed_at,omitempty"`
+	AssignedAt      *time.Time       `json:"assigned_at,omitempty"`
+	RemindedAt      *time.Time       `json:"reminded_at,omitempty"`
+	ResolutionState *ResolutionState `json:"resolution_state,omitempty"`
+	AssignedBy      *UserGroupMini   `json:"assigned_by,omitempty"`
+}
+
+func (ta *TaskAssignment) ResourceType() BoxResourceType {
+	return TaskAssignmentResource
+}
+
+func NewTaskAssignment(api Connection) *TaskAssignment {
+	return &TaskAssignment{apiInfo: api.connInfo()}
+}
+
+var TaskAssignmentAllFields = []string{
+	"type", "id", "item", "assigned_to", "message", "completed_at",
+	"assigned_at", "reminded_at", "resolution_state", "assigned_by",
+}
+
+// Get Task Assignment
+//
+// Fetches a specific task assignment.
+// https://developer.box.com/reference#get-a-task-assignment
+func (ta *TaskAssignment) GetInfoReq(assignmentId string, fields []string) *Request {
+	var url string
+	var query string
+	url = fmt.Sprintf("%s%s%s", ta.apiInfo.api.BaseURL, "task_assignments/", assignmentId)
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+	return ta.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get Task Assignment
+//
+// GetInfoBatch adds GetInfoReq to the batch request, and returns the handle of the task assignment.
+func (ta *TaskAssignment) GetInfoBatch(batch *BatchRequest, assignmentId string, fields []string) *PendingTaskAssignment {
+	return batch.addTaskAssignment(ta.GetInfoReq(assignmentId, fields))
+}
+
+// Get Task Assignment
+//
+// Fetches a specific task assignment.
+// https://developer.box.com/reference#get-a-task-assignment
+func (ta *TaskAssignment) GetInfo(assignmentId string, fields []string) (*TaskAssignment, error) {
+	req := ta.GetInfoReq(assignmentId, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &TaskAssignment{apiInfo: ta.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Create Task Assignment
+//
+// Assigns a task to a user, specified by ID or by login of assignTo.
+// https://developer.box.com/reference#create-a-task-assignment
+func (ta *TaskAssignment) CreateReq(taskId string, assignTo UserGroupMini, fields []string) *Request {
+	var url string
+	var query string
+	url = fmt.Sprintf("%s%s", ta.apiInfo.api.BaseURL, "task_assignments")
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+
+	assignee := map[string]string{}
+	if assignTo.ID != nil {
+		assignee["id"] = *assignTo.ID
+	} else if assignTo.Login != nil {
+		assignee["login"] = *assignTo.Login
+	}
+	body := map[string]interface{}{
+		"task":      map[string]string{"type": "task", "id": taskId},
+		"assign_to": assignee,
+	}
+	bodyBytes, _ := json.Marshal(body)
+	return ta.apiInfo.newRequest(url+query, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Create Task Assignment
+//
+// CreateBatch adds CreateReq to the batch request, and returns the handle of the task assignment.
+func (ta *TaskAssignment) CreateBatch(batch *BatchRequest, taskId string, assignTo UserGroupMini, fields []string) *PendingTaskAssignment {
+	return batch.addTaskAssignment(ta.CreateReq(taskId, assignTo, fields))
+}
+
+// Create Task Assignment
+//
+// Assigns a task to a user, specified by ID or by login of assignTo.
+// https://developer.box.com/reference#create-a-task-assignment
+func (ta *TaskAssignment) Create(taskId string, assignTo UserGroupMini, fields []string) (*TaskAssignment, error) {
+	req := ta.CreateReq(taskId, assignTo, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusCreated {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &TaskAssignment{apiInfo: ta.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Update Task Assignment
+//
+// Updates the message and/or the resolution state of a task assignment.
+// An empty message or resolutionState is not changed.
+// https://developer.box.com/reference#update-a-task-assignment
+func (ta *TaskAssignment) UpdateReq(assignmentId string, message string, resolutionState ResolutionState, fields []string) *Request {
+	var url string
+	var query string
+	url = fmt.Sprintf("%s%s%s", ta.apiInfo.api.BaseURL, "task_assignments/", assignmentId)
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+
+	body := map[string]string{}
+	if message != "" {
+		body["message"] = message
+	}
+	if resolutionState != "" {
+		body["resolution_state"] = string(resolutionState)
+	}
+	bodyBytes, _ := json.Marshal(body)
+	return ta.apiInfo.newRequest(url+query, PUT, nil, bytes.NewReader(bodyBytes))
+}
+
+// Update Task Assignment
+//
+// UpdateBatch adds UpdateReq to the batch request, and returns the handle of the task assignment.
+func (ta *TaskAssignment) UpdateBatch(batch *BatchRequest, assignmentId string, message string, resolutionState ResolutionState, fields []string) *PendingTaskAssignment {
+	return batch.addTaskAssignment(ta.UpdateReq(assignmentId, message, resolutionState, fields))
+}
+
+// Update Task Assignment
+//
+// Updates the message and/or the resolution state of a task assignment.
+// An empty message or resolutionState is not changed.
+// https://developer.box.com/reference#update-a-task-assignment
+func (ta *TaskAssignment) Update(assignmentId string, message string, resolutionState ResolutionState, fields []string) (*TaskAssignment, error) {
+	req := ta.UpdateReq(assignmentId, message, resolutionState, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &TaskAssignment{apiInfo: ta.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Delete Task Assignment
+//
+// Deletes a specific task assignment.
+// https://developer.box.com/reference#delete-a-task-assignment
+func (ta *TaskAssignment) DeleteReq(assignmentId string) *Request {
+	var url string
+	url = fmt.Sprintf("%s%s%s", ta.apiInfo.api.BaseURL, "task_assignments/", assignmentId)
+	return ta.apiInfo.newRequest(url, DELETE, nil, nil)
+}
+
+// Delete Task Assignment
+//
+// DeleteBatch adds DeleteReq to the batch request, and returns the handle of its error.
+func (ta *TaskAssignment) DeleteBatch(batch *BatchRequest, assignmentId string) *PendingNoContent {
+	return batch.addNoContent(ta.DeleteReq(assignmentId))
+}
+
+// Delete Task Assignment
+//
+// Deletes a specific task assignment.
+// https://developer.box.com/reference#delete-a-task-assignment
+func (ta *TaskAssignment) Delete(assignmentId string) error {
+	req := ta.DeleteReq(assignmentId)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+package goboxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type TaskAction string
+
+func (ta *TaskAction) String() string {
+	if ta == nil {
+		return "<nil>"
+	}
+	return string(*ta)
+}
+
+const (
+	TaskActionReview   TaskAction = "review"
+	TaskActionComplete TaskAction = "complete"
+)
+
+type TaskCompletionRule string
+
+func (tc *TaskCompletionRule) String() string {
+	if tc == nil {
+		return "<nil>"
+	}
+	return string(*tc)
+}
+
+const (
+	TaskCompletionRuleAllAssignees TaskCompletionRule = "all_assignees"
+	TaskCompletionRuleAnyAssignee  TaskCompletionRule = "any_assignee"
+)
+
+type Task struct {
+	apiInfo                  *apiInfo
+	Type                     *string             `json:"type,omitempty"`
+	ID                       *string             `json:"id,omitempty"`
+	Item                     *ItemMini           `json:"item,omitempty"`
+	DueAt                    *time.Time          `json:"due_at,omitempty"`
+	Action                   *TaskAction         `json:"action,omitempty"`
+	Message                  *string             `json:"message,omitempty"`
+	TaskAssignmentCollection *TaskAssignments    `json:"task_assignment_collection,omitempty"`
+	IsCompleted              *bool               `json:"is_completed,omitempty"`
+	CreatedBy                *UserGroupMini      `json:"created_by,omitempty"`
+	CreatedAt                *time.Time          `json:"created_at,omitempty"`
+	CompletionRule           *TaskCompletionRule `json:"completion_rule,omitempty"`
+
+	changedFlag uint64
+}
+
+// TaskAssignments is the assignments of a task.
+type TaskAssignments struct {
+	TotalCount int               `json:"total_count"`
+	Entries    []*TaskAssignment `json:"entries"`
+}
+
+const (
+	cTaskAction uint64 = 1 << (iota)
+	cTaskMessage
+	cTaskDueAt
+	cTaskCompletionRule
+)
+
+func (t *Task) ResourceType() BoxResourceType {
+	return TaskResource
+}
+
+func NewTask(api Connection) *Task {
+	return &Task{apiInfo: api.connInfo()}
+}
+
+var TaskAllFields = []string{
+	"type", "id", "item", "due_at", "action", "message", "task_assignment_collection",
+	"is_completed", "created_by", "created_at", "completion_rule",
+}
+
+func (t *Task) SetAction(action TaskAction) *Task {
+	t.Action = &action
+	t.changedFlag |= cTaskAction
+	return t
+}
+func (t *Task) SetMessage(message string) *Task {
+	t.Message = &message
+	t.changedFlag |= cTaskMessage
+	return t
+}
+func (t *Task) SetDueAt(dueAt time.Time) *Task {
+	t.DueAt = &dueAt
+	t.changedFlag |= cTaskDueAt
+	return t
+}
+func (t *Task) SetCompletionRule(completionRule TaskCompletionRule) *Task {
+	t.CompletionRule = &completionRule
+	t.changedFlag |= cTaskCompletionRule
+	return t
+}
+
+// body returns the fields set by SetXxx.
+func (t *Task) body() map[string]interface{} {
+	data := map[string]interface{}{}
+	if t.changedFlag&cTaskAction == cTaskAction {
+		data["action"] = t.Action
+	}
+	if t.changedFlag&cTaskMessage == cTaskMessage {
+		data["message"] = t.Message
+	}
+	if t.changedFlag&cTaskDueAt == cTaskDueAt {
+		data["due_at"] = t.DueAt.Format(time.RFC3339)
+	}
+	if t.changedFlag&cTaskCompletionRule == cTaskCompletionRule {
+		data["completion_rule"] = t.CompletionRule
+	}
+	return data
+}
+
+// Get Task
+//
+// Fetches a specific task.
+// https://developer.box.com/reference#get-a-task
+func (t *Task) GetInfoReq(taskId string, fields []string) *Request {
+	var url string
+	var query string
+	url = fmt.Sprintf("%s%s%s", t.apiInfo.api.BaseURL, "tasks/", taskId)
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+	return t.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get Task
+//
+// GetInfoBatch adds GetInfoReq to the batch request, and returns the handle of the task.
+func (t *Task) GetInfoBatch(batch *BatchRequest, taskId string, fields []string) *PendingTask {
+	return batch.addTask(t.GetInfoReq(taskId, fields))
+}
+
+// Get Task
+//
+// Fetches a specific task.
+// https://developer.box.com/reference#get-a-task
+func (t *Task) GetInfo(taskId string, fields []string) (*Task, error) {
+	req := t.GetInfoReq(taskId, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &Task{apiInfo: t.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	r.setApiInfoToAssignments()
+	return r, nil
+}
+
+// Create Task
+//
+// Creates a task on the file with the fields set by SetAction, SetMessage, SetDueAt and SetCompletionRule.
+// The action defaults to review.
+// https://developer.box.com/reference#create-a-task
+func (t *Task) CreateReq(fileId string, fields []string) *Request {
+	var url string
+	var query string
+	url = fmt.Sprintf("%s%s", t.apiInfo.api.BaseURL, "tasks")
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+
+	data := t.body()
+	data["item"] = map[string]string{"type": "file", "id": fileId}
+	bodyBytes, _ := json.Marshal(data)
+	return t.apiInfo.newRequest(url+query, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Create Task
+//
+// CreateBatch adds CreateReq to the batch request, and returns the handle of the task.
+func (t *Task) CreateBatch(batch *BatchRequest, fileId string, fields []string) *PendingTask {
+	return batch.addTask(t.CreateReq(fileId, fields))
+}
+
+// Create Task
+//
+// Creates a task on the file with the fields set by SetAction, SetMessage, SetDueAt and SetCompletionRule.
+// The action defaults to review.
+// https://developer.box.com/reference#create-a-task
+func (t *Task) Create(fileId string, fields []string) (*Task, error) {
+	req := t.CreateReq(fileId, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusCreated {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &Task{apiInfo: t.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	r.setApiInfoToAssignments()
+	return r, nil
+}
+
+// Update Task
+//
+// Updates the fields of the task set by SetAction, SetMessage, SetDueAt and SetCompletionRule.
+// https://developer.box.com/reference#update-a-task
+func (t *Task) UpdateReq(taskId string, fields []string) *Request {
+	var url string
+	var query string
+	url = fmt.Sprintf("%s%s%s", t.apiInfo.api.BaseURL, "tasks/", taskId)
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+
+	bodyBytes, _ := json.Marshal(t.body())
+	return t.apiInfo.newRequest(url+query, PUT, nil, bytes.NewReader(bodyBytes))
+}
+
+// Update Task
+//
+// UpdateBatch adds UpdateReq to the batch request, and returns the handle of the task.
+func (t *Task) UpdateBatch(batch *BatchRequest, taskId string, fields []string) *PendingTask {
+	return batch.addTask(t.UpdateReq(taskId, fields))
+}
+
+// Update Task
+//
+// Updates the fields of the task set by SetAction, SetMessage, SetDueAt and SetCompletionRule.
+// https://developer.box.com/reference#update-a-task
+func (t *Task) Update(taskId string, fields []string) (*Task, error) {
+	req := t.UpdateReq(taskId, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &Task{apiInfo: t.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	r.setApiInfoToAssignments()
+	return r, nil
+}
+
+// Delete Task
+//
+// Permanently deletes a specific task.
+// https://developer.box.com/reference#delete-a-task
+func (t *Task) DeleteReq(taskId string) *Request {
+	var url string
+	url = fmt.Sprintf("%s%s%s", t.apiInfo.api.BaseURL, "tasks/", taskId)
+	return t.apiInfo.newRequest(url, DELETE, nil, nil)
+}
+
+// Delete Task
+//
+// DeleteBatch adds DeleteReq to the batch request, and returns the handle of its error.
+func (t *Task) DeleteBatch(batch *BatchRequest, taskId string) *PendingNoContent {
+	return batch.addNoContent(t.DeleteReq(taskId))
+}
+
+// Delete Task
+//
+// Permanently deletes a specific task.
+// https://developer.box.com/reference#delete-a-task
+func (t *Task) Delete(taskId string) error {
+	req := t.DeleteReq(taskId)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
+
+// Get Task Assignments
+//
+// Retrieves all of the assignments for a given task.
+// https://developer.box.com/reference#get-the-assignments-for-a-task
+func (t *Task) AssignmentsReq(taskId string) *Request {
+	var url string
+	url = fmt.Sprintf("%s%s%s%s", t.apiInfo.api.BaseURL, "tasks/", taskId, "/assignments")
+	return t.apiInfo.newRequest(url, GET, nil, nil)
+}
+
+// Get Task Assignments
+//
+// Retrieves all of the assignments for a given task.
+// https://developer.box.com/reference#get-the-assignments-for-a-task
+func (t *Task) Assignments(taskId string) ([]*TaskAssignment, error) {
+	req := t.AssignmentsReq(taskId)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	assignments := &TaskAssignments{}
+	err = UnmarshalJSONWrapper(resp.Body, assignments)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assignments.Entries {
+		a.apiInfo = t.apiInfo
+	}
+	return assignments.Entries, nil
+}
+
+func (t *Task) setApiInfoToAssignments() {
+	if t.TaskAssignmentCollection == nil {
+		return
+	}
+	for _, a := range t.TaskAssignmentCollection.Entries {
+		a.apiInfo = t.apiInfo
+	}
+}
+
+// Get File Tasks
+//
+// Retrieves all of the tasks for a file.
+// https://developer.box.com/reference#get-the-tasks-for-a-file
+func (f *File) TasksReq(fileId string, fields []string) *Request {
+	var url string
+	var query string
+	url = fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "files/", fileId, "/tasks")
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query = fmt.Sprintf("?%s", fieldsParam)
+	}
+	return f.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get File Tasks
+//
+// Retrieves all of the tasks for a file.
+// https://developer.box.com/reference#get-the-tasks-for-a-file
+func (f *File) Tasks(fileId string, fields []string) ([]*Task, error) {
+	req := f.TasksReq(fileId, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, newApiStatusError(resp)
+	}
+
+	tasks := struct {
+		TotalCount int     `json:"total_count"`
+		Entries    []*Task `json:"entries"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks.Entries {
+		t.apiInfo = f.apiInfo
+		t.setApiInfoToAssignments()
+	}
+	return tasks.Entries, nil
+}
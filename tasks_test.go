@@ -0,0 +1,101 @@
+package goboxer
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestTask_CreateReq(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+	dueAt := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		task     *Task
+		fields   []string
+		wantUrl  string
+		wantBody string
+	}{
+		{"default", NewTask(apiConn), nil,
+			url + "/2.0/tasks", `{"item":{"id":"10001","type":"file"}}`},
+		{"all", NewTask(apiConn).SetAction(TaskActionComplete).SetMessage("do it").SetDueAt(dueAt).SetCompletionRule(TaskCompletionRuleAnyAssignee), []string{"type", "id"},
+			url + "/2.0/tasks?fields=type,id", `{"action":"complete","completion_rule":"any_assignee","due_at":"2020-04-01T10:00:00Z","item":{"id":"10001","type":"file"},"message":"do it"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.task.CreateReq("10001", tt.fields)
+			if got.Method != POST || got.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want POST %s", got.Method, got.Url, tt.wantUrl)
+			}
+			body, _ := ioutil.ReadAll(got.body)
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestTaskAssignment_Reqs(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+	id := "3001"
+	login := "user@example.com"
+
+	tests := []struct {
+		name       string
+		req        *Request
+		wantMethod Method
+		wantUrl    string
+		wantBody   string
+	}{
+		{"create by id", NewTaskAssignment(apiConn).CreateReq("2001", UserGroupMini{ID: &id, Login: &login}, nil),
+			POST, url + "/2.0/task_assignments", `{"assign_to":{"id":"3001"},"task":{"id":"2001","type":"task"}}`},
+		{"create by login", NewTaskAssignment(apiConn).CreateReq("2001", UserGroupMini{Login: &login}, nil),
+			POST, url + "/2.0/task_assignments", `{"assign_to":{"login":"user@example.com"},"task":{"id":"2001","type":"task"}}`},
+		{"update state", NewTaskAssignment(apiConn).UpdateReq("4001", "", ResolutionStateApproved, nil),
+			PUT, url + "/2.0/task_assignments/4001", `{"resolution_state":"approved"}`},
+		{"update message", NewTaskAssignment(apiConn).UpdateReq("4001", "done", "", []string{"id"}),
+			PUT, url + "/2.0/task_assignments/4001?fields=id", `{"message":"done"}`},
+		{"list", NewTask(apiConn).AssignmentsReq("2001"),
+			GET, url + "/2.0/tasks/2001/assignments", ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Method != tt.wantMethod || tt.req.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want %v %s", tt.req.Method, tt.req.Url, tt.wantMethod, tt.wantUrl)
+			}
+			if tt.req.body != nil {
+				body, _ := ioutil.ReadAll(tt.req.body)
+				if string(body) != tt.wantBody {
+					t.Errorf("body = %s, want %s", body, tt.wantBody)
+				}
+			}
+		})
+	}
+}
+
+func TestParseResource_Task(t *testing.T) {
+	r, err := ParseResource([]byte(`{"type":"task","id":"2001","action":"review","task_assignment_collection":{"total_count":1,"entries":[{"type":"task_assignment","id":"4001","resolution_state":"incomplete"}]}}`))
+	if err != nil {
+		t.Fatalf("ParseResource() error = %v", err)
+	}
+	task, ok := r.(*Task)
+	if !ok || *task.ID != "2001" || *task.Action != TaskActionReview {
+		t.Fatalf("ParseResource() = %#v", r)
+	}
+	info := &apiInfo{api: commonInit("https://example.com")}
+	setApiInfo(task, info)
+	if task.apiInfo != info || task.TaskAssignmentCollection.Entries[0].apiInfo != info {
+		t.Errorf("apiInfo is not set")
+	}
+
+	r, err = ParseResource([]byte(`{"type":"task_assignment","id":"4001","resolution_state":"approved"}`))
+	if err != nil {
+		t.Fatalf("ParseResource() error = %v", err)
+	}
+	if ta, ok := r.(*TaskAssignment); !ok || *ta.ResolutionState != ResolutionStateApproved {
+		t.Errorf("ParseResource() = %#v", r)
+	}
+}
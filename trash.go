@@ -0,0 +1,301 @@
+package goboxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Get Trashed Items
+//
+// Retrieves the files and folders that have been moved to the trash.
+// The items in a trashed folder are not listed, only the folder is.
+// https://developer.box.com/reference#get-the-items-in-the-trash
+//
+//	sort: "name", "date" or "size" ("" means default)
+//	sortDir: "ASC" or "DESC" ("" means default)
+func (f *Folder) TrashedItemsReq(offset int, limit int, sort string, sortDir string, fields []string) *Request {
+	url := fmt.Sprintf("%s%s", f.apiInfo.api.BaseURL, "folders/trash/items")
+	if limit > 1000 {
+		limit = 1000
+	}
+	query := fmt.Sprintf("?offset=%d&limit=%d", offset, limit)
+	if sort != "" {
+		query += fmt.Sprintf("&sort=%s", sort)
+	}
+	if sortDir != "" {
+		query += fmt.Sprintf("&direction=%s", sortDir)
+	}
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		query += fmt.Sprintf("&%s", fieldsParam)
+	}
+	return f.apiInfo.newRequest(url+query, GET, nil, nil)
+}
+
+// Get Trashed Items
+//
+// Retrieves the files and folders that have been moved to the trash.
+// The items in a trashed folder are not listed, only the folder is.
+// https://developer.box.com/reference#get-the-items-in-the-trash
+//
+//	sort: "name", "date" or "size" ("" means default)
+//	sortDir: "ASC" or "DESC" ("" means default)
+func (f *Folder) TrashedItems(offset int, limit int, sort string, sortDir string, fields []string) (outResources []BoxResource, outOffset, outLimit, outTotalCount int, err error) {
+	req := f.TrashedItemsReq(offset, limit, sort, sortDir, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, 0, 0, 0, newApiStatusError(resp)
+	}
+	items := struct {
+		TotalCount int               `json:"total_count"`
+		Offset     int               `json:"offset"`
+		Limit      int               `json:"limit"`
+		Entries    []json.RawMessage `json:"entries"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &items)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	var entries []BoxResource
+	for _, entity := range items.Entries {
+		boxResource, err := ParseResource(entity)
+		if err != nil {
+			return nil, 0, 0, 0, err
+		}
+		setApiInfo(boxResource, f.apiInfo)
+		entries = append(entries, boxResource)
+	}
+	return entries, items.Offset, items.Limit, items.TotalCount, nil
+}
+
+// Get Trashed Items
+//
+// TrashedItemsIter returns the iterator of all the items in the trash. If pageSize is 0, 100 is used.
+func (f *Folder) TrashedItemsIter(sort string, sortDir string, fields []string, pageSize int) *ItemIterator {
+	return &ItemIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := f.TrashedItems(offset, limit, sort, sortDir, fields)
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get Trashed Items
+//
+// TrashedItemsAll returns all the items in the trash.
+func (f *Folder) TrashedItemsAll(sort string, sortDir string, fields []string) ([]BoxResource, error) {
+	var r []BoxResource
+	err := f.TrashedItemsIter(sort, sortDir, fields, 1000).allInto(&r)
+	return r, err
+}
+
+// restoreBody returns the body to restore the item with the new name and the new parent ("" means the original).
+func restoreBody(newName string, newParentId string) *bytes.Reader {
+	body := map[string]interface{}{}
+	if newName != "" {
+		body["name"] = newName
+	}
+	if newParentId != "" {
+		body["parent"] = map[string]string{"id": newParentId}
+	}
+	bodyBytes, _ := json.Marshal(body)
+	return bytes.NewReader(bodyBytes)
+}
+
+// Get Trashed Folder
+//
+// Retrieves the folder that has been moved to the trash.
+// Only the folder moved to the trash itself can be retrieved, not the folders in it.
+// https://developer.box.com/reference#get-trashed-folder
+func (f *Folder) GetTrashedInfoReq(folderId string, fields []string) *Request {
+	url := fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "folders/", folderId, "/trash")
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		url += fmt.Sprintf("?%s", fieldsParam)
+	}
+	return f.apiInfo.newRequest(url, GET, nil, nil)
+}
+
+// Get Trashed Folder
+//
+// Retrieves the folder that has been moved to the trash.
+// Only the folder moved to the trash itself can be retrieved, not the folders in it.
+// https://developer.box.com/reference#get-trashed-folder
+func (f *Folder) GetTrashedInfo(folderId string, fields []string) (*Folder, error) {
+	return f.sendForTrashedFolder(f.GetTrashedInfoReq(folderId, fields), http.StatusOK)
+}
+
+// Restore Folder
+//
+// Restores the folder moved to the trash (e.g. by Delete with recursive) and all the items in it.
+// newName and newParentId are used to avoid the name conflict, or if the original parent folder was deleted.
+// "" means the original name / parent folder.
+// https://developer.box.com/reference#restore-a-trashed-folder
+func (f *Folder) RestoreReq(folderId string, newName string, newParentId string, fields []string) *Request {
+	url := fmt.Sprintf("%s%s%s", f.apiInfo.api.BaseURL, "folders/", folderId)
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		url += fmt.Sprintf("?%s", fieldsParam)
+	}
+	return f.apiInfo.newRequest(url, POST, nil, restoreBody(newName, newParentId))
+}
+
+// Restore Folder
+//
+// RestoreBatch adds RestoreReq to the batch request, and returns the handle of the folder.
+func (f *Folder) RestoreBatch(batch *BatchRequest, folderId string, newName string, newParentId string, fields []string) *PendingFolder {
+	return batch.addFolder(f.RestoreReq(folderId, newName, newParentId, fields))
+}
+
+// Restore Folder
+//
+// Restores the folder moved to the trash (e.g. by Delete with recursive) and all the items in it.
+// newName and newParentId are used to avoid the name conflict, or if the original parent folder was deleted.
+// "" means the original name / parent folder.
+// https://developer.box.com/reference#restore-a-trashed-folder
+func (f *Folder) Restore(folderId string, newName string, newParentId string, fields []string) (*Folder, error) {
+	return f.sendForTrashedFolder(f.RestoreReq(folderId, newName, newParentId, fields), http.StatusCreated)
+}
+
+func (f *Folder) sendForTrashedFolder(req *Request, expectedCode int) (*Folder, error) {
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != expectedCode {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &Folder{apiInfo: f.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Permanently Delete Folder
+//
+// Permanently deletes the folder in the trash and all the items in it. It can not be restored.
+// https://developer.box.com/reference#permanently-delete-folder
+func (f *Folder) DeletePermanentlyReq(folderId string) *Request {
+	url := fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "folders/", folderId, "/trash")
+	return f.apiInfo.newRequest(url, DELETE, nil, nil)
+}
+
+// Permanently Delete Folder
+//
+// Permanently deletes the folder in the trash and all the items in it. It can not be restored.
+// https://developer.box.com/reference#permanently-delete-folder
+func (f *Folder) DeletePermanently(folderId string) error {
+	req := f.DeletePermanentlyReq(folderId)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
+
+// Get Trashed File
+//
+// Retrieves the file that has been moved to the trash.
+// If the file is in a trashed folder, only the folder can be retrieved.
+// https://developer.box.com/reference#get-trashed-file
+func (f *File) GetTrashedInfoReq(fileId string, fields []string) *Request {
+	url := fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "files/", fileId, "/trash")
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		url += fmt.Sprintf("?%s", fieldsParam)
+	}
+	return f.apiInfo.newRequest(url, GET, nil, nil)
+}
+
+// Get Trashed File
+//
+// Retrieves the file that has been moved to the trash.
+// If the file is in a trashed folder, only the folder can be retrieved.
+// https://developer.box.com/reference#get-trashed-file
+func (f *File) GetTrashedInfo(fileId string, fields []string) (*File, error) {
+	return f.sendForTrashedFile(f.GetTrashedInfoReq(fileId, fields), http.StatusOK)
+}
+
+// Restore File
+//
+// Restores the file moved to the trash.
+// newName and newParentId are used to avoid the name conflict, or if the original parent folder was deleted.
+// "" means the original name / parent folder.
+// https://developer.box.com/reference#restore-a-trashed-item
+func (f *File) RestoreReq(fileId string, newName string, newParentId string, fields []string) *Request {
+	url := fmt.Sprintf("%s%s%s", f.apiInfo.api.BaseURL, "files/", fileId)
+	if fieldsParam := BuildFieldsQueryParams(fields); fieldsParam != "" {
+		url += fmt.Sprintf("?%s", fieldsParam)
+	}
+	return f.apiInfo.newRequest(url, POST, nil, restoreBody(newName, newParentId))
+}
+
+// Restore File
+//
+// RestoreBatch adds RestoreReq to the batch request, and returns the handle of the file.
+func (f *File) RestoreBatch(batch *BatchRequest, fileId string, newName string, newParentId string, fields []string) *PendingFile {
+	return batch.addFile(f.RestoreReq(fileId, newName, newParentId, fields))
+}
+
+// Restore File
+//
+// Restores the file moved to the trash.
+// newName and newParentId are used to avoid the name conflict, or if the original parent folder was deleted.
+// "" means the original name / parent folder.
+// https://developer.box.com/reference#restore-a-trashed-item
+func (f *File) Restore(fileId string, newName string, newParentId string, fields []string) (*File, error) {
+	return f.sendForTrashedFile(f.RestoreReq(fileId, newName, newParentId, fields), http.StatusCreated)
+}
+
+func (f *File) sendForTrashedFile(req *Request, expectedCode int) (*File, error) {
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != expectedCode {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := &File{apiInfo: f.apiInfo}
+	err = UnmarshalJSONWrapper(resp.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Permanently Delete File
+//
+// Permanently deletes the file in the trash. It can not be restored.
+// https://developer.box.com/reference#permanently-delete-file
+func (f *File) DeletePermanentlyReq(fileId string) *Request {
+	url := fmt.Sprintf("%s%s%s%s", f.apiInfo.api.BaseURL, "files/", fileId, "/trash")
+	return f.apiInfo.newRequest(url, DELETE, nil, nil)
+}
+
+// Permanently Delete File
+//
+// Permanently deletes the file in the trash. It can not be restored.
+// https://developer.box.com/reference#permanently-delete-file
+func (f *File) DeletePermanently(fileId string) error {
+	req := f.DeletePermanentlyReq(fileId)
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
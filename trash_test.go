@@ -0,0 +1,54 @@
+package goboxer
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestTrash_Reqs(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	tests := []struct {
+		name       string
+		req        *Request
+		wantMethod Method
+		wantUrl    string
+		wantBody   string
+	}{
+		{"items", NewFolder(apiConn).TrashedItemsReq(0, 100, "", "", nil),
+			GET, url + "/2.0/folders/trash/items?offset=0&limit=100", ""},
+		{"items sorted", NewFolder(apiConn).TrashedItemsReq(100, 50, "name", "DESC", []string{"name", "trashed_at"}),
+			GET, url + "/2.0/folders/trash/items?offset=100&limit=50&sort=name&direction=DESC&fields=name,trashed_at", ""},
+		{"items sorted without direction", NewFolder(apiConn).TrashedItemsReq(0, 100, "date", "", nil),
+			GET, url + "/2.0/folders/trash/items?offset=0&limit=100&sort=date", ""},
+		{"items direction only, limit clamped", NewFolder(apiConn).TrashedItemsReq(0, 5000, "", "ASC", nil),
+			GET, url + "/2.0/folders/trash/items?offset=0&limit=1000&direction=ASC", ""},
+		{"get folder", NewFolder(apiConn).GetTrashedInfoReq("20001", []string{"name"}),
+			GET, url + "/2.0/folders/20001/trash?fields=name", ""},
+		{"get file", NewFile(apiConn).GetTrashedInfoReq("10001", nil),
+			GET, url + "/2.0/files/10001/trash", ""},
+		{"restore folder", NewFolder(apiConn).RestoreReq("20001", "", "", nil),
+			POST, url + "/2.0/folders/20001", `{}`},
+		{"restore file renamed", NewFile(apiConn).RestoreReq("10001", "restored.txt", "20002", []string{"name"}),
+			POST, url + "/2.0/files/10001?fields=name", `{"name":"restored.txt","parent":{"id":"20002"}}`},
+		{"delete folder", NewFolder(apiConn).DeletePermanentlyReq("20001"),
+			DELETE, url + "/2.0/folders/20001/trash", ""},
+		{"delete file", NewFile(apiConn).DeletePermanentlyReq("10001"),
+			DELETE, url + "/2.0/files/10001/trash", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Method != tt.wantMethod || tt.req.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want %v %s", tt.req.Method, tt.req.Url, tt.wantMethod, tt.wantUrl)
+			}
+			var body []byte
+			if tt.req.body != nil {
+				body, _ = ioutil.ReadAll(tt.req.body)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -79,6 +79,15 @@ const (
 	EnterpriseTypeRolledOut  EnterpriseType = "rolledout"
 )
 
+// UserType is the filter of the enterprise user listing.
+type UserType string
+
+const (
+	UserTypeAll      UserType = "all"
+	UserTypeManaged  UserType = "managed"
+	UserTypeExternal UserType = "external"
+)
+
 type Enterprise struct {
 	Type EnterpriseType
 	Id   string
@@ -138,7 +147,7 @@ type User struct {
 	IsPasswordResetRequired       *bool               `json:"is_password_reset_required,omitempty"`
 
 	NotifyRolledOut *bool `json:"notify,omitempty"`
-	changeFlag      uint64
+	changedFlag     uint64
 }
 
 const (
@@ -165,9 +174,9 @@ func (u *User) ResourceType() BoxResourceType {
 	return UserResource
 }
 
-func NewUser(api *APIConn) *User {
+func NewUser(api Connection) *User {
 	return &User{
-		apiInfo: &apiInfo{api: api},
+		apiInfo: api.connInfo(),
 	}
 }
 
@@ -194,7 +203,7 @@ func (u *User) GetCurrentUserReq(fields []string) *Request {
 		query = fmt.Sprintf("?%s", fieldsParams)
 	}
 
-	return NewRequest(u.apiInfo.api, urlBase+query, GET, nil, nil)
+	return u.apiInfo.newRequest(urlBase+query, GET, nil, nil)
 }
 
 // Get User
@@ -210,10 +219,10 @@ func (u *User) GetCurrentUser(fields []string) (*User, error) {
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &User{apiInfo: &apiInfo{api: u.apiInfo.api}}
+	r := &User{apiInfo: u.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -234,7 +243,14 @@ func (u *User) GetUserReq(userId string, fields []string) *Request {
 		query = fmt.Sprintf("?%s", fieldsParams)
 	}
 
-	return NewRequest(u.apiInfo.api, urlBase+query, GET, nil, nil)
+	return u.apiInfo.newRequest(urlBase+query, GET, nil, nil)
+}
+
+// Get User
+//
+// GetUserBatch adds GetUserReq to the batch request, and returns the handle of the user.
+func (u *User) GetUserBatch(batch *BatchRequest, userId string, fields []string) *PendingUser {
+	return batch.addUser(u.GetUserReq(userId, fields))
 }
 
 // Get User
@@ -244,16 +260,16 @@ func (u *User) GetUserReq(userId string, fields []string) *Request {
 func (u *User) GetUser(userId string, fields []string) (*User, error) {
 
 	req := u.GetUserReq(userId, fields)
-	resp, err := req.Send()
+	resp, err := req.sendCached()
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &User{apiInfo: &apiInfo{api: u.apiInfo.api}}
+	r := &User{apiInfo: u.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -278,51 +294,58 @@ func (u *User) CreateUserReq(fields []string) *Request {
 	data := &User{}
 	data.Login = u.Login
 	data.Name = u.Name
-	if u.changeFlag&cUserRole == cUserRole {
+	if u.changedFlag&cUserRole == cUserRole {
 		data.Role = u.Role
 	}
-	if u.changeFlag&cUserLanguage == cUserLanguage {
+	if u.changedFlag&cUserLanguage == cUserLanguage {
 		data.Language = u.Language
 	}
-	if u.changeFlag&cUserIsSyncEnabled == cUserIsSyncEnabled {
+	if u.changedFlag&cUserIsSyncEnabled == cUserIsSyncEnabled {
 		data.IsSyncEnabled = u.IsSyncEnabled
 	}
-	if u.changeFlag&cUserJobTitle == cUserJobTitle {
+	if u.changedFlag&cUserJobTitle == cUserJobTitle {
 		data.JobTitle = u.JobTitle
 	}
-	if u.changeFlag&cUserPhone == cUserPhone {
+	if u.changedFlag&cUserPhone == cUserPhone {
 		data.Phone = u.Phone
 	}
-	if u.changeFlag&cUserAddress == cUserAddress {
+	if u.changedFlag&cUserAddress == cUserAddress {
 		data.Address = u.Address
 	}
-	if u.changeFlag&cUserSpaceAmount == cUserSpaceAmount {
+	if u.changedFlag&cUserSpaceAmount == cUserSpaceAmount {
 		data.SpaceAmount = u.SpaceAmount
 	}
-	if u.changeFlag&cUserTrackingCodes == cUserTrackingCodes {
+	if u.changedFlag&cUserTrackingCodes == cUserTrackingCodes {
 		data.TrackingCodes = u.TrackingCodes
 	}
-	if u.changeFlag&cUserCanSeeMangedUsers == cUserCanSeeMangedUsers {
+	if u.changedFlag&cUserCanSeeMangedUsers == cUserCanSeeMangedUsers {
 		data.CanSeeManagedUsers = u.CanSeeManagedUsers
 	}
-	if u.changeFlag&cUserTimezone == cUserTimezone {
+	if u.changedFlag&cUserTimezone == cUserTimezone {
 		data.Timezone = u.Timezone
 	}
-	if u.changeFlag&cUserIsExemptFromDeviceLimits == cUserIsExemptFromDeviceLimits {
+	if u.changedFlag&cUserIsExemptFromDeviceLimits == cUserIsExemptFromDeviceLimits {
 		data.IsExemptFromDeviceLimits = u.IsExemptFromDeviceLimits
 	}
-	if u.changeFlag&cUserIsExemptFromLoginVerification == cUserIsExemptFromLoginVerification {
+	if u.changedFlag&cUserIsExemptFromLoginVerification == cUserIsExemptFromLoginVerification {
 		data.IsExemptFromLoginVerification = u.IsExemptFromLoginVerification
 	}
-	if u.changeFlag&cUserIsExternalCollabRestricted == cUserIsExternalCollabRestricted {
+	if u.changedFlag&cUserIsExternalCollabRestricted == cUserIsExternalCollabRestricted {
 		data.IsExternalCollabRestricted = u.IsExternalCollabRestricted
 	}
-	if u.changeFlag&cUserStatus == cUserStatus {
+	if u.changedFlag&cUserStatus == cUserStatus {
 		data.Status = u.Status
 	}
 	bodyBytes, _ := json.Marshal(data)
 
-	return NewRequest(u.apiInfo.api, urlBase+query, POST, nil, bytes.NewReader(bodyBytes))
+	return u.apiInfo.newRequest(urlBase+query, POST, nil, bytes.NewReader(bodyBytes))
+}
+
+// Create User
+//
+// CreateUserBatch adds CreateUserReq to the batch request, and returns the handle of the user.
+func (u *User) CreateUserBatch(batch *BatchRequest, fields []string) *PendingUser {
+	return batch.addUser(u.CreateUserReq(fields))
 }
 
 // Create User
@@ -338,10 +361,10 @@ func (u *User) CreateUser(fields []string) (*User, error) {
 	}
 
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &User{apiInfo: &apiInfo{api: u.apiInfo.api}}
+	r := &User{apiInfo: u.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -355,89 +378,89 @@ func (u *User) SetLogin(login string) *User {
 }
 func (u *User) SetName(name string) *User {
 	u.Name = &name
-	u.changeFlag |= cUserName
+	u.changedFlag |= cUserName
 	return u
 }
 func (u *User) SetRole(role UserRole) *User {
 	u.Role = &role
-	u.changeFlag |= cUserRole
+	u.changedFlag |= cUserRole
 	return u
 }
 func (u *User) SetLanguage(language string) *User {
 	u.Language = &language
-	u.changeFlag |= cUserLanguage
+	u.changedFlag |= cUserLanguage
 	return u
 }
 func (u *User) SetIsSyncEnabled(isSyncEnabled bool) *User {
 	u.IsSyncEnabled = &isSyncEnabled
-	u.changeFlag |= cUserIsSyncEnabled
+	u.changedFlag |= cUserIsSyncEnabled
 	return u
 }
 func (u *User) SetJobTitle(jobTitle string) *User {
 	u.JobTitle = &jobTitle
-	u.changeFlag |= cUserJobTitle
+	u.changedFlag |= cUserJobTitle
 	return u
 }
 func (u *User) SetPhone(phone string) *User {
 	u.Phone = &phone
-	u.changeFlag |= cUserPhone
+	u.changedFlag |= cUserPhone
 	return u
 }
 func (u *User) SetAddress(address string) *User {
 	u.Address = &address
-	u.changeFlag |= cUserAddress
+	u.changedFlag |= cUserAddress
 	return u
 }
 func (u *User) SetSpaceAmount(spaceAmount int64) *User {
 	u.SpaceAmount = spaceAmount
-	u.changeFlag |= cUserSpaceAmount
+	u.changedFlag |= cUserSpaceAmount
 	return u
 }
 func (u *User) SetTrackingCodes(trackingCodes []map[string]string) *User {
 	u.TrackingCodes = trackingCodes
-	u.changeFlag |= cUserTrackingCodes
+	u.changedFlag |= cUserTrackingCodes
 	return u
 }
 func (u *User) SetCanSeeManagedUsers(canSeeManagedUsers bool) *User {
 	u.CanSeeManagedUsers = &canSeeManagedUsers
-	u.changeFlag |= cUserCanSeeMangedUsers
+	u.changedFlag |= cUserCanSeeMangedUsers
 	return u
 }
 func (u *User) SetTimezone(timezone string) *User {
 	u.Timezone = &timezone
-	u.changeFlag |= cUserTimezone
+	u.changedFlag |= cUserTimezone
 	return u
 }
 func (u *User) SetIsExemptFromDeviceLimits(isExemptFromDeviceLimits bool) *User {
 	u.IsExemptFromDeviceLimits = &isExemptFromDeviceLimits
-	u.changeFlag |= cUserIsExemptFromDeviceLimits
+	u.changedFlag |= cUserIsExemptFromDeviceLimits
 	return u
 }
 
 func (u *User) SetIsExemptFromLoginVerification(isExemptFromLoginVerification bool) *User {
 	u.IsExemptFromLoginVerification = &isExemptFromLoginVerification
-	u.changeFlag |= cUserIsExemptFromLoginVerification
+	u.changedFlag |= cUserIsExemptFromLoginVerification
 	return u
 }
 func (u *User) SetIsExternalCollabRestricted(isExternalCollabRestricted bool) *User {
 	u.IsExternalCollabRestricted = &isExternalCollabRestricted
-	u.changeFlag |= cUserIsExternalCollabRestricted
+	u.changedFlag |= cUserIsExternalCollabRestricted
 	return u
 }
 func (u *User) SetStatus(status UserStatus) *User {
 	u.Status = &status
-	u.changeFlag |= cUserStatus
+	u.changedFlag |= cUserStatus
 	return u
 }
 func (u *User) SetIsPasswordResetRequired(b bool) *User {
 	u.IsPasswordResetRequired = &b
-	u.changeFlag |= cUserIsPasswordResetRequired
+	u.changedFlag |= cUserIsPasswordResetRequired
 	return u
 }
 func (u *User) SetRollOutOfEnterprise(notify bool) *User {
 	u.NotifyRolledOut = &notify
 	u.Enterprise = &Enterprise{Type: EnterpriseTypeRolledOut}
-	u.changeFlag |= cUserRollOut
+	u.changedFlag |= cUserRollOut
 	return u
 }
 
@@ -455,62 +478,69 @@ func (u *User) UpdateUserReq(userId string, fields []string) *Request {
 	}
 
 	data := &User{}
-	if u.changeFlag&cUserName == cUserName {
+	if u.changedFlag&cUserName == cUserName {
 		data.Name = u.Name
 	}
-	if u.changeFlag&cUserRole == cUserRole {
+	if u.changedFlag&cUserRole == cUserRole {
 		data.Role = u.Role
 	}
-	if u.changeFlag&cUserLanguage == cUserLanguage {
+	if u.changedFlag&cUserLanguage == cUserLanguage {
 		data.Language = u.Language
 	}
-	if u.changeFlag&cUserIsSyncEnabled == cUserIsSyncEnabled {
+	if u.changedFlag&cUserIsSyncEnabled == cUserIsSyncEnabled {
 		data.IsSyncEnabled = u.IsSyncEnabled
 	}
-	if u.changeFlag&cUserJobTitle == cUserJobTitle {
+	if u.changedFlag&cUserJobTitle == cUserJobTitle {
 		data.JobTitle = u.JobTitle
 	}
-	if u.changeFlag&cUserPhone == cUserPhone {
+	if u.changedFlag&cUserPhone == cUserPhone {
 		data.Phone = u.Phone
 	}
-	if u.changeFlag&cUserAddress == cUserAddress {
+	if u.changedFlag&cUserAddress == cUserAddress {
 		data.Address = u.Address
 	}
-	if u.changeFlag&cUserSpaceAmount == cUserSpaceAmount {
+	if u.changedFlag&cUserSpaceAmount == cUserSpaceAmount {
 		data.SpaceAmount = u.SpaceAmount
 	}
-	if u.changeFlag&cUserTrackingCodes == cUserTrackingCodes {
+	if u.changedFlag&cUserTrackingCodes == cUserTrackingCodes {
 		data.TrackingCodes = u.TrackingCodes
 	}
-	if u.changeFlag&cUserCanSeeMangedUsers == cUserCanSeeMangedUsers {
+	if u.changedFlag&cUserCanSeeMangedUsers == cUserCanSeeMangedUsers {
 		data.CanSeeManagedUsers = u.CanSeeManagedUsers
 	}
-	if u.changeFlag&cUserTimezone == cUserTimezone {
+	if u.changedFlag&cUserTimezone == cUserTimezone {
 		data.Timezone = u.Timezone
 	}
-	if u.changeFlag&cUserIsExemptFromDeviceLimits == cUserIsExemptFromDeviceLimits {
+	if u.changedFlag&cUserIsExemptFromDeviceLimits == cUserIsExemptFromDeviceLimits {
 		data.IsExemptFromDeviceLimits = u.IsExemptFromDeviceLimits
 	}
-	if u.changeFlag&cUserIsExemptFromLoginVerification == cUserIsExemptFromLoginVerification {
+	if u.changedFlag&cUserIsExemptFromLoginVerification == cUserIsExemptFromLoginVerification {
 		data.IsExemptFromLoginVerification = u.IsExemptFromLoginVerification
 	}
-	if u.changeFlag&cUserIsExternalCollabRestricted == cUserIsExternalCollabRestricted {
+	if u.changedFlag&cUserIsExternalCollabRestricted == cUserIsExternalCollabRestricted {
 		data.IsExternalCollabRestricted = u.IsExternalCollabRestricted
 	}
-	if u.changeFlag&cUserStatus == cUserStatus {
+	if u.changedFlag&cUserStatus == cUserStatus {
 		data.Status = u.Status
 	}
-	if u.changeFlag&cUserIsPasswordResetRequired == cUserIsPasswordResetRequired {
+	if u.changedFlag&cUserIsPasswordResetRequired == cUserIsPasswordResetRequired {
 		data.IsPasswordResetRequired = u.IsPasswordResetRequired
 	}
-	if u.changeFlag&cUserRollOut == cUserRollOut {
+	if u.changedFlag&cUserRollOut == cUserRollOut {
 		data.Enterprise = &Enterprise{Type: EnterpriseTypeRolledOut}
 		data.NotifyRolledOut = u.NotifyRolledOut
 	}
 
 	bodyBytes, _ := json.Marshal(data)
 
-	return NewRequest(u.apiInfo.api, urlBase+query, PUT, nil, bytes.NewReader(bodyBytes))
+	return u.apiInfo.newRequest(urlBase+query, PUT, nil, bytes.NewReader(bodyBytes))
+}
+
+// Update User
+//
+// UpdateUserBatch adds UpdateUserReq to the batch request, and returns the handle of the user.
+func (u *User) UpdateUserBatch(batch *BatchRequest, userId string, fields []string) *PendingUser {
+	return batch.addUser(u.UpdateUserReq(userId, fields))
 }
 
 // Update User
@@ -526,10 +556,10 @@ func (u *User) UpdateUser(userId string, fields []string) (*User, error) {
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &User{apiInfo: &apiInfo{api: u.apiInfo.api}}
+	r := &User{apiInfo: u.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -551,34 +581,34 @@ func (u *User) CreateAppUserReq(fields []string) *Request {
 	}
 
 	data := &User{}
-	if u.changeFlag&cUserName == cUserName {
+	if u.changedFlag&cUserName == cUserName {
 		data.Name = u.Name
 	}
-	if u.changeFlag&cUserLanguage == cUserLanguage {
+	if u.changedFlag&cUserLanguage == cUserLanguage {
 		data.Language = u.Language
 	}
-	if u.changeFlag&cUserJobTitle == cUserJobTitle {
+	if u.changedFlag&cUserJobTitle == cUserJobTitle {
 		data.JobTitle = u.JobTitle
 	}
-	if u.changeFlag&cUserPhone == cUserPhone {
+	if u.changedFlag&cUserPhone == cUserPhone {
 		data.Phone = u.Phone
 	}
-	if u.changeFlag&cUserAddress == cUserAddress {
+	if u.changedFlag&cUserAddress == cUserAddress {
 		data.Address = u.Address
 	}
-	if u.changeFlag&cUserSpaceAmount == cUserSpaceAmount {
+	if u.changedFlag&cUserSpaceAmount == cUserSpaceAmount {
 		data.SpaceAmount = u.SpaceAmount
 	}
-	if u.changeFlag&cUserCanSeeMangedUsers == cUserCanSeeMangedUsers {
+	if u.changedFlag&cUserCanSeeMangedUsers == cUserCanSeeMangedUsers {
 		data.CanSeeManagedUsers = u.CanSeeManagedUsers
 	}
-	if u.changeFlag&cUserTimezone == cUserTimezone {
+	if u.changedFlag&cUserTimezone == cUserTimezone {
 		data.Timezone = u.Timezone
 	}
-	if u.changeFlag&cUserIsExternalCollabRestricted == cUserIsExternalCollabRestricted {
+	if u.changedFlag&cUserIsExternalCollabRestricted == cUserIsExternalCollabRestricted {
 		data.IsExternalCollabRestricted = u.IsExternalCollabRestricted
 	}
-	if u.changeFlag&cUserStatus == cUserStatus {
+	if u.changedFlag&cUserStatus == cUserStatus {
 		data.Status = u.Status
 	}
 
@@ -586,7 +616,7 @@ func (u *User) CreateAppUserReq(fields []string) *Request {
 	data.IsPlatformAccessOnly = &b
 	bodyBytes, _ := json.Marshal(data)
 
-	return NewRequest(u.apiInfo.api, urlBase+query, POST, nil, bytes.NewReader(bodyBytes))
+	return u.apiInfo.newRequest(urlBase+query, POST, nil, bytes.NewReader(bodyBytes))
 }
 
 // Create App User
@@ -602,10 +632,10 @@ func (u *User) CreateAppUser(fields []string) (*User, error) {
 	}
 
 	if resp.ResponseCode != http.StatusCreated {
-		return nil, newApiStatusError(resp.Body)
+		return nil, newApiStatusError(resp)
 	}
 
-	r := &User{apiInfo: &apiInfo{api: u.apiInfo.api}}
+	r := &User{apiInfo: u.apiInfo}
 	err = UnmarshalJSONWrapper(resp.Body, r)
 	if err != nil {
 		return nil, err
@@ -621,7 +651,14 @@ func (u *User) DeleteUserReq(userId string, notify bool, force bool) *Request {
 	var urlBase string
 	urlBase = fmt.Sprintf("%s%s%s?notify=%t&force=%t", u.apiInfo.api.BaseURL, "users/", userId, notify, force)
 
-	return NewRequest(u.apiInfo.api, urlBase, DELETE, nil, nil)
+	return u.apiInfo.newRequest(urlBase, DELETE, nil, nil)
+}
+
+// Delete User
+//
+// DeleteUserBatch adds DeleteUserReq to the batch request, and returns the handle of its error.
+func (u *User) DeleteUserBatch(batch *BatchRequest, userId string, notify bool, force bool) *PendingNoContent {
+	return batch.addNoContent(u.DeleteUserReq(userId, notify, force))
 }
 
 // Delete User
@@ -637,7 +674,7 @@ func (u *User) DeleteUser(userId string, notify bool, force bool) error {
 	}
 
 	if resp.ResponseCode != http.StatusNoContent {
-		return newApiStatusError(resp.Body)
+		return newApiStatusError(resp)
 	}
 
 	return nil
@@ -660,7 +697,7 @@ func (u *User) GetEnterpriseUsersReq(filterTerm string, offset int, limit int, f
 		query += fmt.Sprintf("&%s", fieldsParams)
 	}
 
-	return NewRequest(u.apiInfo.api, urlBase+query, GET, nil, nil)
+	return u.apiInfo.newRequest(urlBase+query, GET, nil, nil)
 }
 func (u *User) GetEnterpriseUsers(filterTerm string, offset int, limit int, fields []string) (outUsers []*User, outOffset int, outLimit int, outTotalCount int, err error) {
 
@@ -671,7 +708,7 @@ func (u *User) GetEnterpriseUsers(filterTerm string, offset int, limit int, fiel
 	}
 
 	if resp.ResponseCode != http.StatusOK {
-		return nil, 0, 0, 0, newApiStatusError(resp.Body)
+		return nil, 0, 0, 0, newApiStatusError(resp)
 	}
 	users := struct {
 		TotalCount int     `json:"total_count"`
@@ -684,7 +721,118 @@ func (u *User) GetEnterpriseUsers(filterTerm string, offset int, limit int, fiel
 		return nil, 0, 0, 0, err
 	}
 	for _, user := range users.Entries {
-		user.apiInfo = &apiInfo{api: u.apiInfo.api}
+		user.apiInfo = u.apiInfo
 	}
 	return users.Entries, users.Offset, users.Limit, users.TotalCount, nil
 }
+
+// Get All Enterprise Users
+//
+// GetEnterpriseUsersIter returns the iterator of all the users in the enterprise. If pageSize is 0, 100 is used.
+func (u *User) GetEnterpriseUsersIter(filterTerm string, fields []string, pageSize int) *UserIterator {
+	return &UserIterator{newOffsetIterator(pageSize, func(offset int, limit int) ([]interface{}, int, error) {
+		page, _, _, totalCount, err := u.GetEnterpriseUsers(filterTerm, offset, limit, fields)
+		return pageItems(page), totalCount, err
+	})}
+}
+
+// Get All Enterprise Users
+//
+// GetEnterpriseUsersAll returns all the users in the enterprise.
+func (u *User) GetEnterpriseUsersAll(filterTerm string, fields []string) ([]*User, error) {
+	var r []*User
+	err := u.GetEnterpriseUsersIter(filterTerm, fields, 1000).allInto(&r)
+	return r, err
+}
+
+// Get Enterprise Users (marker-based paging)
+//
+// Returns the users in the enterprise, paginated by marker.
+// Marker-based paging is recommended for large enterprises.
+// https://developer.box.com/reference#get-all-users-in-an-enterprise
+//
+//	userType: UserTypeAll, UserTypeManaged or UserTypeExternal ("" means default)
+//	externalAppUserId: returns only the app user with the external app user id ("" means no filter)
+//	marker: "" for the first page, the returned nextMarker for the following pages
+func (u *User) GetEnterpriseUsersMarkerReq(filterTerm string, userType UserType, externalAppUserId string, marker string, limit int, fields []string) *Request {
+	var urlBase string
+	var query string
+
+	urlBase = fmt.Sprintf("%s%s", u.apiInfo.api.BaseURL, "users")
+
+	if limit > 1000 {
+		limit = 1000
+	}
+	query += fmt.Sprintf("?usemarker=true&limit=%d", limit)
+	if marker != "" {
+		query += fmt.Sprintf("&marker=%s", url.QueryEscape(marker))
+	}
+	if filterTerm != "" {
+		query += fmt.Sprintf("&filter_term=%s", url.QueryEscape(filterTerm))
+	}
+	if userType != "" {
+		query += fmt.Sprintf("&user_type=%s", userType)
+	}
+	if externalAppUserId != "" {
+		query += fmt.Sprintf("&external_app_user_id=%s", url.QueryEscape(externalAppUserId))
+	}
+	if fieldsParams := BuildFieldsQueryParams(fields); fieldsParams != "" {
+		query += fmt.Sprintf("&%s", fieldsParams)
+	}
+
+	return u.apiInfo.newRequest(urlBase+query, GET, nil, nil)
+}
+
+// Get Enterprise Users (marker-based paging)
+//
+// Returns the users in the enterprise, paginated by marker.
+// nextMarker is empty if there are no more users.
+// https://developer.box.com/reference#get-all-users-in-an-enterprise
+//
+//	userType: UserTypeAll, UserTypeManaged or UserTypeExternal ("" means default)
+//	externalAppUserId: returns only the app user with the external app user id ("" means no filter)
+//	marker: "" for the first page, the returned nextMarker for the following pages
+func (u *User) GetEnterpriseUsersMarker(filterTerm string, userType UserType, externalAppUserId string, marker string, limit int, fields []string) (outUsers []*User, nextMarker string, err error) {
+
+	req := u.GetEnterpriseUsersMarkerReq(filterTerm, userType, externalAppUserId, marker, limit, fields)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.ResponseCode != http.StatusOK {
+		return nil, "", newApiStatusError(resp)
+	}
+	users := struct {
+		NextMarker string  `json:"next_marker,omitempty"`
+		Entries    []*User `json:"entries"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &users)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, user := range users.Entries {
+		user.apiInfo = u.apiInfo
+	}
+	return users.Entries, users.NextMarker, nil
+}
+
+// Get All Enterprise Users (marker-based paging)
+//
+// GetEnterpriseUsersMarkerIter returns the iterator of all the users in the enterprise, paginated by marker.
+// If pageSize is 0, 100 is used.
+func (u *User) GetEnterpriseUsersMarkerIter(filterTerm string, userType UserType, externalAppUserId string, fields []string, pageSize int) *UserIterator {
+	return &UserIterator{newMarkerIterator(pageSize, func(marker string, limit int) ([]interface{}, string, error) {
+		page, nextMarker, err := u.GetEnterpriseUsersMarker(filterTerm, userType, externalAppUserId, marker, limit, fields)
+		return pageItems(page), nextMarker, err
+	})}
+}
+
+// Get All Enterprise Users (marker-based paging)
+//
+// GetEnterpriseUsersMarkerAll returns all the users in the enterprise, paginated by marker.
+func (u *User) GetEnterpriseUsersMarkerAll(filterTerm string, userType UserType, externalAppUserId string, fields []string) ([]*User, error) {
+	var r []*User
+	err := u.GetEnterpriseUsersMarkerIter(filterTerm, userType, externalAppUserId, fields, 1000).allInto(&r)
+	return r, err
+}
@@ -2812,6 +2812,59 @@ func TestUser_GetEnterpriseUsersReq(t *testing.T) {
 	}
 }
 
+func TestUser_GetEnterpriseUsersMarkerReq(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	type args struct {
+		filterTerm        string
+		userType          UserType
+		externalAppUserId string
+		marker            string
+		limit             int
+		fields            []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"first page",
+			args{"", "", "", "", 1001, nil},
+			url + "/2.0/users?usemarker=true&limit=1000",
+		},
+		{"next page",
+			args{"", "", "", "JV9IRGZmieiBasejOG9yDCRNgd2ymoZIbjsxbJMjIs3kioVii", 100, nil},
+			url + "/2.0/users?usemarker=true&limit=100&marker=JV9IRGZmieiBasejOG9yDCRNgd2ymoZIbjsxbJMjIs3kioVii",
+		},
+		{"filters",
+			args{"あい", UserTypeManaged, "my id/1", "", 100, []string{"type", "id"}},
+			url + "/2.0/users?usemarker=true&limit=100&filter_term=%E3%81%82%E3%81%84&user_type=managed&external_app_user_id=my+id%2F1&fields=type,id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUser(apiConn)
+			got := u.GetEnterpriseUsersMarkerReq(tt.args.filterTerm, tt.args.userType, tt.args.externalAppUserId, tt.args.marker, tt.args.limit, tt.args.fields)
+
+			want := &Request{
+				apiConn:            apiConn,
+				Url:                tt.want,
+				Method:             GET,
+				shouldAuthenticate: true,
+				numRedirects:       defaultNumRedirects,
+				headers:            http.Header{},
+				body:               nil,
+			}
+			opts := diffCompOptions(*got)
+			opts = append(opts, cmpopts.IgnoreUnexported(Request{}))
+			if diff := cmp.Diff(got, want, opts...); diff != "" {
+				t.Errorf("diff:  (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestUser_GetEnterpriseUsers(t *testing.T) {
 	// test server (dummy box api)
 	ts := httptest.NewServer(http.HandlerFunc(
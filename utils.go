@@ -11,8 +11,9 @@ import (
 )
 
 const (
-	ContentTypeApplicationJson = "application/json"
-	ContentTypeFormUrlEncoded  = "application/x-www-form-urlencoded"
+	ContentTypeApplicationJson      = "application/json"
+	ContentTypeFormUrlEncoded       = "application/x-www-form-urlencoded"
+	ContentTypeApplicationJsonPatch = "application/json-patch+json"
 )
 
 func BuildFieldsQueryParams(fields []string) string {
@@ -152,6 +153,18 @@ func ParseResource(jsonEntity []byte) (r BoxResource, err error) {
 			c := &Collaboration{}
 			err = dec.Decode(c)
 			r = c
+		case "comment":
+			c := &Comment{}
+			err = dec.Decode(c)
+			r = c
+		case "task":
+			t := &Task{}
+			err = dec.Decode(t)
+			r = t
+		case "task_assignment":
+			ta := &TaskAssignment{}
+			err = dec.Decode(ta)
+			r = ta
 		}
 	}
 	if r == nil {
@@ -183,6 +196,16 @@ func setApiInfo(r BoxResource, info *apiInfo) {
 	case CollaborationResource:
 		s := r.(*Collaboration)
 		s.apiInfo = info
+	case CommentResource:
+		s := r.(*Comment)
+		s.apiInfo = info
+	case TaskResource:
+		s := r.(*Task)
+		s.apiInfo = info
+		s.setApiInfoToAssignments()
+	case TaskAssignmentResource:
+		s := r.(*TaskAssignment)
+		s.apiInfo = info
 	default:
 		// nothing to do
 	}
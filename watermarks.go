@@ -0,0 +1,163 @@
+package goboxer
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Watermark is the watermark applied to a file or a folder.
+type Watermark struct {
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	ModifiedAt *time.Time `json:"modified_at,omitempty"`
+}
+
+func (w *Watermark) String() string {
+	if w == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("{ CreatedAt:%s, ModifiedAt:%s }", w.CreatedAt, w.ModifiedAt)
+}
+
+// watermarkBody is the body to apply the default watermark.
+const watermarkBody = `{"watermark":{"imprint":"default"}}`
+
+func watermarkReq(info *apiInfo, itemPath string, id string, method Method) *Request {
+	url := fmt.Sprintf("%s%s%s%s", info.api.BaseURL, itemPath, id, "/watermark")
+	if method == PUT {
+		return info.newRequest(url, method, nil, bytes.NewReader([]byte(watermarkBody)))
+	}
+	return info.newRequest(url, method, nil, nil)
+}
+
+// sendForWatermark sends the request and decodes the watermark in the response.
+// Apply returns 201 if the watermark is newly applied, 200 if updated.
+func sendForWatermark(req *Request) (*Watermark, error) {
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ResponseCode != http.StatusOK && resp.ResponseCode != http.StatusCreated {
+		return nil, newApiStatusError(resp)
+	}
+
+	r := struct {
+		Watermark *Watermark `json:"watermark"`
+	}{}
+	err = UnmarshalJSONWrapper(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r.Watermark, nil
+}
+
+// sendForWatermarkRemoval sends the request to remove the watermark.
+func sendForWatermarkRemoval(req *Request) error {
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp.ResponseCode != http.StatusNoContent {
+		return newApiStatusError(resp)
+	}
+	return nil
+}
+
+// Get Watermark on File
+//
+// Retrieve the watermark for a file. Returns ErrNotFound if the file is not watermarked.
+// https://developer.box.com/reference#get-watermark-on-file
+func (f *File) GetWatermarkReq(fileId string) *Request {
+	return watermarkReq(f.apiInfo, "files/", fileId, GET)
+}
+
+// Get Watermark on File
+//
+// Retrieve the watermark for a file. Returns ErrNotFound if the file is not watermarked.
+// https://developer.box.com/reference#get-watermark-on-file
+func (f *File) GetWatermark(fileId string) (*Watermark, error) {
+	return sendForWatermark(f.GetWatermarkReq(fileId))
+}
+
+// Apply Watermark on File
+//
+// Apply or update the watermark for a file.
+// https://developer.box.com/reference#apply-watermark-on-file
+func (f *File) ApplyWatermarkReq(fileId string) *Request {
+	return watermarkReq(f.apiInfo, "files/", fileId, PUT)
+}
+
+// Apply Watermark on File
+//
+// Apply or update the watermark for a file.
+// https://developer.box.com/reference#apply-watermark-on-file
+func (f *File) ApplyWatermark(fileId string) (*Watermark, error) {
+	return sendForWatermark(f.ApplyWatermarkReq(fileId))
+}
+
+// Remove Watermark on File
+//
+// Removes the watermark from a file. Returns ErrNotFound if the file is not watermarked.
+// https://developer.box.com/reference#remove-watermark-on-file
+func (f *File) RemoveWatermarkReq(fileId string) *Request {
+	return watermarkReq(f.apiInfo, "files/", fileId, DELETE)
+}
+
+// Remove Watermark on File
+//
+// Removes the watermark from a file. Returns ErrNotFound if the file is not watermarked.
+// https://developer.box.com/reference#remove-watermark-on-file
+func (f *File) RemoveWatermark(fileId string) error {
+	return sendForWatermarkRemoval(f.RemoveWatermarkReq(fileId))
+}
+
+// Get Watermark on Folder
+//
+// Retrieve the watermark for a folder. Returns ErrNotFound if the folder is not watermarked.
+// https://developer.box.com/reference#get-watermark-for-a-folder
+func (f *Folder) GetWatermarkReq(folderId string) *Request {
+	return watermarkReq(f.apiInfo, "folders/", folderId, GET)
+}
+
+// Get Watermark on Folder
+//
+// Retrieve the watermark for a folder. Returns ErrNotFound if the folder is not watermarked.
+// https://developer.box.com/reference#get-watermark-for-a-folder
+func (f *Folder) GetWatermark(folderId string) (*Watermark, error) {
+	return sendForWatermark(f.GetWatermarkReq(folderId))
+}
+
+// Apply Watermark on Folder
+//
+// Apply or update the watermark for a folder. The watermark applies to all the items in the folder.
+// https://developer.box.com/reference#apply-watermark-to-folder
+func (f *Folder) ApplyWatermarkReq(folderId string) *Request {
+	return watermarkReq(f.apiInfo, "folders/", folderId, PUT)
+}
+
+// Apply Watermark on Folder
+//
+// Apply or update the watermark for a folder. The watermark applies to all the items in the folder.
+// https://developer.box.com/reference#apply-watermark-to-folder
+func (f *Folder) ApplyWatermark(folderId string) (*Watermark, error) {
+	return sendForWatermark(f.ApplyWatermarkReq(folderId))
+}
+
+// Remove Watermark on Folder
+//
+// Removes the watermark from a folder. Returns ErrNotFound if the folder is not watermarked.
+// https://developer.box.com/reference#remove-watermark-from-folder
+func (f *Folder) RemoveWatermarkReq(folderId string) *Request {
+	return watermarkReq(f.apiInfo, "folders/", folderId, DELETE)
+}
+
+// Remove Watermark on Folder
+//
+// Removes the watermark from a folder. Returns ErrNotFound if the folder is not watermarked.
+// https://developer.box.com/reference#remove-watermark-from-folder
+func (f *Folder) RemoveWatermark(folderId string) error {
+	return sendForWatermarkRemoval(f.RemoveWatermarkReq(folderId))
+}
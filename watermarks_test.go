@@ -0,0 +1,79 @@
+package goboxer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestWatermark_Reqs(t *testing.T) {
+	url := "https://example.com"
+	apiConn := commonInit(url)
+
+	tests := []struct {
+		name       string
+		req        *Request
+		wantMethod Method
+		wantUrl    string
+		wantBody   string
+	}{
+		{"get file", NewFile(apiConn).GetWatermarkReq("10001"), GET, url + "/2.0/files/10001/watermark", ""},
+		{"apply file", NewFile(apiConn).ApplyWatermarkReq("10001"), PUT, url + "/2.0/files/10001/watermark", `{"watermark":{"imprint":"default"}}`},
+		{"remove file", NewFile(apiConn).RemoveWatermarkReq("10001"), DELETE, url + "/2.0/files/10001/watermark", ""},
+		{"get folder", NewFolder(apiConn).GetWatermarkReq("20001"), GET, url + "/2.0/folders/20001/watermark", ""},
+		{"apply folder", NewFolder(apiConn).ApplyWatermarkReq("20001"), PUT, url + "/2.0/folders/20001/watermark", `{"watermark":{"imprint":"default"}}`},
+		{"remove folder", NewFolder(apiConn).RemoveWatermarkReq("20001"), DELETE, url + "/2.0/folders/20001/watermark", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Method != tt.wantMethod || tt.req.Url != tt.wantUrl {
+				t.Errorf("got %v %s, want %v %s", tt.req.Method, tt.req.Url, tt.wantMethod, tt.wantUrl)
+			}
+			var body []byte
+			if tt.req.body != nil {
+				body, _ = ioutil.ReadAll(tt.req.body)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestWatermark_Responses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpHeaderContentType, ContentTypeApplicationJson)
+		switch {
+		case r.URL.Path == "/2.0/files/10001/watermark" && r.Method == http.MethodPut:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"watermark":{"created_at":"2020-04-01T10:00:00-07:00","modified_at":"2020-04-01T10:00:00-07:00"}}`))
+		case r.URL.Path == "/2.0/folders/20001/watermark" && r.Method == http.MethodPut:
+			_, _ = w.Write([]byte(`{"watermark":{"created_at":"2020-04-01T10:00:00-07:00","modified_at":"2020-04-02T10:00:00-07:00"}}`))
+		case r.URL.Path == "/2.0/folders/20001/watermark" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"error","status":404,"code":"not_found"}`))
+		}
+	}))
+	defer ts.Close()
+	apiConn := commonInit(ts.URL)
+
+	created, err := NewFile(apiConn).ApplyWatermark("10001")
+	if err != nil || created.CreatedAt == nil || !created.CreatedAt.Equal(*created.ModifiedAt) {
+		t.Errorf("File.ApplyWatermark() = %v, %v", created, err)
+	}
+	updated, err := NewFolder(apiConn).ApplyWatermark("20001")
+	if err != nil || !updated.ModifiedAt.After(*updated.CreatedAt) {
+		t.Errorf("Folder.ApplyWatermark() = %v, %v", updated, err)
+	}
+	if err := NewFolder(apiConn).RemoveWatermark("20001"); err != nil {
+		t.Errorf("Folder.RemoveWatermark() = %v", err)
+	}
+	if _, err := NewFile(apiConn).GetWatermark("10001"); !xerrors.Is(err, ErrNotFound) {
+		t.Errorf("File.GetWatermark() error = %v, want ErrNotFound", err)
+	}
+}